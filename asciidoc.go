@@ -0,0 +1,442 @@
+package goeditorjs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// AsciiDocEngine is the engine that creates AsciiDoc from EditorJS blocks, e.g. for Asciidoctor
+// Handlers can be registered while the engine is generating.
+type AsciiDocEngine struct {
+	BlockHandlers map[string]AsciiDocBlockHandler
+	TuneHandlers  map[string]AsciiDocTuneHandler
+	// Registry, if set, provides the handlers for block types that aren't in BlockHandlers
+	Registry *Registry
+	// UnknownBlockPolicy decides what happens to blocks without a registered handler. Defaults to UnknownBlockFail.
+	UnknownBlockPolicy UnknownBlockPolicy
+	// FallbackHandler generates AsciiDoc for blocks without a registered handler when using UnknownBlockFallback
+	FallbackHandler AsciiDocBlockHandler
+	// OnUnknownBlock, if set, is called with every block that doesn't have a registered handler
+	OnUnknownBlock func(editorJSBlock EditorJSBlock)
+	// ContinueOnError generates every block that can be generated and returns BlockErrors for the blocks that can't.
+	// Otherwise generation stops at the first block that fails with a *BlockError.
+	ContinueOnError bool
+
+	mu sync.RWMutex
+}
+
+// AsciiDocBlockHandler is an interface for a plugable EditorJS AsciiDoc generator
+type AsciiDocBlockHandler interface {
+	Type() string // Type returns the type the block handler supports as a string
+	GenerateAsciiDoc(editorJSBlock EditorJSBlock) (string, error)
+}
+
+// ContextAsciiDocBlockHandler is an interface for a plugable EditorJS AsciiDoc generator that is given the context of the generation.
+// The engine calls GenerateAsciiDocContext instead of GenerateAsciiDoc for handlers implementing it.
+type ContextAsciiDocBlockHandler interface {
+	AsciiDocBlockHandler
+	GenerateAsciiDocContext(ctx context.Context, editorJSBlock EditorJSBlock) (string, error)
+}
+
+// AsciiDocTuneHandler is an interface for a plugable EditorJS block tune AsciiDoc generator.
+// It's given the AsciiDoc generated for a block that has the tune and returns the tuned AsciiDoc, or ErrSkipBlock to suppress the block.
+type AsciiDocTuneHandler interface {
+	TuneHandler
+	TuneAsciiDoc(tuneData json.RawMessage, editorJSBlock EditorJSBlock, asciiDoc string) (string, error)
+}
+
+// NewAsciiDocEngine creates a new AsciiDocEngine
+func NewAsciiDocEngine() *AsciiDocEngine {
+	bhs := make(map[string]AsciiDocBlockHandler)
+	ths := make(map[string]AsciiDocTuneHandler)
+	return &AsciiDocEngine{BlockHandlers: bhs, TuneHandlers: ths}
+}
+
+// NewAsciiDocEngineFromRegistry creates a new AsciiDocEngine that uses the handlers of the registry
+func NewAsciiDocEngineFromRegistry(registry *Registry) *AsciiDocEngine {
+	asciiDocEngine := NewAsciiDocEngine()
+	asciiDocEngine.Registry = registry
+	return asciiDocEngine
+}
+
+// NewDefaultAsciiDocEngine creates a new AsciiDocEngine with all the built-in block handlers and tune handlers registered
+func NewDefaultAsciiDocEngine() *AsciiDocEngine {
+	asciiDocEngine := NewAsciiDocEngineFromRegistry(NewDefaultRegistry())
+	asciiDocEngine.RegisterTuneHandlers(&HiddenTuneHandler{})
+	return asciiDocEngine
+}
+
+// RegisterBlockHandlers registers or overrides a block handlers for blockType given by AsciiDocBlockHandler.Type()
+func (asciiDocEngine *AsciiDocEngine) RegisterBlockHandlers(handlers ...AsciiDocBlockHandler) {
+	asciiDocEngine.mu.Lock()
+	defer asciiDocEngine.mu.Unlock()
+	if asciiDocEngine.BlockHandlers == nil {
+		asciiDocEngine.BlockHandlers = make(map[string]AsciiDocBlockHandler)
+	}
+	for _, bh := range handlers {
+		asciiDocEngine.BlockHandlers[bh.Type()] = bh
+	}
+}
+
+// RegisterTuneHandlers registers or overrides tune handlers for the tune given by AsciiDocTuneHandler.Type()
+func (asciiDocEngine *AsciiDocEngine) RegisterTuneHandlers(handlers ...AsciiDocTuneHandler) {
+	asciiDocEngine.mu.Lock()
+	defer asciiDocEngine.mu.Unlock()
+	if asciiDocEngine.TuneHandlers == nil {
+		asciiDocEngine.TuneHandlers = make(map[string]AsciiDocTuneHandler)
+	}
+	for _, th := range handlers {
+		asciiDocEngine.TuneHandlers[th.Type()] = th
+	}
+}
+
+// GenerateAsciiDoc generates AsciiDoc from the editorJS using configured set of AsciiDoc handlers
+func (asciiDocEngine *AsciiDocEngine) GenerateAsciiDoc(editorJSData string) (string, error) {
+	document, err := ParseDocument(editorJSData)
+	if err != nil {
+		return "", err
+	}
+	return asciiDocEngine.GenerateAsciiDocFromDocument(document)
+}
+
+// GenerateAsciiDocContext generates AsciiDoc from the editorJS using configured set of AsciiDoc handlers.
+// The context is passed to handlers implementing ContextAsciiDocBlockHandler, and generation stops when the context is done.
+func (asciiDocEngine *AsciiDocEngine) GenerateAsciiDocContext(ctx context.Context, editorJSData string) (string, error) {
+	document, err := ParseDocument(editorJSData)
+	if err != nil {
+		return "", err
+	}
+	return asciiDocEngine.GenerateAsciiDocFromDocumentContext(ctx, document)
+}
+
+// GenerateAsciiDocFromDocument generates AsciiDoc from a parsed Document using configured set of AsciiDoc handlers
+func (asciiDocEngine *AsciiDocEngine) GenerateAsciiDocFromDocument(document *Document) (string, error) {
+	result := &strings.Builder{}
+	err := asciiDocEngine.RenderAsciiDocFromDocument(result, document)
+	return result.String(), err
+}
+
+// GenerateAsciiDocFromDocumentContext generates AsciiDoc from a parsed Document using configured set of AsciiDoc handlers.
+// The context is passed to handlers implementing ContextAsciiDocBlockHandler, and generation stops when the context is done.
+func (asciiDocEngine *AsciiDocEngine) GenerateAsciiDocFromDocumentContext(ctx context.Context, document *Document) (string, error) {
+	result := &strings.Builder{}
+	err := asciiDocEngine.renderDocument(ctx, result, document)
+	return result.String(), err
+}
+
+// RenderAsciiDoc reads editorJS data from r and writes the AsciiDoc to w using configured set of AsciiDoc handlers
+func (asciiDocEngine *AsciiDocEngine) RenderAsciiDoc(w io.Writer, r io.Reader) error {
+	document, err := decodeDocument(r)
+	if err != nil {
+		return err
+	}
+	return asciiDocEngine.RenderAsciiDocFromDocument(w, document)
+}
+
+// RenderAsciiDocContext reads editorJS data from r and writes the AsciiDoc to w using configured set of AsciiDoc handlers.
+// The context is passed to handlers implementing ContextAsciiDocBlockHandler, and rendering stops when the context is done.
+func (asciiDocEngine *AsciiDocEngine) RenderAsciiDocContext(ctx context.Context, w io.Writer, r io.Reader) error {
+	document, err := decodeDocument(r)
+	if err != nil {
+		return err
+	}
+	return asciiDocEngine.RenderAsciiDocFromDocumentContext(ctx, w, document)
+}
+
+// RenderAsciiDocFromDocument writes the AsciiDoc for a parsed Document to w using configured set of AsciiDoc handlers
+func (asciiDocEngine *AsciiDocEngine) RenderAsciiDocFromDocument(w io.Writer, document *Document) error {
+	return asciiDocEngine.renderDocument(context.Background(), w, document)
+}
+
+// RenderAsciiDocFromDocumentContext writes the AsciiDoc for a parsed Document to w using configured set of AsciiDoc handlers.
+// The context is passed to handlers implementing ContextAsciiDocBlockHandler, and rendering stops when the context is done.
+func (asciiDocEngine *AsciiDocEngine) RenderAsciiDocFromDocumentContext(ctx context.Context, w io.Writer, document *Document) error {
+	return asciiDocEngine.renderDocument(ctx, w, document)
+}
+
+// renderDocument writes the AsciiDoc for a parsed Document to w
+func (asciiDocEngine *AsciiDocEngine) renderDocument(ctx context.Context, w io.Writer, document *Document) error {
+	return writeBlocks(ctx, w, document.Blocks, "\n\n", asciiDocEngine.ContinueOnError, asciiDocEngine.writeBlock)
+}
+
+// writeBlock writes the AsciiDoc for a single block to buf
+func (asciiDocEngine *AsciiDocEngine) writeBlock(ctx context.Context, buf *bytes.Buffer, block EditorJSBlock) (bool, error) {
+	generator, ok := asciiDocEngine.blockHandler(block.Type)
+	if !ok {
+		switch unknownBlock(asciiDocEngine.UnknownBlockPolicy, asciiDocEngine.FallbackHandler != nil, asciiDocEngine.OnUnknownBlock, block) {
+		case UnknownBlockSkip:
+			return false, nil
+		case UnknownBlockPlaceholder:
+			buf.WriteString(asciiDocUnknownBlockPlaceholder(block))
+			return true, nil
+		case UnknownBlockFallback:
+			generator = asciiDocEngine.FallbackHandler
+		default:
+			return false, errBlockHandlerNotFound(block)
+		}
+	}
+
+	var asciiDoc string
+	var err error
+	if contextGenerator, ok := generator.(ContextAsciiDocBlockHandler); ok {
+		asciiDoc, err = contextGenerator.GenerateAsciiDocContext(ctx, block)
+	} else {
+		asciiDoc, err = generator.GenerateAsciiDoc(block)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if len(block.Tunes) > 0 {
+		asciiDoc, err = asciiDocEngine.applyTunes(block, asciiDoc)
+		if errors.Is(err, ErrSkipBlock) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+	}
+
+	buf.WriteString(asciiDoc)
+	return true, nil
+}
+
+// blockHandler returns the handler for the block type from BlockHandlers, or from the Registry if it has one the engine can use
+func (asciiDocEngine *AsciiDocEngine) blockHandler(blockType string) (AsciiDocBlockHandler, bool) {
+	asciiDocEngine.mu.RLock()
+	generator, ok := asciiDocEngine.BlockHandlers[blockType]
+	registry := asciiDocEngine.Registry
+	asciiDocEngine.mu.RUnlock()
+	if ok || registry == nil {
+		return generator, ok
+	}
+
+	if h, ok := registry.Lookup(blockType); ok {
+		generator, ok = h.(AsciiDocBlockHandler)
+		return generator, ok
+	}
+	return nil, false
+}
+
+// tuneHandler returns the registered tune handler for the tune
+func (asciiDocEngine *AsciiDocEngine) tuneHandler(name string) (AsciiDocTuneHandler, bool) {
+	asciiDocEngine.mu.RLock()
+	defer asciiDocEngine.mu.RUnlock()
+	tuneHandler, ok := asciiDocEngine.TuneHandlers[name]
+	return tuneHandler, ok
+}
+
+// applyTunes applies the registered tune handlers for the tunes of the block, in order of tune name
+func (asciiDocEngine *AsciiDocEngine) applyTunes(block EditorJSBlock, asciiDoc string) (string, error) {
+	for _, name := range tuneNames(block) {
+		if tuneHandler, ok := asciiDocEngine.tuneHandler(name); ok {
+			var err error
+			asciiDoc, err = tuneHandler.TuneAsciiDoc(block.Tunes[name], block, asciiDoc)
+			if err != nil {
+				return "", err
+			}
+		}
+	}
+	return asciiDoc, nil
+}
+
+// asciiDocUnknownBlockPlaceholder returns the AsciiDoc comment used by UnknownBlockPlaceholder
+func asciiDocUnknownBlockPlaceholder(editorJSBlock EditorJSBlock) string {
+	// A line comment ends at the end of the line
+	blockType := strings.NewReplacer("\r", " ", "\n", " ").Replace(editorJSBlock.Type)
+	return fmt.Sprintf(`// goeditorjs: no handler for block type "%s"`, blockType)
+}
+
+// asciiDocDelimiter returns a delimiter line of at least four characters c for a delimited block with the content,
+// which is longer than any line of the content that would end the block early
+func asciiDocDelimiter(content string, c string) string {
+	length := 4
+	for _, line := range strings.Split(content, "\n") {
+		if len(line) >= length && strings.Trim(line, c) == "" {
+			length = len(line) + 1
+		}
+	}
+	return strings.Repeat(c, length)
+}
+
+// asciiDocText formats the text of a block as a single line
+func asciiDocText(htmlData string) string {
+	return strings.ReplaceAll(InlineHTMLToAsciiDoc(htmlData), " +\n", " ")
+}
+
+// inlineAsciiDocMarks are the AsciiDoc formatting marks put around the content of the supported inline elements.
+// Unconstrained marks are used, so they also format parts of words.
+var inlineAsciiDocMarks = map[string][2]string{
+	"b": {"**", "**"}, "strong": {"**", "**"},
+	"i": {"__", "__"}, "em": {"__", "__"},
+	"code": {"``", "``"},
+	"mark": {"##", "##"},
+	"u":    {"[.underline]##", "##"},
+	"s":    {"[.line-through]##", "##"}, "del": {"[.line-through]##", "##"}, "strike": {"[.line-through]##", "##"},
+}
+
+// inlineAsciiDocFrame is an element being converted, its content is collected until the element is closed
+type inlineAsciiDocFrame struct {
+	tag  string
+	href string
+	// inLink is set for the frames inside of a link macro
+	inLink  bool
+	content strings.Builder
+}
+
+// InlineHTMLToAsciiDoc converts editor.js inline html to AsciiDoc.
+// Bold, italic, underline, strikethrough, links, inline code and marked text are converted, line breaks become hard line breaks
+// and other tags are removed, keeping their content. Entities are decoded and text that has a meaning in AsciiDoc is passed through as is.
+func InlineHTMLToAsciiDoc(htmlData string) string {
+	root := &inlineAsciiDocFrame{}
+	stack := []*inlineAsciiDocFrame{root}
+	atLineStart := true
+	dropped, droppedDepth := "", 0
+	for _, token := range tokenizeHTML(htmlData) {
+		current := stack[len(stack)-1]
+		if droppedDepth > 0 {
+			if token.Data == dropped && token.Type == htmlStartTagToken && !token.SelfClosing {
+				droppedDepth++
+			} else if token.Data == dropped && token.Type == htmlEndTagToken {
+				droppedDepth--
+			}
+			continue
+		}
+
+		switch token.Type {
+		case htmlTextToken:
+			text := htmlWhitespace.ReplaceAllString(html.UnescapeString(token.Data), " ")
+			if atLineStart {
+				text = strings.TrimLeft(text, " ")
+			}
+			if current.inLink {
+				current.content.WriteString(escapeAsciiDocLinkText(text))
+			} else {
+				current.content.WriteString(escapeAsciiDocText(text, atLineStart && len(stack) == 1))
+			}
+			if text != "" {
+				atLineStart = false
+			}
+		case htmlStartTagToken:
+			switch {
+			case token.Data == "br":
+				current.content.WriteString(" +\n")
+				atLineStart = true
+			case token.Data == "a" || inlineAsciiDocMarks[token.Data][0] != "":
+				if token.SelfClosing {
+					continue
+				}
+				frame := &inlineAsciiDocFrame{tag: token.Data, inLink: current.inLink}
+				if token.Data == "a" {
+					href, _ := token.attribute("href")
+					frame.href = inlineURLPolicy.SanitizeURL(href)
+					frame.inLink = frame.inLink || frame.href != ""
+				}
+				stack = append(stack, frame)
+			case sanitizeDroppedElements[token.Data] && !token.SelfClosing && !htmlVoidElements[token.Data]:
+				dropped, droppedDepth = token.Data, 1
+			}
+		case htmlEndTagToken:
+			i := len(stack) - 1
+			for i > 0 && stack[i].tag != token.Data {
+				i--
+			}
+			for i > 0 && len(stack) > i {
+				stack = closeInlineAsciiDocFrame(stack)
+			}
+		}
+	}
+
+	for len(stack) > 1 {
+		stack = closeInlineAsciiDocFrame(stack)
+	}
+
+	asciiDoc := root.content.String()
+	for strings.HasSuffix(asciiDoc, " +\n") {
+		asciiDoc = strings.TrimSuffix(asciiDoc, " +\n")
+	}
+	return asciiDoc
+}
+
+// closeInlineAsciiDocFrame writes the AsciiDoc of the innermost frame to its parent and removes it from the stack
+func closeInlineAsciiDocFrame(stack []*inlineAsciiDocFrame) []*inlineAsciiDocFrame {
+	frame, parent := stack[len(stack)-1], stack[len(stack)-2]
+	content := frame.content.String()
+
+	// Whitespace is moved outside of the marks and macros
+	trimmed := strings.TrimLeftFunc(content, unicode.IsSpace)
+	leading := content[:len(content)-len(trimmed)]
+	core := strings.TrimRightFunc(trimmed, unicode.IsSpace)
+	trailing := trimmed[len(core):]
+
+	switch {
+	case core == "":
+		parent.content.WriteString(content)
+	case frame.tag == "a":
+		if frame.href == "" {
+			parent.content.WriteString(content)
+		} else {
+			parent.content.WriteString(fmt.Sprintf("%slink:%s[%s]%s", leading, asciiDocURL(frame.href), core, trailing))
+		}
+	default:
+		marks := inlineAsciiDocMarks[frame.tag]
+		parent.content.WriteString(leading + marks[0] + core + marks[1] + trailing)
+	}
+
+	return stack[:len(stack)-1]
+}
+
+// asciiDocURL returns the url as the target of an AsciiDoc macro, which ends at whitespace or a bracket
+func asciiDocURL(url string) string {
+	return strings.NewReplacer(" ", "%20", "\t", "%09", "[", "%5B", "]", "%5D").Replace(url)
+}
+
+// asciiDocSpecialCharacters start inline formatting, macros, attribute references or escapes in AsciiDoc
+const asciiDocSpecialCharacters = "*_`#^~+[]{}<\\"
+
+// asciiDocLineStart matches the start of a line that would be a block marker, like a section title, list marker,
+// block title, comment or admonition label
+var asciiDocLineStart = regexp.MustCompile(`^(?:[.=/|'>:-]|\d+[.)] |(?:NOTE|TIP|IMPORTANT|WARNING|CAUTION): )`)
+
+// escapeAsciiDocText passes text that has a meaning in AsciiDoc through as is.
+// Lines that would start a block are escaped if atLineStart is set.
+func escapeAsciiDocText(text string, atLineStart bool) string {
+	prefix := ""
+	if atLineStart && asciiDocLineStart.MatchString(text) {
+		prefix = "{empty}"
+	}
+	if !strings.ContainsAny(text, asciiDocSpecialCharacters) {
+		return prefix + text
+	}
+
+	// A backslash before the closing bracket of the passthrough would escape it
+	passed := strings.TrimRight(text, "\\")
+	backslashes := strings.Repeat("{backslash}", len(text)-len(passed))
+	if passed == "" {
+		return prefix + backslashes
+	}
+	return prefix + "pass:c[" + strings.ReplaceAll(passed, "]", "\\]") + "]" + backslashes
+}
+
+// escapeAsciiDocLinkText replaces the characters that have a meaning in AsciiDoc with character references.
+// A passthrough can't be used in the text of a link macro, as its closing bracket would end the text of the link.
+func escapeAsciiDocLinkText(text string) string {
+	result := &strings.Builder{}
+	for _, r := range text {
+		if strings.ContainsRune(asciiDocSpecialCharacters, r) {
+			fmt.Fprintf(result, "&#%d;", r)
+		} else {
+			result.WriteRune(r)
+		}
+	}
+	return result.String()
+}
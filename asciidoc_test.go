@@ -0,0 +1,294 @@
+package goeditorjs_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/davidscottmills/goeditorjs"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_NewAsciiDocEngine(t *testing.T) {
+	eng := goeditorjs.NewAsciiDocEngine()
+	require.NotNil(t, eng.BlockHandlers)
+	require.NotNil(t, eng.TuneHandlers)
+}
+
+func Test_GenerateAsciiDoc_Returns_Parse_Err(t *testing.T) {
+	eng := goeditorjs.NewAsciiDocEngine()
+	_, err := eng.GenerateAsciiDoc(``)
+	require.Error(t, err)
+}
+
+func Test_GenerateAsciiDoc_NoHandler_Should_Err(t *testing.T) {
+	eng := goeditorjs.NewAsciiDocEngine()
+	_, err := eng.GenerateAsciiDoc(`{"blocks": [{"type": "header","data": {"text": "Heading","level": 1}}]}`)
+	require.True(t, errors.Is(err, goeditorjs.ErrBlockHandlerNotFound))
+}
+
+func Test_GenerateAsciiDoc(t *testing.T) {
+	editorJSData := `{"blocks": [
+		{"type": "header","data": {"text": "The <b>Title</b>","level": 1}},
+		{"type": "paragraph","data": {"text": "Text","alignment": "left"}},
+		{"type": "list","data": {"style": "unordered","items": ["Apple","Pear"]}}
+	]}`
+
+	result, err := goeditorjs.NewDefaultAsciiDocEngine().GenerateAsciiDoc(editorJSData)
+	require.NoError(t, err)
+	require.Equal(t, "== The **Title**\n\nText\n\n* Apple\n* Pear", result)
+}
+
+func Test_HeaderHandler_GenerateAsciiDoc_Returns_Parse_Err(t *testing.T) {
+	h := &goeditorjs.HeaderHandler{}
+	_, err := h.GenerateAsciiDoc(goeditorjs.EditorJSBlock{Type: "header", Data: []byte{}})
+	require.Error(t, err)
+}
+
+func Test_HeaderHandler_GenerateAsciiDoc(t *testing.T) {
+	h := &goeditorjs.HeaderHandler{}
+	testData := []struct {
+		data           string
+		expectedResult string
+	}{
+		{data: `{"text": "The <b>Title</b>","level": 1}`, expectedResult: "== The **Title**"},
+		{data: `{"text": "Deep<br>heading","level": 6}`, expectedResult: "====== Deep heading"},
+	}
+
+	for _, td := range testData {
+		result, err := h.GenerateAsciiDoc(goeditorjs.EditorJSBlock{Type: "header", Data: []byte(td.data)})
+		require.NoError(t, err)
+		require.Equal(t, td.expectedResult, result, td.data)
+	}
+}
+
+func Test_ParagraphHandler_GenerateAsciiDoc_Returns_Parse_Err(t *testing.T) {
+	h := &goeditorjs.ParagraphHandler{}
+	_, err := h.GenerateAsciiDoc(goeditorjs.EditorJSBlock{Type: "paragraph", Data: []byte{}})
+	require.Error(t, err)
+}
+
+func Test_ParagraphHandler_GenerateAsciiDoc(t *testing.T) {
+	h := &goeditorjs.ParagraphHandler{}
+	testData := []struct {
+		data           string
+		expectedResult string
+	}{
+		{data: `{"text": "Fish &amp; chips&nbsp;<i>today</i><br>. Second   line","alignment": "left"}`,
+			expectedResult: "Fish & chips\u00a0__today__ +\n{empty}. Second line"},
+		{data: `{"text": "Centered","alignment": "center"}`, expectedResult: "[.text-center]\nCentered"},
+		{data: `{"text": "Text","alignment": "center]\n== Injected\n[.x"}`, expectedResult: "Text"},
+	}
+
+	for _, td := range testData {
+		result, err := h.GenerateAsciiDoc(goeditorjs.EditorJSBlock{Type: "paragraph", Data: []byte(td.data)})
+		require.NoError(t, err)
+		require.Equal(t, td.expectedResult, result, td.data)
+	}
+}
+
+func Test_ListHandler_GenerateAsciiDoc_Returns_Parse_Err(t *testing.T) {
+	h := &goeditorjs.ListHandler{}
+	_, err := h.GenerateAsciiDoc(goeditorjs.EditorJSBlock{Type: "list", Data: []byte{}})
+	require.Error(t, err)
+}
+
+func Test_ListHandler_GenerateAsciiDoc(t *testing.T) {
+	h := &goeditorjs.ListHandler{}
+	testData := []struct {
+		data           string
+		expectedResult string
+	}{
+		{data: `{"style": "unordered","items": ["Apple","Pear"]}`, expectedResult: "* Apple\n* Pear"},
+		{data: `{"style": "ordered","meta": {"start": 3,"counterType": "lower-roman"},"items": [{"content": "One","items": [{"content": "Nested","items": []}]},{"content": "Two","items": []}]}`,
+			expectedResult: "[lowerroman,start=3]\n. One\n[lowerroman]\n.. Nested\n. Two"},
+		{data: `{"style": "ordered","items": [{"content": "One","items": [{"content": "Nested","items": []}]}]}`,
+			expectedResult: ". One\n[arabic]\n.. Nested"},
+		{data: `{"style": "checklist","items": [{"content": "Done","meta": {"checked": true},"items": [{"content": "Todo","items": []}]}]}`,
+			expectedResult: "* [x] Done\n** [ ] Todo"},
+	}
+
+	for _, td := range testData {
+		result, err := h.GenerateAsciiDoc(goeditorjs.EditorJSBlock{Type: "list", Data: []byte(td.data)})
+		require.NoError(t, err)
+		require.Equal(t, td.expectedResult, result, td.data)
+	}
+}
+
+func Test_CodeBoxHandler_GenerateAsciiDoc_Returns_Parse_Err(t *testing.T) {
+	h := &goeditorjs.CodeBoxHandler{}
+	_, err := h.GenerateAsciiDoc(goeditorjs.EditorJSBlock{Type: "codeBox", Data: []byte{}})
+	require.Error(t, err)
+}
+
+func Test_CodeBoxHandler_GenerateAsciiDoc(t *testing.T) {
+	h := &goeditorjs.CodeBoxHandler{}
+	testData := []struct {
+		data           string
+		expectedResult string
+	}{
+		{data: `{"language": "go","code": "<span>x</span> := 1<div>y &lt; x</div>"}`, expectedResult: "[source,go]\n----\nx := 1\ny < x\n----"},
+		{data: `{"language": "","code": "----"}`, expectedResult: "-----\n----\n-----"},
+		{data: `{"language": "c++]\n[x y","code": "x"}`, expectedResult: "[source,c++xy]\n----\nx\n----"},
+	}
+
+	for _, td := range testData {
+		result, err := h.GenerateAsciiDoc(goeditorjs.EditorJSBlock{Type: "codeBox", Data: []byte(td.data)})
+		require.NoError(t, err)
+		require.Equal(t, td.expectedResult, result, td.data)
+	}
+}
+
+func Test_RawHTMLHandler_GenerateAsciiDoc_Returns_Parse_Err(t *testing.T) {
+	h := &goeditorjs.RawHTMLHandler{}
+	_, err := h.GenerateAsciiDoc(goeditorjs.EditorJSBlock{Type: "raw", Data: []byte{}})
+	require.Error(t, err)
+}
+
+func Test_RawHTMLHandler_GenerateAsciiDoc(t *testing.T) {
+	h := &goeditorjs.RawHTMLHandler{}
+	result, err := h.GenerateAsciiDoc(goeditorjs.EditorJSBlock{Type: "raw", Data: []byte(`{"html": "<div>Raw</div>"}`)})
+	require.NoError(t, err)
+	require.Equal(t, "++++\n<div>Raw</div>\n++++", result)
+}
+
+func Test_ImageHandler_GenerateAsciiDoc(t *testing.T) {
+	h := &goeditorjs.ImageHandler{}
+	testData := []struct {
+		data           string
+		expectedResult string
+	}{
+		{data: `{"file": {"url": "https://example.com/a b.png"},"caption": "The \"<i>caption</i>\"","withBorder": true,"stretched": true}`,
+			expectedResult: ".The \"__caption__\"\nimage::https://example.com/a%20b.png[\"The \\\"caption\\\"\",role=\"image-tool--stretched image-tool--withBorder\"]"},
+		{data: `{"file": {"url": "https://example.com/a.png"},"caption": ""}`, expectedResult: "image::https://example.com/a.png[]"},
+	}
+
+	for _, td := range testData {
+		result, err := h.GenerateAsciiDoc(goeditorjs.EditorJSBlock{Type: "image", Data: []byte(td.data)})
+		require.NoError(t, err)
+		require.Equal(t, td.expectedResult, result, td.data)
+	}
+}
+
+func Test_ImageHandler_GenerateAsciiDoc_Options(t *testing.T) {
+	handler := &goeditorjs.ImageHandler{Options: &goeditorjs.ImageHandlerOptions{StretchClass: "wide", BorderClass: "bordered", BackgroundClass: "filled"}}
+	block := goeditorjs.EditorJSBlock{Type: "image", Data: []byte(`{"file": {"url": "a.png"},"withBackground": true,"withBorder": true}`)}
+
+	result, err := handler.GenerateAsciiDoc(block)
+	require.NoError(t, err)
+	require.Equal(t, `image::a.png[role="bordered filled"]`, result)
+
+	_, err = handler.GenerateAsciiDoc(goeditorjs.EditorJSBlock{Type: "image", Data: []byte(`[]`)})
+	require.Error(t, err)
+}
+
+func Test_InlineHTMLToAsciiDoc(t *testing.T) {
+	testData := []struct {
+		html           string
+		expectedResult string
+	}{
+		{html: "Plain text", expectedResult: "Plain text"},
+		{html: "<b>Bold</b> <strong>strong</strong> <i>italic</i> <em>em</em>", expectedResult: "**Bold** **strong** __italic__ __em__"},
+		{html: "<b> spaced </b>word", expectedResult: "**spaced** word"},
+		{html: `<u class="cdx-underline">Underline</u> <s>struck</s> <mark class="cdx-marker">marked</mark>`,
+			expectedResult: "[.underline]##Underline## [.line-through]##struck## ##marked##"},
+		{html: `<code class="inline-code">a_b</code>`, expectedResult: "``pass:c[a_b]``"},
+		{html: `<a href="https://example.com/a b">A <b>link</b></a> <a href="javascript:alert(1)">Bad</a>`,
+			expectedResult: "link:https://example.com/a%20b[A **link**] Bad"},
+		{html: `<a href="https://x.io">a*b [c] <i>d_e</i></a>`, expectedResult: "link:https://x.io[a&#42;b &#91;c&#93; __d&#95;e__]"},
+		{html: "*not bold* [x] {attribute} a\\", expectedResult: "pass:c[*not bold* [x\\] {attribute} a]{backslash}"},
+		{html: "&lt;tag&gt; &amp; more", expectedResult: "pass:c[<tag> & more]"},
+		{html: "= Not a title<br>* Not a list<br>NOTE: Not an admonition", expectedResult: "{empty}= Not a title +\npass:c[* Not a list] +\n{empty}NOTE: Not an admonition"},
+		{html: "Text<script>alert(1)</script><span>span</span><br><br>", expectedResult: "Textspan"},
+	}
+
+	for _, td := range testData {
+		require.Equal(t, td.expectedResult, goeditorjs.InlineHTMLToAsciiDoc(td.html), td.html)
+	}
+}
+
+func Test_GenerateAsciiDoc_UnknownBlockPolicy(t *testing.T) {
+	editorJSData := `{"blocks": [{"type": "unknown","data": {}},{"type": "paragraph","data": {"text": "Text","alignment": "left"}}]}`
+	eng := goeditorjs.NewDefaultAsciiDocEngine()
+
+	eng.UnknownBlockPolicy = goeditorjs.UnknownBlockSkip
+	result, err := eng.GenerateAsciiDoc(editorJSData)
+	require.NoError(t, err)
+	require.Equal(t, "Text", result)
+
+	eng.UnknownBlockPolicy = goeditorjs.UnknownBlockPlaceholder
+	result, err = eng.GenerateAsciiDoc(editorJSData)
+	require.NoError(t, err)
+	require.Equal(t, "// goeditorjs: no handler for block type \"unknown\"\n\nText", result)
+}
+
+func Test_NewDefaultAsciiDocEngine(t *testing.T) {
+	editorJSData := `{"blocks": [
+		{"type": "paragraph","data": {"text": "Hidden","alignment": "left"},"tunes": {"hidden": true}},
+		{"type": "header","data": {"text": "Shown","level": 2}},
+		{"type": "table","data": {"content": [["A"]]}}
+	]}`
+	eng := goeditorjs.NewDefaultAsciiDocEngine()
+
+	_, err := eng.GenerateAsciiDoc(editorJSData)
+	require.True(t, errors.Is(err, goeditorjs.ErrBlockHandlerNotFound))
+
+	eng.UnknownBlockPolicy = goeditorjs.UnknownBlockSkip
+	result, err := eng.GenerateAsciiDoc(editorJSData)
+	require.NoError(t, err)
+	require.Equal(t, "=== Shown", result)
+}
+
+func Test_GenerateAsciiDoc_ContinueOnError(t *testing.T) {
+	editorJSData := `{"blocks": [{"type": "paragraph","data": []},{"type": "paragraph","data": {"text": "Text","alignment": "left"}}]}`
+	eng := goeditorjs.NewDefaultAsciiDocEngine()
+	eng.ContinueOnError = true
+
+	result, err := eng.GenerateAsciiDoc(editorJSData)
+	var blockErrs goeditorjs.BlockErrors
+	require.True(t, errors.As(err, &blockErrs))
+	require.Len(t, blockErrs, 1)
+	require.Equal(t, 0, blockErrs[0].Index)
+	require.Equal(t, "Text", result)
+}
+
+func Test_RenderAsciiDoc(t *testing.T) {
+	editorJSData := `{"blocks": [{"type": "header","data": {"text": "Title","level": 1}},{"type": "paragraph","data": {"text": "Text","alignment": "left"}}]}`
+	w := &bytes.Buffer{}
+	err := goeditorjs.NewDefaultAsciiDocEngine().RenderAsciiDoc(w, strings.NewReader(editorJSData))
+	require.NoError(t, err)
+	require.Equal(t, "== Title\n\nText", w.String())
+
+	err = goeditorjs.NewDefaultAsciiDocEngine().RenderAsciiDoc(w, strings.NewReader(``))
+	require.Error(t, err)
+}
+
+func Test_GenerateAsciiDocContext_Stops_When_Cancelled(t *testing.T) {
+	editorJSData := `{"blocks": [{"type": "paragraph","data": {"text": "Text","alignment": "left"}}]}`
+	document, err := goeditorjs.ParseDocument(editorJSData)
+	require.NoError(t, err)
+	eng := goeditorjs.NewDefaultAsciiDocEngine()
+
+	result, err := eng.GenerateAsciiDocFromDocumentContext(context.Background(), document)
+	require.NoError(t, err)
+	require.Equal(t, "Text", result)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	result, err = eng.GenerateAsciiDocContext(ctx, editorJSData)
+	require.Equal(t, context.Canceled, err)
+	require.Equal(t, "", result)
+
+	result, err = eng.GenerateAsciiDocFromDocumentContext(ctx, document)
+	require.Equal(t, context.Canceled, err)
+	require.Equal(t, "", result)
+
+	w := &bytes.Buffer{}
+	require.Equal(t, context.Canceled, eng.RenderAsciiDocContext(ctx, w, strings.NewReader(editorJSData)))
+	require.Equal(t, context.Canceled, eng.RenderAsciiDocFromDocumentContext(ctx, w, document))
+	require.Equal(t, "", w.String())
+	require.Error(t, eng.RenderAsciiDocContext(ctx, w, strings.NewReader(``)))
+}
@@ -0,0 +1,115 @@
+package goeditorjs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+)
+
+// UnknownBlockPolicy decides what an engine does with a block that doesn't have a registered handler
+type UnknownBlockPolicy int
+
+const (
+	// UnknownBlockFail stops generation and returns ErrBlockHandlerNotFound. This is the default.
+	UnknownBlockFail UnknownBlockPolicy = iota
+	// UnknownBlockSkip leaves the block out of the output
+	UnknownBlockSkip
+	// UnknownBlockPlaceholder outputs a note naming the block type in place of the block.
+	// It's an html comment in html and markdown and a comment in AsciiDoc and reStructuredText.
+	UnknownBlockPlaceholder
+	// UnknownBlockFallback passes the block to the FallbackHandler of the engine.
+	// If the engine doesn't have a FallbackHandler, generation fails as with UnknownBlockFail.
+	UnknownBlockFallback
+)
+
+// unknownBlock reports the block to onUnknownBlock, if set, and returns the policy to apply to it
+func unknownBlock(policy UnknownBlockPolicy, hasFallback bool, onUnknownBlock func(editorJSBlock EditorJSBlock), editorJSBlock EditorJSBlock) UnknownBlockPolicy {
+	if onUnknownBlock != nil {
+		onUnknownBlock(editorJSBlock)
+	}
+
+	if policy == UnknownBlockFallback && !hasFallback {
+		return UnknownBlockFail
+	}
+	return policy
+}
+
+// errBlockHandlerNotFound returns ErrBlockHandlerNotFound for the block
+func errBlockHandlerNotFound(editorJSBlock EditorJSBlock) error {
+	return fmt.Errorf("%w, Block Type: %s", ErrBlockHandlerNotFound, editorJSBlock.Type)
+}
+
+var (
+	// unsafeBlockTypeChars matches the characters of a block type that aren't kept in html comments
+	unsafeBlockTypeChars = regexp.MustCompile(`[^A-Za-z0-9_-]`)
+	// hyphenRuns matches runs of hyphens, as "--" would end an html comment early
+	hyphenRuns = regexp.MustCompile(`-{2,}`)
+)
+
+// unknownBlockPlaceholder returns the html comment used by UnknownBlockPlaceholder.
+// Characters of the block type other than letters, digits, "_" and "-" are replaced with "_".
+func unknownBlockPlaceholder(editorJSBlock EditorJSBlock) string {
+	blockType := unsafeBlockTypeChars.ReplaceAllString(editorJSBlock.Type, "_")
+	blockType = hyphenRuns.ReplaceAllString(blockType, "-")
+	return fmt.Sprintf(`<!-- goeditorjs: no handler for block type "%s" -->`, blockType)
+}
+
+// blockWriter writes the output of a single block to buf. It returns false if the block doesn't have any output.
+type blockWriter func(ctx context.Context, buf *bytes.Buffer, editorJSBlock EditorJSBlock) (bool, error)
+
+// writeBlocks writes the output of every block to w, separated by separator.
+// Each block is generated into a buffer first, so only the output of blocks generated successfully is written.
+// Generation stops at the first block that fails with a *BlockError, or, if continueOnError is set,
+// continues with the next block and BlockErrors for every failing block are returned at the end.
+// If ctx is done, generation stops and the error of the context is returned.
+func writeBlocks(ctx context.Context, w io.Writer, blocks []EditorJSBlock, separator string, continueOnError bool, write blockWriter) error {
+	buf := &bytes.Buffer{}
+	blockErrs := BlockErrors{}
+	written := false
+	for i, block := range blocks {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		buf.Reset()
+		ok, err := write(ctx, buf, block)
+		if err != nil {
+			blockErr := &BlockError{Index: i, ID: block.ID, Type: block.Type, Err: err}
+			if !continueOnError {
+				return blockErr
+			}
+			blockErrs = append(blockErrs, blockErr)
+			continue
+		}
+		if !ok {
+			continue
+		}
+
+		if written {
+			if _, err := io.WriteString(w, separator); err != nil {
+				return err
+			}
+		}
+		if _, err := buf.WriteTo(w); err != nil {
+			return err
+		}
+		written = true
+	}
+
+	if len(blockErrs) > 0 {
+		return blockErrs
+	}
+	return nil
+}
+
+// decodeDocument decodes a Document from r
+func decodeDocument(r io.Reader) (*Document, error) {
+	document := &Document{}
+	if err := json.NewDecoder(r).Decode(document); err != nil {
+		return nil, err
+	}
+	return document, nil
+}
@@ -0,0 +1,71 @@
+package goeditorjs
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// BlockError is returned from the engines when a block fails to generate. It wraps the cause of the failure.
+type BlockError struct {
+	// Index is the index of the block in the document
+	Index int
+	// ID is the id of the block, if the document has block ids
+	ID   string
+	Type string
+	Err  error
+}
+
+// Error returns the error message, including where the block is in the document
+func (e *BlockError) Error() string {
+	if e.ID != "" {
+		return fmt.Sprintf("block %d (id: %s, type: %s): %v", e.Index, e.ID, e.Type, e.Err)
+	}
+	return fmt.Sprintf("block %d (type: %s): %v", e.Index, e.Type, e.Err)
+}
+
+// Unwrap returns the cause of the error
+func (e *BlockError) Unwrap() error {
+	return e.Err
+}
+
+// BlockErrors is returned from the engines when ContinueOnError is set and one or more blocks failed to generate
+type BlockErrors []*BlockError
+
+// Error returns the messages of all of the errors
+func (e BlockErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, blockErr := range e {
+		messages = append(messages, blockErr.Error())
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Is reports whether any of the errors matches target
+func (e BlockErrors) Is(target error) bool {
+	for _, blockErr := range e {
+		if errors.Is(blockErr, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first of the errors that matches target, and if so, sets target to that error value and returns true
+func (e BlockErrors) As(target interface{}) bool {
+	for _, blockErr := range e {
+		if errors.As(blockErr, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// Unwrap returns the errors
+func (e BlockErrors) Unwrap() []error {
+	errs := make([]error, 0, len(e))
+	for _, blockErr := range e {
+		errs = append(errs, blockErr)
+	}
+	return errs
+}
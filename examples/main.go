@@ -15,19 +15,16 @@ func main() {
 
 	ejs := string(content)
 
+	// Both engines are built from a registry with all built-in handlers,
+	// the image handler is replaced to use custom classes.
+	registry := goeditorjs.NewDefaultRegistry()
+	registry.Register(&goeditorjs.ImageHandler{Options: &goeditorjs.ImageHandlerOptions{
+		StretchClass:    "imageStretched",
+		BorderClass:     "imageBorder",
+		BackgroundClass: "imageBackground"}})
+
 	// Generate HTML and save it to a file
-	htmlEngine := goeditorjs.NewHTMLEngine()
-	htmlEngine.RegisterBlockHandlers(
-		&goeditorjs.HeaderHandler{},
-		&goeditorjs.ParagraphHandler{},
-		&goeditorjs.ListHandler{},
-		&goeditorjs.CodeBoxHandler{},
-		&goeditorjs.RawHTMLHandler{},
-		&goeditorjs.ImageHandler{Options: &goeditorjs.ImageHandlerOptions{
-			StretchClass:    "imageStretched",
-			BorderClass:     "imageBorder",
-			BackgroundClass: "imageBackground"}},
-	)
+	htmlEngine := goeditorjs.NewHTMLEngineFromRegistry(registry)
 	html, err := htmlEngine.GenerateHTML(ejs)
 	if err != nil {
 		log.Fatal(err)
@@ -39,18 +36,7 @@ func main() {
 	}
 
 	// Generate markdown and save it to a file
-	markdownEngine := goeditorjs.NewMarkdownEngine()
-	markdownEngine.RegisterBlockHandlers(
-		&goeditorjs.HeaderHandler{},
-		&goeditorjs.ParagraphHandler{},
-		&goeditorjs.ListHandler{},
-		&goeditorjs.CodeBoxHandler{},
-		&goeditorjs.RawHTMLHandler{},
-		&goeditorjs.ImageHandler{Options: &goeditorjs.ImageHandlerOptions{
-			StretchClass:    "imageStretched",
-			BorderClass:     "imageBorder",
-			BackgroundClass: "imageBackground"}},
-	)
+	markdownEngine := goeditorjs.NewMarkdownEngineFromRegistry(registry)
 	md, err := markdownEngine.GenerateMarkdown(ejs)
 	if err != nil {
 		log.Fatal(err)
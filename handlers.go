@@ -1,11 +1,17 @@
 package goeditorjs
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"html"
+	"html/template"
+	"net/url"
 	"regexp"
-	"sort"
+	"strconv"
 	"strings"
+	"unicode/utf8"
 )
 
 // HeaderHandler is the default HeaderHandler for EditorJS HTML generation
@@ -23,22 +29,87 @@ func (*HeaderHandler) Type() string {
 
 // GenerateHTML generates html for HeaderBlocks
 func (h *HeaderHandler) GenerateHTML(editorJSBlock EditorJSBlock) (string, error) {
+	return h.GenerateHTMLContext(context.Background(), editorJSBlock)
+}
+
+// GenerateHTMLContext generates html for HeaderBlocks, sanitizing the text with the SanitizePolicy of ctx
+func (h *HeaderHandler) GenerateHTMLContext(ctx context.Context, editorJSBlock EditorJSBlock) (string, error) {
 	header, err := h.parse(editorJSBlock)
 	if err != nil {
 		return "", err
 	}
 
-	return fmt.Sprintf("<h%d>%s</h%d>", header.Level, header.Text, header.Level), nil
+	policy := SanitizePolicyFromContext(ctx)
+	return fmt.Sprintf("<h%d>%s</h%d>", header.Level, policy.Sanitize(header.Text), header.Level), nil
 }
 
 // GenerateMarkdown generates markdown for HeaderBlocks
 func (h *HeaderHandler) GenerateMarkdown(editorJSBlock EditorJSBlock) (string, error) {
+	return h.GenerateMarkdownContext(context.Background(), editorJSBlock)
+}
+
+// GenerateMarkdownContext generates markdown for HeaderBlocks, converting inline html with the InlineMarkdownOptions of ctx
+func (h *HeaderHandler) GenerateMarkdownContext(ctx context.Context, editorJSBlock EditorJSBlock) (string, error) {
+	header, err := h.parse(editorJSBlock)
+	if err != nil {
+		return "", err
+	}
+
+	text := InlineHTMLToMarkdown(header.Text, InlineMarkdownOptionsFromContext(ctx))
+	return fmt.Sprintf("%s %s", strings.Repeat("#", header.Level), text), nil
+}
+
+// GenerateText generates plain text for HeaderBlocks
+func (h *HeaderHandler) GenerateText(editorJSBlock EditorJSBlock) (string, error) {
+	header, err := h.parse(editorJSBlock)
+	if err != nil {
+		return "", err
+	}
+
+	return htmlToText(header.Text, false), nil
+}
+
+// GenerateAsciiDoc generates AsciiDoc for HeaderBlocks. Headers are sections one level below the document title,
+// so a level 1 header is "==", and levels past the deepest section level are clamped to it.
+func (h *HeaderHandler) GenerateAsciiDoc(editorJSBlock EditorJSBlock) (string, error) {
+	header, err := h.parse(editorJSBlock)
+	if err != nil {
+		return "", err
+	}
+
+	level := header.Level
+	if level < 1 {
+		level = 1
+	} else if level > 5 {
+		level = 5
+	}
+	return fmt.Sprintf("%s %s", strings.Repeat("=", level+1), asciiDocText(header.Text)), nil
+}
+
+// rstSectionAdornments are the characters underlining the section titles of header levels 1 to 6
+var rstSectionAdornments = []string{"=", "-", "~", "^", `"`, "'"}
+
+// GenerateRST generates reStructuredText for HeaderBlocks, as section titles underlined with a character per level.
+// Headers without text generate nothing, as a section title can't be empty.
+func (h *HeaderHandler) GenerateRST(editorJSBlock EditorJSBlock) (string, error) {
 	header, err := h.parse(editorJSBlock)
 	if err != nil {
 		return "", err
 	}
 
-	return fmt.Sprintf("%s %s", strings.Repeat("#", header.Level), header.Text), nil
+	level := header.Level
+	if level < 1 {
+		level = 1
+	} else if level > len(rstSectionAdornments) {
+		level = len(rstSectionAdornments)
+	}
+
+	title := rstText(header.Text)
+	if strings.TrimSpace(title) == "" {
+		return "", nil
+	}
+	underline := strings.Repeat(rstSectionAdornments[level-1], rstWidth(title))
+	return fmt.Sprintf("%s\n%s", title, underline), nil
 }
 
 // ParagraphHandler is the default ParagraphHandler for EditorJS HTML generation
@@ -56,20 +127,31 @@ func (*ParagraphHandler) Type() string {
 
 // GenerateHTML generates html for ParagraphBlocks
 func (h *ParagraphHandler) GenerateHTML(editorJSBlock EditorJSBlock) (string, error) {
+	return h.GenerateHTMLContext(context.Background(), editorJSBlock)
+}
+
+// GenerateHTMLContext generates html for ParagraphBlocks, sanitizing the text with the SanitizePolicy of ctx
+func (h *ParagraphHandler) GenerateHTMLContext(ctx context.Context, editorJSBlock EditorJSBlock) (string, error) {
 	paragraph, err := h.parse(editorJSBlock)
 	if err != nil {
 		return "", err
 	}
 
+	policy := SanitizePolicyFromContext(ctx)
 	if paragraph.Alignment != "left" {
-		return fmt.Sprintf(`<p style="text-align:%s">%s</p>`, paragraph.Alignment, paragraph.Text), nil
+		return fmt.Sprintf(`<p style="text-align:%s">%s</p>`, policy.SanitizeAttribute(paragraph.Alignment), policy.Sanitize(paragraph.Text)), nil
 	}
 
-	return fmt.Sprintf(`<p>%s</p>`, paragraph.Text), nil
+	return fmt.Sprintf(`<p>%s</p>`, policy.Sanitize(paragraph.Text)), nil
 }
 
 // GenerateMarkdown generates markdown for ParagraphBlocks
 func (h *ParagraphHandler) GenerateMarkdown(editorJSBlock EditorJSBlock) (string, error) {
+	return h.GenerateMarkdownContext(context.Background(), editorJSBlock)
+}
+
+// GenerateMarkdownContext generates markdown for ParagraphBlocks, converting inline html with the InlineMarkdownOptions of ctx
+func (h *ParagraphHandler) GenerateMarkdownContext(ctx context.Context, editorJSBlock EditorJSBlock) (string, error) {
 	paragraph, err := h.parse(editorJSBlock)
 	if err != nil {
 		return "", err
@@ -80,7 +162,43 @@ func (h *ParagraphHandler) GenerateMarkdown(editorJSBlock EditorJSBlock) (string
 		return fmt.Sprintf(`<p style="text-align:%s">%s</p>`, paragraph.Alignment, paragraph.Text), nil
 	}
 
-	return paragraph.Text, nil
+	return InlineHTMLToMarkdown(paragraph.Text, InlineMarkdownOptionsFromContext(ctx)), nil
+}
+
+// GenerateText generates plain text for ParagraphBlocks
+func (h *ParagraphHandler) GenerateText(editorJSBlock EditorJSBlock) (string, error) {
+	paragraph, err := h.parse(editorJSBlock)
+	if err != nil {
+		return "", err
+	}
+
+	return htmlToText(paragraph.Text, false), nil
+}
+
+// GenerateAsciiDoc generates AsciiDoc for ParagraphBlocks. Alignment is set with the text alignment roles of Asciidoctor,
+// alignments other than left, center, right and justify are left out.
+func (h *ParagraphHandler) GenerateAsciiDoc(editorJSBlock EditorJSBlock) (string, error) {
+	paragraph, err := h.parse(editorJSBlock)
+	if err != nil {
+		return "", err
+	}
+
+	text := InlineHTMLToAsciiDoc(paragraph.Text)
+	if isTextAlignment(paragraph.Alignment) && paragraph.Alignment != "left" {
+		return fmt.Sprintf("[.text-%s]\n%s", paragraph.Alignment, text), nil
+	}
+	return text, nil
+}
+
+// GenerateRST generates reStructuredText for ParagraphBlocks. Paragraphs with line breaks are line blocks.
+// reStructuredText doesn't support alignment, so it's left out.
+func (h *ParagraphHandler) GenerateRST(editorJSBlock EditorJSBlock) (string, error) {
+	paragraph, err := h.parse(editorJSBlock)
+	if err != nil {
+		return "", err
+	}
+
+	return rstParagraph(InlineHTMLToRST(paragraph.Text)), nil
 }
 
 // ListHandler is the default ListHandler for EditorJS HTML generation
@@ -98,46 +216,318 @@ func (*ListHandler) Type() string {
 
 // GenerateHTML generates html for ListBlocks
 func (h *ListHandler) GenerateHTML(editorJSBlock EditorJSBlock) (string, error) {
+	return h.GenerateHTMLContext(context.Background(), editorJSBlock)
+}
+
+// GenerateHTMLContext generates html for ListBlocks, sanitizing the items with the SanitizePolicy of ctx
+func (h *ListHandler) GenerateHTMLContext(ctx context.Context, editorJSBlock EditorJSBlock) (string, error) {
 	list, err := h.parse(editorJSBlock)
 	if err != nil {
 		return "", err
 	}
 
-	result := ""
-	if list.Style == "ordered" {
-		result = "<ol>%s</ol>"
-	} else {
-		result = "<ul>%s</ul>"
+	return h.generateHTML(SanitizePolicyFromContext(ctx), list.Style, list.Meta, list.Items), nil
+}
+
+// GenerateMarkdown generates markdown for ListBlocks
+func (h *ListHandler) GenerateMarkdown(editorJSBlock EditorJSBlock) (string, error) {
+	return h.GenerateMarkdownContext(context.Background(), editorJSBlock)
+}
+
+// GenerateMarkdownContext generates markdown for ListBlocks, converting inline html with the InlineMarkdownOptions of ctx
+func (h *ListHandler) GenerateMarkdownContext(ctx context.Context, editorJSBlock EditorJSBlock) (string, error) {
+	list, err := h.parse(editorJSBlock)
+	if err != nil {
+		return "", err
 	}
 
-	innerData := ""
-	for _, s := range list.Items {
-		innerData += fmt.Sprintf("<li>%s</li>", s)
+	if list.Style == "ordered" && !isNumericCounterType(list.Meta.CounterType) {
+		// Native markdown only supports numeric counters, so we'll use html instead.
+		return h.generateHTML(nil, list.Style, list.Meta, list.Items), nil
+	}
+
+	start := 1
+	if list.Meta.Start > 0 {
+		start = list.Meta.Start
 	}
 
-	return fmt.Sprintf(result, innerData), nil
+	return strings.Join(h.generateMarkdown(InlineMarkdownOptionsFromContext(ctx), list.Style, start, list.Items, ""), "\n"), nil
 }
 
-// GenerateMarkdown generates markdown for ListBlocks
-func (h *ListHandler) GenerateMarkdown(editorJSBlock EditorJSBlock) (string, error) {
+// GenerateText generates plain text for ListBlocks
+func (h *ListHandler) GenerateText(editorJSBlock EditorJSBlock) (string, error) {
 	list, err := h.parse(editorJSBlock)
 	if err != nil {
 		return "", err
 	}
 
-	listItemPrefix := ""
+	start := 1
+	if list.Meta.Start > 0 {
+		start = list.Meta.Start
+	}
+
+	return strings.Join(h.generateText(list.Style, start, list.Items, ""), "\n"), nil
+}
+
+// GenerateAsciiDoc generates AsciiDoc for ListBlocks
+func (h *ListHandler) GenerateAsciiDoc(editorJSBlock EditorJSBlock) (string, error) {
+	list, err := h.parse(editorJSBlock)
+	if err != nil {
+		return "", err
+	}
+
+	results := []string{}
 	if list.Style == "ordered" {
-		listItemPrefix = "1. "
+		attributes := []string{}
+		if style := asciiDocListStyle(list.Meta.CounterType); style != "arabic" {
+			attributes = append(attributes, style)
+		}
+		if list.Meta.Start > 1 {
+			attributes = append(attributes, fmt.Sprintf("start=%d", list.Meta.Start))
+		}
+		if len(attributes) > 0 {
+			results = append(results, "["+strings.Join(attributes, ",")+"]")
+		}
+	}
+
+	return strings.Join(append(results, h.generateAsciiDoc(list.Style, list.Meta.CounterType, list.Items, 1)...), "\n"), nil
+}
+
+// GenerateRST generates reStructuredText for ListBlocks, as bullet or enumerated lists
+func (h *ListHandler) GenerateRST(editorJSBlock EditorJSBlock) (string, error) {
+	list, err := h.parse(editorJSBlock)
+	if err != nil {
+		return "", err
+	}
+
+	start := 1
+	if list.Meta.Start > 0 {
+		start = list.Meta.Start
+	}
+
+	return strings.Join(h.generateRST(list.Style, list.Meta.CounterType, start, list.Items), "\n"), nil
+}
+
+func (h *ListHandler) generateHTML(policy *SanitizePolicy, style string, meta listMeta, items []listItem) string {
+	result := ""
+	if style == "ordered" {
+		attributes := ""
+		if meta.Start > 1 {
+			attributes += fmt.Sprintf(` start="%d"`, meta.Start)
+		}
+		if !isNumericCounterType(meta.CounterType) {
+			attributes += fmt.Sprintf(` style="list-style-type:%s"`, policy.SanitizeAttribute(meta.CounterType))
+		}
+		result = "<ol" + attributes + ">%s</ol>"
+	} else {
+		result = "<ul>%s</ul>"
+	}
+
+	// Nested lists share the style and counter type of the list, but always start at the first counter.
+	nestedMeta := listMeta{CounterType: meta.CounterType}
+
+	innerData := ""
+	for _, item := range items {
+		content := policy.Sanitize(item.Content)
+		if style == "checklist" {
+			content = checkboxHTML(content, item.Meta.Checked, "", "")
+		}
+		if len(item.Items) > 0 {
+			content += h.generateHTML(policy, style, nestedMeta, item.Items)
+		}
+		innerData += fmt.Sprintf("<li>%s</li>", content)
+	}
+
+	return fmt.Sprintf(result, innerData)
+}
+
+func (h *ListHandler) generateMarkdown(options *InlineMarkdownOptions, style string, start int, items []listItem, indent string) []string {
+	listItemPrefix := ""
+	if style == "ordered" {
+		listItemPrefix = fmt.Sprintf("%d. ", start)
 	} else {
 		listItemPrefix = "- "
 	}
 
+	// Nested items have to be indented past the list marker of their parent
+	nestedIndent := indent + strings.Repeat(" ", len(listItemPrefix))
+
 	results := []string{}
-	for _, s := range list.Items {
-		results = append(results, listItemPrefix+s)
+	for _, item := range items {
+		prefix := listItemPrefix
+		if style == "checklist" {
+			prefix += checkboxMarkdown(item.Meta.Checked)
+		}
+		results = append(results, indent+prefix+InlineHTMLToMarkdown(item.Content, options))
+		results = append(results, h.generateMarkdown(options, style, 1, item.Items, nestedIndent)...)
 	}
 
-	return strings.Join(results, "\n"), nil
+	return results
+}
+
+func (h *ListHandler) generateText(style string, start int, items []listItem, indent string) []string {
+	results := []string{}
+	for i, item := range items {
+		prefix := "- "
+		switch style {
+		case "ordered":
+			prefix = fmt.Sprintf("%d. ", start+i)
+		case "checklist":
+			prefix = checkboxMarkdown(item.Meta.Checked)
+		}
+
+		// Lines of the item, and its nested items, are indented past the list marker
+		nestedIndent := indent + strings.Repeat(" ", len(prefix))
+		content := strings.ReplaceAll(htmlToText(item.Content, false), "\n", "\n"+nestedIndent)
+		results = append(results, indent+prefix+content)
+		results = append(results, h.generateText(style, 1, item.Items, nestedIndent)...)
+	}
+
+	return results
+}
+
+func (h *ListHandler) generateAsciiDoc(style, counterType string, items []listItem, depth int) []string {
+	marker := strings.Repeat("*", depth) + " "
+	if style == "ordered" {
+		marker = strings.Repeat(".", depth) + " "
+	}
+
+	results := []string{}
+	for _, item := range items {
+		prefix := marker
+		if style == "checklist" {
+			prefix += checkboxMarkdown(item.Meta.Checked)
+		}
+		results = append(results, prefix+InlineHTMLToAsciiDoc(item.Content))
+
+		if len(item.Items) > 0 {
+			// Nested lists share the counter type of the list, instead of the default style of their depth
+			if style == "ordered" {
+				results = append(results, "["+asciiDocListStyle(counterType)+"]")
+			}
+			results = append(results, h.generateAsciiDoc(style, counterType, item.Items, depth+1)...)
+		}
+	}
+
+	return results
+}
+
+func (h *ListHandler) generateRST(style, counterType string, start int, items []listItem) []string {
+	results := []string{}
+	for i, item := range items {
+		prefix := "- "
+		switch style {
+		case "ordered":
+			prefix = rstEnumerator(counterType, start+len(items)-1, start+i) + " "
+		case "checklist":
+			prefix += checkboxMarkdown(item.Meta.Checked)
+		}
+
+		// Lines of the item, and its nested items, are indented past the list marker
+		indent := strings.Repeat(" ", utf8.RuneCountInString(prefix))
+		content := rstParagraph(InlineHTMLToRST(item.Content))
+		results = append(results, prefix+strings.ReplaceAll(content, "\n", "\n"+indent))
+
+		// Nested lists are separated from the items around them by blank lines
+		if len(item.Items) > 0 {
+			results = append(results, "", rstIndent(strings.Join(h.generateRST(style, counterType, 1, item.Items), "\n"), indent))
+			if i < len(items)-1 {
+				results = append(results, "")
+			}
+		}
+	}
+
+	return results
+}
+
+// rstEnumerator returns the enumerator of the nth item of an enumerated list with the counter type, whose last item is the last one.
+// Letters only go up to z, so longer lists are numbered instead.
+func rstEnumerator(counterType string, last, n int) string {
+	switch counterType {
+	case "lower-alpha", "lower-latin", "upper-alpha", "upper-latin":
+		if last > 26 {
+			break
+		}
+		letter := string(rune('a' + n - 1))
+		if strings.HasPrefix(counterType, "upper") {
+			letter = strings.ToUpper(letter)
+		}
+		return letter + "."
+	case "lower-roman":
+		return strings.ToLower(romanNumeral(n)) + "."
+	case "upper-roman":
+		return romanNumeral(n) + "."
+	}
+	return fmt.Sprintf("%d.", n)
+}
+
+// romanNumeral returns n as an upper case roman numeral
+func romanNumeral(n int) string {
+	values := []int{1000, 900, 500, 400, 100, 90, 50, 40, 10, 9, 5, 4, 1}
+	symbols := []string{"M", "CM", "D", "CD", "C", "XC", "L", "XL", "X", "IX", "V", "IV", "I"}
+
+	result := &strings.Builder{}
+	for i, value := range values {
+		for n >= value {
+			result.WriteString(symbols[i])
+			n -= value
+		}
+	}
+	return result.String()
+}
+
+// asciiDocListStyle returns the AsciiDoc ordered list style of the counter type
+func asciiDocListStyle(counterType string) string {
+	switch counterType {
+	case "lower-alpha", "lower-latin":
+		return "loweralpha"
+	case "upper-alpha", "upper-latin":
+		return "upperalpha"
+	case "lower-roman":
+		return "lowerroman"
+	case "upper-roman":
+		return "upperroman"
+	case "lower-greek":
+		return "lowergreek"
+	}
+	return "arabic"
+}
+
+// isNumericCounterType reports whether the ordered list counter type is a plain decimal counter
+func isNumericCounterType(counterType string) bool {
+	return counterType == "" || counterType == "numeric" || counterType == "decimal"
+}
+
+// checkboxHTML generates a disabled checkbox wrapped in the label it's associated with
+func checkboxHTML(text string, checked bool, labelClass, checkboxClass string) string {
+	checkedAttribute := ""
+	if checked {
+		checkedAttribute = " checked"
+	}
+	return fmt.Sprintf(`<label%s><input type="checkbox"%s disabled%s/> %s</label>`,
+		classAttribute(labelClass), classAttribute(checkboxClass), checkedAttribute, text)
+}
+
+// classAttribute returns a class attribute with a leading space for the given classes, or "" if there are none
+func classAttribute(classes ...string) string {
+	nonEmpty := []string{}
+	for _, class := range classes {
+		if class != "" {
+			nonEmpty = append(nonEmpty, class)
+		}
+	}
+
+	if len(nonEmpty) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(` class="%s"`, strings.Join(nonEmpty, " "))
+}
+
+func checkboxMarkdown(checked bool) string {
+	if checked {
+		return "[x] "
+	}
+	return "[ ] "
 }
 
 // CodeBoxHandler is the default CodeBoxHandler for EditorJS HTML generation
@@ -155,12 +545,23 @@ func (*CodeBoxHandler) Type() string {
 
 // GenerateHTML generates html for CodeBoxBlocks
 func (h *CodeBoxHandler) GenerateHTML(editorJSBlock EditorJSBlock) (string, error) {
+	return h.GenerateHTMLContext(context.Background(), editorJSBlock)
+}
+
+// GenerateHTMLContext generates html for CodeBoxBlocks, sanitizing the code with the SanitizePolicy of ctx
+func (h *CodeBoxHandler) GenerateHTMLContext(ctx context.Context, editorJSBlock EditorJSBlock) (string, error) {
 	codeBox, err := h.parse(editorJSBlock)
 	if err != nil {
 		return "", err
 	}
 
-	return fmt.Sprintf(`<pre><code class="%s">%s</code></pre>`, codeBox.Language, codeBox.Code), nil
+	policy := SanitizePolicyFromContext(ctx)
+	code := codeBox.Code
+	if policy != nil {
+		// The lines of the code are wrapped in elements the policy would remove, so the code is reduced to its text
+		code = html.EscapeString(htmlToText(code, true))
+	}
+	return fmt.Sprintf(`<pre><code class="%s">%s</code></pre>`, policy.SanitizeAttribute(codeBox.Language), code), nil
 }
 
 // GenerateMarkdown generates markdown for CodeBoxBlocks
@@ -170,27 +571,56 @@ func (h *CodeBoxHandler) GenerateMarkdown(editorJSBlock EditorJSBlock) (string,
 		return "", err
 	}
 
-	codeBox.Code = strings.ReplaceAll(codeBox.Code, "<div>", "\n")
-	codeBox.Code = removeHTMLTags(codeBox.Code)
+	return fmt.Sprintf("```%s\n%s\n```", codeBox.Language, htmlToText(codeBox.Code, true)), nil
+}
+
+// GenerateText generates plain text for CodeBoxBlocks
+func (h *CodeBoxHandler) GenerateText(editorJSBlock EditorJSBlock) (string, error) {
+	codeBox, err := h.parse(editorJSBlock)
+	if err != nil {
+		return "", err
+	}
+
+	return htmlToText(codeBox.Code, true), nil
+}
+
+// unsafeLanguageChars matches the characters that can't be part of the language of code
+var unsafeLanguageChars = regexp.MustCompile(`[^A-Za-z0-9_+#.-]`)
 
-	return fmt.Sprintf("```%s\n%s\n```", codeBox.Language, codeBox.Code), nil
+// sanitizeCodeLanguage strips the characters from the language of code that could break out of the markup it's put in
+func sanitizeCodeLanguage(language string) string {
+	return unsafeLanguageChars.ReplaceAllString(language, "")
 }
 
-func removeHTMLTags(in string) string {
-	// regex to match html tag
-	const pattern = `(<\/?[a-zA-A]+?[^>]*\/?>)*`
-	r := regexp.MustCompile(pattern)
-	groups := r.FindAllString(in, -1)
-	// should replace long string first
-	sort.Slice(groups, func(i, j int) bool {
-		return len(groups[i]) > len(groups[j])
-	})
-	for _, group := range groups {
-		if strings.TrimSpace(group) != "" {
-			in = strings.ReplaceAll(in, group, "")
-		}
+// GenerateAsciiDoc generates AsciiDoc for CodeBoxBlocks, as a source block if the language is set
+func (h *CodeBoxHandler) GenerateAsciiDoc(editorJSBlock EditorJSBlock) (string, error) {
+	codeBox, err := h.parse(editorJSBlock)
+	if err != nil {
+		return "", err
 	}
-	return in
+
+	code := htmlToText(codeBox.Code, true)
+	delimiter := asciiDocDelimiter(code, "-")
+	language := sanitizeCodeLanguage(codeBox.Language)
+	if language == "" {
+		return fmt.Sprintf("%s\n%s\n%s", delimiter, code, delimiter), nil
+	}
+	return fmt.Sprintf("[source,%s]\n%s\n%s\n%s", language, delimiter, code, delimiter), nil
+}
+
+// GenerateRST generates reStructuredText for CodeBoxBlocks, as a code-block directive.
+// Code boxes without code generate a comment, as the directive requires content.
+func (h *CodeBoxHandler) GenerateRST(editorJSBlock EditorJSBlock) (string, error) {
+	codeBox, err := h.parse(editorJSBlock)
+	if err != nil {
+		return "", err
+	}
+
+	code := htmlToText(codeBox.Code, true)
+	if strings.TrimSpace(code) == "" {
+		return ".. goeditorjs: empty code block", nil
+	}
+	return rstDirective("code-block", sanitizeCodeLanguage(codeBox.Language), nil, code), nil
 }
 
 // RawHTMLHandler is the default raw handler for EditorJS HTML generation
@@ -203,8 +633,17 @@ func (*RawHTMLHandler) Type() string {
 
 // GenerateHTML generates html for rawBlocks
 func (h *RawHTMLHandler) GenerateHTML(editorJSBlock EditorJSBlock) (string, error) {
-	return h.raw(editorJSBlock)
+	return h.GenerateHTMLContext(context.Background(), editorJSBlock)
+}
+
+// GenerateHTMLContext generates html for rawBlocks, sanitizing the html with the SanitizePolicy of ctx
+func (h *RawHTMLHandler) GenerateHTMLContext(ctx context.Context, editorJSBlock EditorJSBlock) (string, error) {
+	raw, err := h.raw(editorJSBlock)
+	if err != nil {
+		return "", err
+	}
 
+	return SanitizePolicyFromContext(ctx).Sanitize(raw), nil
 }
 
 // GenerateMarkdown generates markdown for rawBlocks
@@ -212,6 +651,37 @@ func (h *RawHTMLHandler) GenerateMarkdown(editorJSBlock EditorJSBlock) (string,
 	return h.raw(editorJSBlock)
 }
 
+// GenerateText generates plain text for rawBlocks
+func (h *RawHTMLHandler) GenerateText(editorJSBlock EditorJSBlock) (string, error) {
+	raw, err := h.raw(editorJSBlock)
+	if err != nil {
+		return "", err
+	}
+
+	return htmlToText(raw, false), nil
+}
+
+// GenerateAsciiDoc generates AsciiDoc for rawBlocks, passing the html through in a passthrough block
+func (h *RawHTMLHandler) GenerateAsciiDoc(editorJSBlock EditorJSBlock) (string, error) {
+	raw, err := h.raw(editorJSBlock)
+	if err != nil {
+		return "", err
+	}
+
+	delimiter := asciiDocDelimiter(raw, "+")
+	return fmt.Sprintf("%s\n%s\n%s", delimiter, raw, delimiter), nil
+}
+
+// GenerateRST generates reStructuredText for rawBlocks, passing the html through with a raw directive
+func (h *RawHTMLHandler) GenerateRST(editorJSBlock EditorJSBlock) (string, error) {
+	raw, err := h.raw(editorJSBlock)
+	if err != nil {
+		return "", err
+	}
+
+	return rstDirective("raw", "html", nil, raw), nil
+}
+
 func (h *RawHTMLHandler) raw(editorJSBlock EditorJSBlock) (string, error) {
 	raw := &raw{}
 	err := json.Unmarshal(editorJSBlock.Data, raw)
@@ -252,14 +722,26 @@ func (*ImageHandler) Type() string {
 	return "image"
 }
 
+func (h *ImageHandler) options() *ImageHandlerOptions {
+	if h.Options == nil {
+		return DefaultImageHandlerOptions
+	}
+	return h.Options
+}
+
 // GenerateHTML generates html for ImageBlocks
 func (h *ImageHandler) GenerateHTML(editorJSBlock EditorJSBlock) (string, error) {
+	return h.GenerateHTMLContext(context.Background(), editorJSBlock)
+}
+
+// GenerateHTMLContext generates html for ImageBlocks, sanitizing the url and caption with the SanitizePolicy of ctx
+func (h *ImageHandler) GenerateHTMLContext(ctx context.Context, editorJSBlock EditorJSBlock) (string, error) {
 	image, err := h.parse(editorJSBlock)
 	if err != nil {
 		return "", err
 	}
 
-	return h.generateHTML(image)
+	return h.generateHTML(SanitizePolicyFromContext(ctx), image)
 }
 
 // GenerateMarkdown generates markdown for ImageBlocks
@@ -270,34 +752,1326 @@ func (h *ImageHandler) GenerateMarkdown(editorJSBlock EditorJSBlock) (string, er
 	}
 
 	if image.Stretched || image.WithBackground || image.WithBorder {
-		return h.generateHTML(image)
+		return h.generateHTML(nil, image)
 	}
 	return fmt.Sprintf(`![alt text](%s "%s")`, image.File.URL, image.Caption), nil
 
 }
 
-func (h *ImageHandler) generateHTML(image *image) (string, error) {
-	if h.Options == nil {
-		h.Options = DefaultImageHandlerOptions
+// GenerateText generates plain text for ImageBlocks, which is the caption of the image
+func (h *ImageHandler) GenerateText(editorJSBlock EditorJSBlock) (string, error) {
+	image, err := h.parse(editorJSBlock)
+	if err != nil {
+		return "", err
 	}
 
-	classes := []string{}
-	if image.Stretched {
-		classes = append(classes, h.Options.StretchClass)
+	return htmlToText(image.Caption, false), nil
+}
+
+// GenerateAsciiDoc generates AsciiDoc for ImageBlocks. The caption is the title and the alt text of the image,
+// and the classes of the options for stretched images, borders and backgrounds are its roles.
+func (h *ImageHandler) GenerateAsciiDoc(editorJSBlock EditorJSBlock) (string, error) {
+	image, err := h.parse(editorJSBlock)
+	if err != nil {
+		return "", err
 	}
 
-	if image.WithBorder {
-		classes = append(classes, h.Options.BorderClass)
+	attributes := []string{}
+	if alt := htmlToText(image.Caption, false); alt != "" {
+		attributes = append(attributes, `"`+strings.ReplaceAll(alt, `"`, `\"`)+`"`)
 	}
+	if roles := strings.Join(h.classes(image), " "); roles != "" {
+		attributes = append(attributes, fmt.Sprintf(`role="%s"`, roles))
+	}
+	macro := fmt.Sprintf("image::%s[%s]", asciiDocURL(image.File.URL), strings.Join(attributes, ","))
 
-	if image.WithBackground {
-		classes = append(classes, h.Options.BackgroundClass)
+	if image.Caption == "" {
+		return macro, nil
 	}
+	return fmt.Sprintf(".%s\n%s", asciiDocText(image.Caption), macro), nil
+}
 
-	class := ""
-	if len(classes) > 0 {
-		class = fmt.Sprintf(`class="%s"`, strings.Join(classes, " "))
+// GenerateRST generates reStructuredText for ImageBlocks. Images with a caption are figures, with the caption as their alt text too,
+// and the classes of the options for stretched images, borders and backgrounds are the classes of the image.
+func (h *ImageHandler) GenerateRST(editorJSBlock EditorJSBlock) (string, error) {
+	image, err := h.parse(editorJSBlock)
+	if err != nil {
+		return "", err
+	}
+
+	options := [][2]string{}
+	alt := htmlToText(image.Caption, false)
+	if alt != "" {
+		options = append(options, [2]string{"alt", strings.ReplaceAll(alt, "\n", " ")})
+	}
+	if classes := h.classes(image); len(classes) > 0 {
+		options = append(options, [2]string{"class", strings.Join(classes, " ")})
 	}
 
-	return fmt.Sprintf(`<img src="%s" alt="%s" %s/>`, image.File.URL, image.Caption, class), nil
+	if alt == "" {
+		return rstDirective("image", rstURL(image.File.URL), options, ""), nil
+	}
+	return rstDirective("figure", rstURL(image.File.URL), options, rstText(image.Caption)), nil
+}
+
+func (h *ImageHandler) generateHTML(policy *SanitizePolicy, image *image) (string, error) {
+	classes := h.classes(image)
+	class := ""
+	if len(classes) > 0 {
+		class = fmt.Sprintf(`class="%s"`, strings.Join(classes, " "))
+	}
+
+	src := image.File.URL
+	if policy != nil {
+		src = html.EscapeString(policy.SanitizeURL(src))
+	}
+
+	return fmt.Sprintf(`<img src="%s" alt="%s" %s/>`, src, policy.SanitizeAttribute(image.Caption), class), nil
+}
+
+// classes returns the classes of the options for the flags of the image
+func (h *ImageHandler) classes(image *image) []string {
+	options := h.options()
+
+	classes := []string{}
+	if image.Stretched {
+		classes = append(classes, options.StretchClass)
+	}
+
+	if image.WithBorder {
+		classes = append(classes, options.BorderClass)
+	}
+
+	if image.WithBackground {
+		classes = append(classes, options.BackgroundClass)
+	}
+
+	return classes
+}
+
+// TableHandler is the default TableHandler for EditorJS HTML generation
+type TableHandler struct{}
+
+// tableBlockTags matches block level html that can't be represented inside of a GFM table cell
+var tableBlockTags = regexp.MustCompile(`(?i)<\s*/?\s*(p|div|ul|ol|li|table|thead|tbody|tfoot|tr|td|th|pre|blockquote|h[1-6]|hr|figure|section|dl|dt|dd)\b`)
+
+func (*TableHandler) parse(editorJSBlock EditorJSBlock) (*table, error) {
+	table := &table{}
+	return table, json.Unmarshal(editorJSBlock.Data, table)
+}
+
+// Type "table"
+func (*TableHandler) Type() string {
+	return "table"
+}
+
+// GenerateHTML generates html for TableBlocks
+func (h *TableHandler) GenerateHTML(editorJSBlock EditorJSBlock) (string, error) {
+	return h.GenerateHTMLContext(context.Background(), editorJSBlock)
+}
+
+// GenerateHTMLContext generates html for TableBlocks, sanitizing the cells with the SanitizePolicy of ctx
+func (h *TableHandler) GenerateHTMLContext(ctx context.Context, editorJSBlock EditorJSBlock) (string, error) {
+	table, err := h.parse(editorJSBlock)
+	if err != nil {
+		return "", err
+	}
+
+	return h.generateHTML(SanitizePolicyFromContext(ctx), table), nil
+}
+
+// GenerateMarkdown generates markdown for TableBlocks
+func (h *TableHandler) GenerateMarkdown(editorJSBlock EditorJSBlock) (string, error) {
+	return h.GenerateMarkdownContext(context.Background(), editorJSBlock)
+}
+
+// GenerateMarkdownContext generates markdown for TableBlocks, converting inline html with the InlineMarkdownOptions of ctx
+func (h *TableHandler) GenerateMarkdownContext(ctx context.Context, editorJSBlock EditorJSBlock) (string, error) {
+	table, err := h.parse(editorJSBlock)
+	if err != nil {
+		return "", err
+	}
+
+	columns := 0
+	for _, row := range table.Content {
+		for _, cell := range row {
+			if tableBlockTags.MatchString(cell) {
+				// GFM tables can only hold inline content, so we'll use html instead.
+				return h.generateHTML(nil, table), nil
+			}
+		}
+		if len(row) > columns {
+			columns = len(row)
+		}
+	}
+
+	if columns == 0 {
+		return "", nil
+	}
+
+	rows := table.Content
+	if !table.WithHeadings {
+		// GFM tables require a header row, so an empty one is used.
+		rows = append([][]string{{}}, rows...)
+	}
+
+	options := InlineMarkdownOptionsFromContext(ctx)
+	results := []string{}
+	for i, row := range rows {
+		results = append(results, markdownTableRow(options, row, columns))
+		if i == 0 {
+			results = append(results, "|"+strings.Repeat(" --- |", columns))
+		}
+	}
+
+	return strings.Join(results, "\n"), nil
+}
+
+// GenerateText generates plain text for TableBlocks, with the cells of a row separated by tabs
+func (h *TableHandler) GenerateText(editorJSBlock EditorJSBlock) (string, error) {
+	table, err := h.parse(editorJSBlock)
+	if err != nil {
+		return "", err
+	}
+
+	rows := []string{}
+	for _, row := range table.Content {
+		cells := []string{}
+		for _, cell := range row {
+			cells = append(cells, strings.ReplaceAll(htmlToText(cell, false), "\n", " "))
+		}
+		rows = append(rows, strings.Join(cells, "\t"))
+	}
+
+	return strings.Join(rows, "\n"), nil
+}
+
+func (h *TableHandler) generateHTML(policy *SanitizePolicy, table *table) string {
+	rows := table.Content
+	head := ""
+	if table.WithHeadings && len(rows) > 0 {
+		head = fmt.Sprintf("<thead>%s</thead>", htmlTableRow(policy, rows[0], "th"))
+		rows = rows[1:]
+	}
+
+	body := ""
+	if len(rows) > 0 {
+		innerData := ""
+		for _, row := range rows {
+			innerData += htmlTableRow(policy, row, "td")
+		}
+		body = fmt.Sprintf("<tbody>%s</tbody>", innerData)
+	}
+
+	return fmt.Sprintf("<table>%s%s</table>", head, body)
+}
+
+func htmlTableRow(policy *SanitizePolicy, row []string, cellTag string) string {
+	innerData := ""
+	for _, cell := range row {
+		innerData += fmt.Sprintf("<%s>%s</%s>", cellTag, policy.Sanitize(cell), cellTag)
+	}
+	return fmt.Sprintf("<tr>%s</tr>", innerData)
+}
+
+func markdownTableRow(options *InlineMarkdownOptions, row []string, columns int) string {
+	result := "|"
+	for i := 0; i < columns; i++ {
+		cell := ""
+		if i < len(row) {
+			cell = escapeMarkdownTableCell(InlineHTMLToMarkdown(row[i], options))
+		}
+		result += fmt.Sprintf(" %s |", cell)
+	}
+	return result
+}
+
+func escapeMarkdownTableCell(cell string) string {
+	cell = strings.ReplaceAll(cell, "\r\n", "\n")
+	cell = strings.ReplaceAll(cell, "\n", "<br>")
+	cell = strings.ReplaceAll(cell, "|", `\|`)
+	return strings.TrimSpace(cell)
+}
+
+// ChecklistHandler is the default ChecklistHandler for EditorJS HTML generation
+type ChecklistHandler struct {
+	// Options are made available to the GenerateHTML function.
+	// If not provided, DefaultChecklistHandlerOptions will be used.
+	Options *ChecklistHandlerOptions
+}
+
+// ChecklistHandlerOptions are the options available to the ChecklistHandler
+type ChecklistHandlerOptions struct {
+	ListClass        string
+	ItemClass        string
+	CheckedItemClass string
+	CheckboxClass    string
+	LabelClass       string
+}
+
+// DefaultChecklistHandlerOptions are the default options available to the ChecklistHandler
+var DefaultChecklistHandlerOptions = &ChecklistHandlerOptions{
+	ListClass:        "cdx-checklist",
+	ItemClass:        "cdx-checklist__item",
+	CheckedItemClass: "cdx-checklist__item--checked",
+	CheckboxClass:    "cdx-checklist__item-checkbox",
+	LabelClass:       "cdx-checklist__item-text"}
+
+func (*ChecklistHandler) parse(editorJSBlock EditorJSBlock) (*checklist, error) {
+	checklist := &checklist{}
+	return checklist, json.Unmarshal(editorJSBlock.Data, checklist)
+}
+
+// Type "checklist"
+func (*ChecklistHandler) Type() string {
+	return "checklist"
+}
+
+func (h *ChecklistHandler) options() *ChecklistHandlerOptions {
+	if h.Options == nil {
+		return DefaultChecklistHandlerOptions
+	}
+	return h.Options
+}
+
+// GenerateHTML generates html for ChecklistBlocks
+func (h *ChecklistHandler) GenerateHTML(editorJSBlock EditorJSBlock) (string, error) {
+	return h.GenerateHTMLContext(context.Background(), editorJSBlock)
+}
+
+// GenerateHTMLContext generates html for ChecklistBlocks, sanitizing the items with the SanitizePolicy of ctx
+func (h *ChecklistHandler) GenerateHTMLContext(ctx context.Context, editorJSBlock EditorJSBlock) (string, error) {
+	checklist, err := h.parse(editorJSBlock)
+	if err != nil {
+		return "", err
+	}
+
+	options := h.options()
+	policy := SanitizePolicyFromContext(ctx)
+
+	innerData := ""
+	for _, item := range checklist.Items {
+		itemClass := options.ItemClass
+		if item.Checked {
+			itemClass = strings.TrimSpace(itemClass + " " + options.CheckedItemClass)
+		}
+		innerData += fmt.Sprintf("<li%s>%s</li>", classAttribute(itemClass),
+			checkboxHTML(policy.Sanitize(item.Text), item.Checked, options.LabelClass, options.CheckboxClass))
+	}
+
+	return fmt.Sprintf("<ul%s>%s</ul>", classAttribute(options.ListClass), innerData), nil
+}
+
+// GenerateMarkdown generates markdown for ChecklistBlocks
+func (h *ChecklistHandler) GenerateMarkdown(editorJSBlock EditorJSBlock) (string, error) {
+	return h.GenerateMarkdownContext(context.Background(), editorJSBlock)
+}
+
+// GenerateMarkdownContext generates markdown for ChecklistBlocks, converting inline html with the InlineMarkdownOptions of ctx
+func (h *ChecklistHandler) GenerateMarkdownContext(ctx context.Context, editorJSBlock EditorJSBlock) (string, error) {
+	checklist, err := h.parse(editorJSBlock)
+	if err != nil {
+		return "", err
+	}
+
+	options := InlineMarkdownOptionsFromContext(ctx)
+	results := []string{}
+	for _, item := range checklist.Items {
+		results = append(results, "- "+checkboxMarkdown(item.Checked)+InlineHTMLToMarkdown(item.Text, options))
+	}
+
+	return strings.Join(results, "\n"), nil
+}
+
+// GenerateText generates plain text for ChecklistBlocks
+func (h *ChecklistHandler) GenerateText(editorJSBlock EditorJSBlock) (string, error) {
+	checklist, err := h.parse(editorJSBlock)
+	if err != nil {
+		return "", err
+	}
+
+	results := []string{}
+	for _, item := range checklist.Items {
+		results = append(results, checkboxMarkdown(item.Checked)+htmlToText(item.Text, false))
+	}
+
+	return strings.Join(results, "\n"), nil
+}
+
+// QuoteHandler is the default QuoteHandler for EditorJS HTML generation
+type QuoteHandler struct{}
+
+// lineBreaks matches the line breaks editor.js places in inline text
+var lineBreaks = regexp.MustCompile(`(?i)<br\s*/?>|\r?\n`)
+
+// markdownLines splits markdown at the line breaks of the inline text. Every line followed by another line
+// ends with a hard line break, as markdown would otherwise join the lines. Empty lines break paragraphs instead.
+func markdownLines(markdown string) []string {
+	lines := lineBreaks.Split(markdown, -1)
+	for i := range lines[:len(lines)-1] {
+		if strings.TrimSpace(lines[i]) != "" && strings.TrimSpace(lines[i+1]) != "" {
+			lines[i] = strings.TrimRight(lines[i], " ") + "\\"
+		}
+	}
+	return lines
+}
+
+func (*QuoteHandler) parse(editorJSBlock EditorJSBlock) (*quote, error) {
+	quote := &quote{}
+	return quote, json.Unmarshal(editorJSBlock.Data, quote)
+}
+
+// Type "quote"
+func (*QuoteHandler) Type() string {
+	return "quote"
+}
+
+// GenerateHTML generates html for QuoteBlocks
+func (h *QuoteHandler) GenerateHTML(editorJSBlock EditorJSBlock) (string, error) {
+	return h.GenerateHTMLContext(context.Background(), editorJSBlock)
+}
+
+// GenerateHTMLContext generates html for QuoteBlocks, sanitizing the text and caption with the SanitizePolicy of ctx
+func (h *QuoteHandler) GenerateHTMLContext(ctx context.Context, editorJSBlock EditorJSBlock) (string, error) {
+	quote, err := h.parse(editorJSBlock)
+	if err != nil {
+		return "", err
+	}
+
+	return h.generateHTML(SanitizePolicyFromContext(ctx), quote), nil
+}
+
+// GenerateMarkdown generates markdown for QuoteBlocks
+func (h *QuoteHandler) GenerateMarkdown(editorJSBlock EditorJSBlock) (string, error) {
+	return h.GenerateMarkdownContext(context.Background(), editorJSBlock)
+}
+
+// GenerateMarkdownContext generates markdown for QuoteBlocks, converting inline html with the InlineMarkdownOptions of ctx
+func (h *QuoteHandler) GenerateMarkdownContext(ctx context.Context, editorJSBlock EditorJSBlock) (string, error) {
+	quote, err := h.parse(editorJSBlock)
+	if err != nil {
+		return "", err
+	}
+
+	if quote.Alignment != "" && quote.Alignment != "left" {
+		// Native markdown doesn't support alignment, so we'll use html instead.
+		return h.generateHTML(nil, quote), nil
+	}
+
+	options := InlineMarkdownOptionsFromContext(ctx)
+	results := []string{}
+	for _, line := range markdownLines(InlineHTMLToMarkdown(quote.Text, options)) {
+		results = append(results, strings.TrimRight("> "+line, " "))
+	}
+
+	if quote.Caption != "" {
+		results = append(results, ">", "> — "+InlineHTMLToMarkdown(quote.Caption, options))
+	}
+
+	return strings.Join(results, "\n"), nil
+}
+
+// GenerateText generates plain text for QuoteBlocks
+func (h *QuoteHandler) GenerateText(editorJSBlock EditorJSBlock) (string, error) {
+	quote, err := h.parse(editorJSBlock)
+	if err != nil {
+		return "", err
+	}
+
+	result := htmlToText(quote.Text, false)
+	if caption := htmlToText(quote.Caption, false); caption != "" {
+		result += "\n— " + caption
+	}
+
+	return result, nil
+}
+
+func (h *QuoteHandler) generateHTML(policy *SanitizePolicy, quote *quote) string {
+	style := ""
+	if quote.Alignment != "" && quote.Alignment != "left" {
+		style = fmt.Sprintf(` style="text-align:%s"`, policy.SanitizeAttribute(quote.Alignment))
+	}
+
+	if quote.Caption == "" {
+		return fmt.Sprintf("<blockquote%s>%s</blockquote>", style, policy.Sanitize(quote.Text))
+	}
+
+	return fmt.Sprintf("<figure%s><blockquote>%s</blockquote><figcaption><cite>%s</cite></figcaption></figure>",
+		style, policy.Sanitize(quote.Text), policy.Sanitize(quote.Caption))
+}
+
+// WarningHandler is the default WarningHandler for EditorJS HTML generation
+type WarningHandler struct {
+	// Options are made available to the GenerateHTML and GenerateMarkdown functions.
+	// If not provided, DefaultWarningHandlerOptions will be used.
+	Options *WarningHandlerOptions
+}
+
+// WarningMarkdownStyle is the admonition syntax used when generating markdown for WarningBlocks
+type WarningMarkdownStyle int
+
+const (
+	// WarningStyleGitHub generates GitHub style alerts, e.g. "> [!WARNING]"
+	WarningStyleGitHub WarningMarkdownStyle = iota
+	// WarningStyleMkDocs generates MkDocs admonitions, e.g. "!!! warning"
+	WarningStyleMkDocs
+	// WarningStyleDocusaurus generates Docusaurus admonitions, e.g. ":::warning"
+	WarningStyleDocusaurus
+)
+
+// WarningHandlerOptions are the options available to the WarningHandler
+type WarningHandlerOptions struct {
+	ContainerClass string
+	TitleClass     string
+	MessageClass   string
+	// Role is the ARIA role of the container. Defaults to "alert" if empty.
+	Role string
+	// Kind is the kind of admonition generated in markdown, e.g. "note", "caution". Defaults to "warning" if empty.
+	Kind          string
+	MarkdownStyle WarningMarkdownStyle
+}
+
+// DefaultWarningHandlerOptions are the default options available to the WarningHandler
+var DefaultWarningHandlerOptions = &WarningHandlerOptions{
+	ContainerClass: "cdx-warning",
+	TitleClass:     "cdx-warning__title",
+	MessageClass:   "cdx-warning__message",
+	Role:           "alert",
+	Kind:           "warning",
+	MarkdownStyle:  WarningStyleGitHub}
+
+func (*WarningHandler) parse(editorJSBlock EditorJSBlock) (*warning, error) {
+	warning := &warning{}
+	return warning, json.Unmarshal(editorJSBlock.Data, warning)
+}
+
+// Type "warning"
+func (*WarningHandler) Type() string {
+	return "warning"
+}
+
+func (h *WarningHandler) options() *WarningHandlerOptions {
+	if h.Options == nil {
+		return DefaultWarningHandlerOptions
+	}
+	return h.Options
+}
+
+// GenerateHTML generates html for WarningBlocks
+func (h *WarningHandler) GenerateHTML(editorJSBlock EditorJSBlock) (string, error) {
+	return h.GenerateHTMLContext(context.Background(), editorJSBlock)
+}
+
+// GenerateHTMLContext generates html for WarningBlocks, sanitizing the title and message with the SanitizePolicy of ctx
+func (h *WarningHandler) GenerateHTMLContext(ctx context.Context, editorJSBlock EditorJSBlock) (string, error) {
+	warning, err := h.parse(editorJSBlock)
+	if err != nil {
+		return "", err
+	}
+
+	options := h.options()
+	role := options.Role
+	if role == "" {
+		role = "alert"
+	}
+
+	policy := SanitizePolicyFromContext(ctx)
+	innerData := ""
+	if warning.Title != "" {
+		innerData += fmt.Sprintf("<p%s>%s</p>", classAttribute(options.TitleClass), policy.Sanitize(warning.Title))
+	}
+	innerData += fmt.Sprintf("<p%s>%s</p>", classAttribute(options.MessageClass), policy.Sanitize(warning.Message))
+
+	return fmt.Sprintf(`<div%s role="%s">%s</div>`, classAttribute(options.ContainerClass), role, innerData), nil
+}
+
+// GenerateMarkdown generates markdown for WarningBlocks
+func (h *WarningHandler) GenerateMarkdown(editorJSBlock EditorJSBlock) (string, error) {
+	return h.GenerateMarkdownContext(context.Background(), editorJSBlock)
+}
+
+// GenerateMarkdownContext generates markdown for WarningBlocks, converting inline html with the InlineMarkdownOptions of ctx
+func (h *WarningHandler) GenerateMarkdownContext(ctx context.Context, editorJSBlock EditorJSBlock) (string, error) {
+	warning, err := h.parse(editorJSBlock)
+	if err != nil {
+		return "", err
+	}
+
+	options := h.options()
+	kind := options.Kind
+	if kind == "" {
+		kind = "warning"
+	}
+
+	inlineOptions := InlineMarkdownOptionsFromContext(ctx)
+	warning.Title = InlineHTMLToMarkdown(warning.Title, inlineOptions)
+	message := markdownLines(InlineHTMLToMarkdown(warning.Message, inlineOptions))
+	results := []string{}
+	switch options.MarkdownStyle {
+	case WarningStyleMkDocs:
+		if warning.Title != "" {
+			results = append(results, fmt.Sprintf(`!!! %s "%s"`, kind, strings.ReplaceAll(warning.Title, `"`, "&quot;")))
+		} else {
+			results = append(results, "!!! "+kind)
+		}
+		for _, line := range message {
+			results = append(results, strings.TrimRight("    "+line, " "))
+		}
+	case WarningStyleDocusaurus:
+		if warning.Title != "" {
+			results = append(results, fmt.Sprintf(":::%s[%s]", kind, warning.Title))
+		} else {
+			results = append(results, ":::"+kind)
+		}
+		results = append(results, "")
+		results = append(results, message...)
+		results = append(results, "", ":::")
+	default:
+		results = append(results, fmt.Sprintf("> [!%s]", strings.ToUpper(kind)))
+		if warning.Title != "" {
+			results = append(results, fmt.Sprintf("> **%s**", warning.Title), ">")
+		}
+		for _, line := range message {
+			results = append(results, strings.TrimRight("> "+line, " "))
+		}
+	}
+
+	return strings.Join(results, "\n"), nil
+}
+
+// GenerateText generates plain text for WarningBlocks
+func (h *WarningHandler) GenerateText(editorJSBlock EditorJSBlock) (string, error) {
+	warning, err := h.parse(editorJSBlock)
+	if err != nil {
+		return "", err
+	}
+
+	return joinNonEmpty("\n", htmlToText(warning.Title, false), htmlToText(warning.Message, false)), nil
+}
+
+// EmbedHandler is the default EmbedHandler for EditorJS HTML generation
+type EmbedHandler struct {
+	// Options are made available to the GenerateHTML and GenerateMarkdown functions.
+	// If not provided, DefaultEmbedHandlerOptions will be used.
+	Options *EmbedHandlerOptions
+}
+
+// EmbedHandlerOptions are the options available to the EmbedHandler
+type EmbedHandlerOptions struct {
+	// Services are used in addition to DefaultEmbedServices, overriding services with the same name.
+	Services map[string]*EmbedService
+	// AllowedServices restricts which services are embedded. If empty, all known services are allowed.
+	AllowedServices []string
+	// RejectUnknown makes unknown or disallowed services return ErrEmbedNotAllowed.
+	// Otherwise they are degraded to a plain link to their source.
+	RejectUnknown bool
+	WrapperClass  string
+	CaptionClass  string
+	// Sandbox is the sandbox attribute given to iframes. The attribute is left off if empty.
+	// Adding allow-same-origin to allow-scripts lets the embedded page remove its own sandbox.
+	Sandbox string
+	// MarkdownThumbnails renders markdown as a linked thumbnail for services that provide one.
+	MarkdownThumbnails bool
+}
+
+// DefaultEmbedHandlerOptions are the default options available to the EmbedHandler
+var DefaultEmbedHandlerOptions = &EmbedHandlerOptions{
+	WrapperClass:       "embed-tool",
+	CaptionClass:       "embed-tool__caption",
+	Sandbox:            "allow-scripts allow-popups allow-presentation",
+	MarkdownThumbnails: true}
+
+// EmbedService describes how the content of an embedded service is rendered
+type EmbedService struct {
+	// Hosts the embed url is allowed to point to. If empty, any host is allowed.
+	Hosts []string
+	// Template renders the embedded content. It is executed with *EmbedData.
+	Template *template.Template
+	// Responsive wraps the content in a container that keeps the aspect ratio of the embed.
+	Responsive bool
+	// Thumbnail returns a thumbnail url used in markdown, or "" if there is none. May be nil.
+	Thumbnail func(data *EmbedData) string
+}
+
+// EmbedData is the data of an embed block, it's made available to EmbedService templates
+type EmbedData struct {
+	Service string `json:"service"`
+	Source  string `json:"source"`
+	Embed   string `json:"embed"`
+	Width   int    `json:"width"`
+	Height  int    `json:"height"`
+	Caption string `json:"caption"`
+	// Sandbox is the sandbox attribute from EmbedHandlerOptions
+	Sandbox string `json:"-"`
+}
+
+var (
+	embedIframeTemplate = template.Must(template.New("iframe").Parse(
+		`<iframe src="{{.Embed}}" width="{{.Width}}" height="{{.Height}}" loading="lazy"{{if .Sandbox}} sandbox="{{.Sandbox}}"{{end}} frameborder="0" allowfullscreen></iframe>`))
+	embedResponsiveIframeTemplate = template.Must(template.New("responsiveIframe").Parse(
+		`<iframe src="{{.Embed}}" width="{{.Width}}" height="{{.Height}}" loading="lazy"{{if .Sandbox}} sandbox="{{.Sandbox}}"{{end}} frameborder="0" allowfullscreen style="position:absolute;top:0;left:0;width:100%;height:100%"></iframe>`))
+	embedScriptTemplate = template.Must(template.New("script").Parse(
+		`<script src="{{.Embed}}"></script>`))
+)
+
+// DefaultEmbedServices are the services known to the EmbedHandler
+var DefaultEmbedServices = map[string]*EmbedService{
+	"youtube": {
+		Hosts:      []string{"www.youtube.com", "youtube.com", "www.youtube-nocookie.com"},
+		Template:   embedResponsiveIframeTemplate,
+		Responsive: true,
+		Thumbnail:  youtubeThumbnail},
+	"vimeo": {
+		Hosts:      []string{"player.vimeo.com"},
+		Template:   embedResponsiveIframeTemplate,
+		Responsive: true},
+	"twitter": {
+		Hosts:    []string{"twitframe.com"},
+		Template: embedIframeTemplate},
+	"codepen": {
+		Hosts:      []string{"codepen.io"},
+		Template:   embedResponsiveIframeTemplate,
+		Responsive: true},
+	"gist": {
+		Hosts:    []string{"gist.github.com"},
+		Template: embedScriptTemplate},
+	"instagram": {
+		Hosts:    []string{"www.instagram.com", "instagram.com"},
+		Template: embedIframeTemplate},
+}
+
+func youtubeThumbnail(data *EmbedData) string {
+	u, err := url.Parse(data.Embed)
+	if err != nil || !strings.HasPrefix(u.Path, "/embed/") {
+		return ""
+	}
+	return fmt.Sprintf("https://img.youtube.com/vi/%s/hqdefault.jpg", url.PathEscape(strings.TrimPrefix(u.Path, "/embed/")))
+}
+
+func (*EmbedHandler) parse(editorJSBlock EditorJSBlock) (*EmbedData, error) {
+	embed := &EmbedData{}
+	return embed, json.Unmarshal(editorJSBlock.Data, embed)
+}
+
+// Type "embed"
+func (*EmbedHandler) Type() string {
+	return "embed"
+}
+
+func (h *EmbedHandler) options() *EmbedHandlerOptions {
+	if h.Options == nil {
+		return DefaultEmbedHandlerOptions
+	}
+	return h.Options
+}
+
+// service returns the EmbedService for the embed, or nil if the service is unknown or not allowed
+func (h *EmbedHandler) service(embed *EmbedData) *EmbedService {
+	options := h.options()
+	if len(options.AllowedServices) > 0 && !containsString(options.AllowedServices, embed.Service) {
+		return nil
+	}
+
+	service, ok := options.Services[embed.Service]
+	if !ok {
+		service, ok = DefaultEmbedServices[embed.Service]
+	}
+	if !ok || service == nil || service.Template == nil {
+		return nil
+	}
+
+	u, err := url.Parse(embed.Embed)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") {
+		return nil
+	}
+	if len(service.Hosts) > 0 && !containsString(service.Hosts, strings.ToLower(u.Hostname())) {
+		return nil
+	}
+
+	return service
+}
+
+// GenerateHTML generates html for EmbedBlocks
+func (h *EmbedHandler) GenerateHTML(editorJSBlock EditorJSBlock) (string, error) {
+	return h.GenerateHTMLContext(context.Background(), editorJSBlock)
+}
+
+// GenerateHTMLContext generates html for EmbedBlocks, sanitizing the caption with the SanitizePolicy of ctx
+func (h *EmbedHandler) GenerateHTMLContext(ctx context.Context, editorJSBlock EditorJSBlock) (string, error) {
+	embed, err := h.parse(editorJSBlock)
+	if err != nil {
+		return "", err
+	}
+
+	options := h.options()
+	embed.Caption = SanitizePolicyFromContext(ctx).Sanitize(embed.Caption)
+	service := h.service(embed)
+	if service == nil {
+		if options.RejectUnknown {
+			return "", fmt.Errorf("%w, Service: %s", ErrEmbedNotAllowed, embed.Service)
+		}
+		return embedLinkHTML(embed), nil
+	}
+
+	embed.Sandbox = options.Sandbox
+	content := &bytes.Buffer{}
+	if err := service.Template.Execute(content, embed); err != nil {
+		return "", err
+	}
+
+	innerData := content.String()
+	if service.Responsive {
+		ratio := 56.25
+		if embed.Width > 0 && embed.Height > 0 {
+			ratio = float64(embed.Height) / float64(embed.Width) * 100
+		}
+		innerData = fmt.Sprintf(`<div style="position:relative;padding-bottom:%.2f%%;height:0;overflow:hidden">%s</div>`, ratio, innerData)
+	}
+
+	if embed.Caption != "" {
+		innerData += fmt.Sprintf("<figcaption%s>%s</figcaption>", classAttribute(options.CaptionClass), embed.Caption)
+	}
+
+	wrapperClass := ""
+	if options.WrapperClass != "" {
+		wrapperClass = options.WrapperClass + " " + options.WrapperClass + "--" + embed.Service
+	}
+
+	return fmt.Sprintf("<figure%s>%s</figure>", classAttribute(wrapperClass), innerData), nil
+}
+
+// GenerateMarkdown generates markdown for EmbedBlocks
+func (h *EmbedHandler) GenerateMarkdown(editorJSBlock EditorJSBlock) (string, error) {
+	embed, err := h.parse(editorJSBlock)
+	if err != nil {
+		return "", err
+	}
+
+	options := h.options()
+	service := h.service(embed)
+	if service == nil && options.RejectUnknown {
+		return "", fmt.Errorf("%w, Service: %s", ErrEmbedNotAllowed, embed.Service)
+	}
+
+	text := InlineHTMLToMarkdown(embed.Caption, nil)
+	if !isSafeLinkURL(embed.Source) {
+		return text, nil
+	}
+
+	if text == "" {
+		text = escapeMarkdownText(embed.Source, false)
+	}
+	source := markdownLinkDestination(embed.Source)
+
+	if service != nil && service.Thumbnail != nil && options.MarkdownThumbnails {
+		if thumbnail := service.Thumbnail(embed); thumbnail != "" {
+			return fmt.Sprintf("[![%s](%s)](%s)", text, markdownLinkDestination(thumbnail), source), nil
+		}
+	}
+
+	return fmt.Sprintf("[%s](%s)", text, source), nil
+}
+
+// GenerateText generates plain text for EmbedBlocks, which is the caption and the source of the embed
+func (h *EmbedHandler) GenerateText(editorJSBlock EditorJSBlock) (string, error) {
+	embed, err := h.parse(editorJSBlock)
+	if err != nil {
+		return "", err
+	}
+
+	source := ""
+	if isSafeLinkURL(embed.Source) {
+		source = embed.Source
+	}
+
+	return joinNonEmpty("\n", htmlToText(embed.Caption, false), source), nil
+}
+
+// embedLinkHTML degrades an embed to a link to its source
+func embedLinkHTML(embed *EmbedData) string {
+	if !isSafeLinkURL(embed.Source) {
+		if embed.Caption == "" {
+			return ""
+		}
+		return fmt.Sprintf("<p>%s</p>", embed.Caption)
+	}
+
+	text := embed.Caption
+	if text == "" {
+		text = html.EscapeString(embed.Source)
+	}
+	return fmt.Sprintf(`<p><a href="%s">%s</a></p>`, html.EscapeString(embed.Source), text)
+}
+
+// isSafeLinkURL reports whether rawURL is an absolute http or https url
+func isSafeLinkURL(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	return err == nil && (u.Scheme == "https" || u.Scheme == "http") && u.Host != ""
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// LinkToolHandler is the default LinkToolHandler for EditorJS HTML generation
+type LinkToolHandler struct {
+	// Options are made available to the GenerateHTML function.
+	// If not provided, DefaultLinkToolHandlerOptions will be used.
+	Options *LinkToolHandlerOptions
+}
+
+// LinkToolHandlerOptions are the options available to the LinkToolHandler
+type LinkToolHandlerOptions struct {
+	CardClass        string
+	ImageClass       string
+	TitleClass       string
+	DescriptionClass string
+	DomainClass      string
+	// Rel is the rel attribute of the link, e.g. "noopener nofollow". The attribute is left off if empty.
+	Rel string
+	// Target is the target attribute of the link, e.g. "_blank". The attribute is left off if empty.
+	Target string
+}
+
+// DefaultLinkToolHandlerOptions are the default options available to the LinkToolHandler
+var DefaultLinkToolHandlerOptions = &LinkToolHandlerOptions{
+	CardClass:        "link-tool__content",
+	ImageClass:       "link-tool__image",
+	TitleClass:       "link-tool__title",
+	DescriptionClass: "link-tool__description",
+	DomainClass:      "link-tool__anchor"}
+
+func (*LinkToolHandler) parse(editorJSBlock EditorJSBlock) (*linkTool, error) {
+	linkTool := &linkTool{}
+	return linkTool, json.Unmarshal(editorJSBlock.Data, linkTool)
+}
+
+// Type "linkTool"
+func (*LinkToolHandler) Type() string {
+	return "linkTool"
+}
+
+func (h *LinkToolHandler) options() *LinkToolHandlerOptions {
+	if h.Options == nil {
+		return DefaultLinkToolHandlerOptions
+	}
+	return h.Options
+}
+
+// GenerateHTML generates html for LinkToolBlocks
+func (h *LinkToolHandler) GenerateHTML(editorJSBlock EditorJSBlock) (string, error) {
+	linkTool, err := h.parse(editorJSBlock)
+	if err != nil {
+		return "", err
+	}
+
+	options := h.options()
+	innerData := ""
+	if isSafeLinkURL(linkTool.Meta.Image.URL) {
+		innerData += fmt.Sprintf(`<img%s src="%s" alt=""/>`, classAttribute(options.ImageClass), html.EscapeString(linkTool.Meta.Image.URL))
+	}
+	innerData += fmt.Sprintf("<div%s>%s</div>", classAttribute(options.TitleClass), html.EscapeString(linkTool.title()))
+	if linkTool.Meta.Description != "" {
+		innerData += fmt.Sprintf("<p%s>%s</p>", classAttribute(options.DescriptionClass), html.EscapeString(linkTool.Meta.Description))
+	}
+	if domain := linkTool.domain(); domain != "" {
+		innerData += fmt.Sprintf("<span%s>%s</span>", classAttribute(options.DomainClass), html.EscapeString(domain))
+	}
+
+	if !isSafeLinkURL(linkTool.Link) {
+		return fmt.Sprintf("<div%s>%s</div>", classAttribute(options.CardClass), innerData), nil
+	}
+
+	attributes := ""
+	if options.Rel != "" {
+		attributes += fmt.Sprintf(` rel="%s"`, html.EscapeString(options.Rel))
+	}
+	if options.Target != "" {
+		attributes += fmt.Sprintf(` target="%s"`, html.EscapeString(options.Target))
+	}
+
+	return fmt.Sprintf(`<a%s href="%s"%s>%s</a>`, classAttribute(options.CardClass), html.EscapeString(linkTool.Link), attributes, innerData), nil
+}
+
+// GenerateMarkdown generates markdown for LinkToolBlocks
+func (h *LinkToolHandler) GenerateMarkdown(editorJSBlock EditorJSBlock) (string, error) {
+	linkTool, err := h.parse(editorJSBlock)
+	if err != nil {
+		return "", err
+	}
+
+	result := linkTool.title()
+	if isSafeLinkURL(linkTool.Link) {
+		result = fmt.Sprintf("[%s](%s)", result, linkTool.Link)
+	}
+
+	if linkTool.Meta.Description != "" {
+		result += "\n\n> " + linkTool.Meta.Description
+	}
+
+	return result, nil
+}
+
+// GenerateText generates plain text for LinkToolBlocks
+func (h *LinkToolHandler) GenerateText(editorJSBlock EditorJSBlock) (string, error) {
+	linkTool, err := h.parse(editorJSBlock)
+	if err != nil {
+		return "", err
+	}
+
+	link := ""
+	if isSafeLinkURL(linkTool.Link) {
+		link = linkTool.Link
+	}
+
+	return joinNonEmpty("\n", linkTool.title(), linkTool.Meta.Description, link), nil
+}
+
+// AttachesHandler is the default AttachesHandler for EditorJS HTML generation
+type AttachesHandler struct {
+	// Options are made available to the GenerateHTML and GenerateMarkdown functions.
+	// If not provided, DefaultAttachesHandlerOptions will be used.
+	Options *AttachesHandlerOptions
+}
+
+// AttachesHandlerOptions are the options available to the AttachesHandler
+type AttachesHandlerOptions struct {
+	CardClass      string
+	ExtensionClass string
+	TitleClass     string
+	SizeClass      string
+	// FileURL, if set, is given the url of the file and returns the url to link to, e.g. a CDN or signed url.
+	FileURL func(url string) string
+	// Locale is used to format file sizes. If not provided, FileSizeLocaleEnglish will be used.
+	Locale *FileSizeLocale
+}
+
+// DefaultAttachesHandlerOptions are the default options available to the AttachesHandler
+var DefaultAttachesHandlerOptions = &AttachesHandlerOptions{
+	CardClass:      "cdx-attaches",
+	ExtensionClass: "cdx-attaches__file-icon",
+	TitleClass:     "cdx-attaches__title",
+	SizeClass:      "cdx-attaches__size"}
+
+// FileSizeLocale describes how file sizes are formatted
+type FileSizeLocale struct {
+	DecimalSeparator string
+	// Units for bytes, kilobytes, megabytes, gigabytes and terabytes
+	Units [5]string
+}
+
+var (
+	// FileSizeLocaleEnglish formats file sizes like "1.5 MB"
+	FileSizeLocaleEnglish = &FileSizeLocale{DecimalSeparator: ".", Units: [5]string{"B", "KB", "MB", "GB", "TB"}}
+	// FileSizeLocaleGerman formats file sizes like "1,5 MB"
+	FileSizeLocaleGerman = &FileSizeLocale{DecimalSeparator: ",", Units: [5]string{"B", "KB", "MB", "GB", "TB"}}
+	// FileSizeLocaleFrench formats file sizes like "1,5 Mo"
+	FileSizeLocaleFrench = &FileSizeLocale{DecimalSeparator: ",", Units: [5]string{"o", "Ko", "Mo", "Go", "To"}}
+)
+
+// FormatFileSize formats a size in bytes as a human readable string, e.g. "1.5 MB".
+// If locale is nil, FileSizeLocaleEnglish will be used.
+func FormatFileSize(size float64, locale *FileSizeLocale) string {
+	if locale == nil {
+		locale = FileSizeLocaleEnglish
+	}
+
+	unit := 0
+	for size >= 1024 && unit < len(locale.Units)-1 {
+		size /= 1024
+		unit++
+	}
+
+	if unit == 0 {
+		return fmt.Sprintf("%d %s", int64(size), locale.Units[unit])
+	}
+
+	formatted := strings.TrimSuffix(strconv.FormatFloat(size, 'f', 1, 64), ".0")
+	return fmt.Sprintf("%s %s", strings.Replace(formatted, ".", locale.DecimalSeparator, 1), locale.Units[unit])
+}
+
+func (*AttachesHandler) parse(editorJSBlock EditorJSBlock) (*attaches, error) {
+	attaches := &attaches{}
+	return attaches, json.Unmarshal(editorJSBlock.Data, attaches)
+}
+
+// Type "attaches"
+func (*AttachesHandler) Type() string {
+	return "attaches"
+}
+
+func (h *AttachesHandler) options() *AttachesHandlerOptions {
+	if h.Options == nil {
+		return DefaultAttachesHandlerOptions
+	}
+	return h.Options
+}
+
+// fileURL returns the configured url for the attached file, or "" if the url isn't safe to link to
+func (h *AttachesHandler) fileURL(attaches *attaches) string {
+	fileURL := attaches.File.URL
+	if h.options().FileURL != nil {
+		fileURL = h.options().FileURL(fileURL)
+	}
+
+	if !isSafeFileURL(fileURL) {
+		return ""
+	}
+	return fileURL
+}
+
+// GenerateHTML generates html for AttachesBlocks
+func (h *AttachesHandler) GenerateHTML(editorJSBlock EditorJSBlock) (string, error) {
+	attaches, err := h.parse(editorJSBlock)
+	if err != nil {
+		return "", err
+	}
+
+	options := h.options()
+	innerData := ""
+	if extension := attaches.extension(); extension != "" {
+		innerData += fmt.Sprintf("<span%s>%s</span>", classAttribute(options.ExtensionClass), html.EscapeString(strings.ToUpper(extension)))
+	}
+
+	title := html.EscapeString(attaches.title())
+	if fileURL := h.fileURL(attaches); fileURL != "" {
+		download := ""
+		if attaches.File.Name != "" {
+			download = fmt.Sprintf(`="%s"`, html.EscapeString(attaches.File.Name))
+		}
+		innerData += fmt.Sprintf(`<a%s href="%s" download%s>%s</a>`, classAttribute(options.TitleClass), html.EscapeString(fileURL), download, title)
+	} else {
+		innerData += fmt.Sprintf("<span%s>%s</span>", classAttribute(options.TitleClass), title)
+	}
+
+	if attaches.File.Size > 0 {
+		innerData += fmt.Sprintf("<span%s>%s</span>", classAttribute(options.SizeClass), FormatFileSize(attaches.File.Size, options.Locale))
+	}
+
+	return fmt.Sprintf("<div%s>%s</div>", classAttribute(options.CardClass), innerData), nil
+}
+
+// GenerateMarkdown generates markdown for AttachesBlocks
+func (h *AttachesHandler) GenerateMarkdown(editorJSBlock EditorJSBlock) (string, error) {
+	attaches, err := h.parse(editorJSBlock)
+	if err != nil {
+		return "", err
+	}
+
+	result := attaches.title()
+	if fileURL := h.fileURL(attaches); fileURL != "" {
+		result = fmt.Sprintf("[%s](%s)", result, fileURL)
+	}
+
+	if attaches.File.Size > 0 {
+		result += fmt.Sprintf(" (%s)", FormatFileSize(attaches.File.Size, h.options().Locale))
+	}
+
+	return result, nil
+}
+
+// GenerateText generates plain text for AttachesBlocks
+func (h *AttachesHandler) GenerateText(editorJSBlock EditorJSBlock) (string, error) {
+	attaches, err := h.parse(editorJSBlock)
+	if err != nil {
+		return "", err
+	}
+
+	result := attaches.title()
+	if attaches.File.Size > 0 {
+		result += fmt.Sprintf(" (%s)", FormatFileSize(attaches.File.Size, h.options().Locale))
+	}
+
+	return result, nil
+}
+
+// isSafeFileURL reports whether rawURL is a relative url or an absolute http or https url
+func isSafeFileURL(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil || rawURL == "" {
+		return false
+	}
+	return u.Scheme == "" || u.Scheme == "https" || u.Scheme == "http"
+}
+
+// DelimiterHandler is the default DelimiterHandler for EditorJS HTML generation
+type DelimiterHandler struct {
+	// Options are made available to the GenerateHTML and GenerateMarkdown functions.
+	// If not provided, DefaultDelimiterHandlerOptions will be used.
+	Options *DelimiterHandlerOptions
+}
+
+// DelimiterHandlerOptions are the options available to the DelimiterHandler
+type DelimiterHandlerOptions struct {
+	// HTML is the html generated for a delimiter, e.g. `<div class="ce-delimiter">***</div>`
+	HTML string
+	// Markdown is the thematic break generated for a delimiter, e.g. "***" or "---".
+	// "***" can never be read as the setext heading underline of a preceding paragraph,
+	// "---" is only safe while it's separated from the paragraph by a blank line.
+	Markdown string
+	// Text is the plain text generated for a delimiter
+	Text string
+}
+
+// DefaultDelimiterHandlerOptions are the default options available to the DelimiterHandler
+var DefaultDelimiterHandlerOptions = &DelimiterHandlerOptions{
+	HTML:     "<hr/>",
+	Markdown: "***",
+	Text:     "***"}
+
+// Type "delimiter"
+func (*DelimiterHandler) Type() string {
+	return "delimiter"
+}
+
+func (h *DelimiterHandler) options() *DelimiterHandlerOptions {
+	if h.Options == nil {
+		return DefaultDelimiterHandlerOptions
+	}
+	return h.Options
+}
+
+// GenerateHTML generates html for DelimiterBlocks
+func (h *DelimiterHandler) GenerateHTML(editorJSBlock EditorJSBlock) (string, error) {
+	if h.options().HTML == "" {
+		return DefaultDelimiterHandlerOptions.HTML, nil
+	}
+	return h.options().HTML, nil
+}
+
+// GenerateMarkdown generates markdown for DelimiterBlocks
+func (h *DelimiterHandler) GenerateMarkdown(editorJSBlock EditorJSBlock) (string, error) {
+	if h.options().Markdown == "" {
+		return DefaultDelimiterHandlerOptions.Markdown, nil
+	}
+	return h.options().Markdown, nil
+}
+
+// GenerateText generates plain text for DelimiterBlocks
+func (h *DelimiterHandler) GenerateText(editorJSBlock EditorJSBlock) (string, error) {
+	if h.options().Text == "" {
+		return DefaultDelimiterHandlerOptions.Text, nil
+	}
+	return h.options().Text, nil
+}
+
+// CodeHandler is the default CodeHandler for EditorJS HTML generation
+type CodeHandler struct {
+	// Options are made available to the GenerateHTML and GenerateMarkdown functions.
+	// If not provided, DefaultCodeHandlerOptions will be used.
+	Options *CodeHandlerOptions
+}
+
+// CodeHandlerOptions are the options available to the CodeHandler
+type CodeHandlerOptions struct {
+	// LanguageField is the field of the block data that holds the language, e.g. "language" or "lang".
+	LanguageField string
+	// ClassPrefix is prepended to the language to form the class of the code element, e.g. "language-".
+	ClassPrefix string
+	// DefaultLanguage is used for blocks that don't have a language.
+	DefaultLanguage string
+}
+
+// DefaultCodeHandlerOptions are the default options available to the CodeHandler
+var DefaultCodeHandlerOptions = &CodeHandlerOptions{
+	LanguageField: "language",
+	ClassPrefix:   "language-"}
+
+func (h *CodeHandler) parse(editorJSBlock EditorJSBlock) (*code, error) {
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(editorJSBlock.Data, &fields); err != nil {
+		return nil, err
+	}
+
+	code := &code{Language: h.options().DefaultLanguage}
+	if raw, ok := fields["code"]; ok {
+		if err := json.Unmarshal(raw, &code.Code); err != nil {
+			return nil, err
+		}
+	}
+
+	if raw, ok := fields[h.options().LanguageField]; ok {
+		language := ""
+		if err := json.Unmarshal(raw, &language); err != nil {
+			return nil, err
+		}
+		if language != "" {
+			code.Language = language
+		}
+	}
+
+	return code, nil
+}
+
+// Type "code"
+func (*CodeHandler) Type() string {
+	return "code"
+}
+
+func (h *CodeHandler) options() *CodeHandlerOptions {
+	if h.Options == nil {
+		return DefaultCodeHandlerOptions
+	}
+	return h.Options
+}
+
+// GenerateHTML generates html for CodeBlocks
+func (h *CodeHandler) GenerateHTML(editorJSBlock EditorJSBlock) (string, error) {
+	code, err := h.parse(editorJSBlock)
+	if err != nil {
+		return "", err
+	}
+
+	class := ""
+	if code.Language != "" {
+		class = classAttribute(h.options().ClassPrefix + html.EscapeString(code.Language))
+	}
+
+	return fmt.Sprintf(`<pre><code%s>%s</code></pre>`, class, html.EscapeString(code.Code)), nil
+}
+
+// GenerateMarkdown generates markdown for CodeBlocks
+func (h *CodeHandler) GenerateMarkdown(editorJSBlock EditorJSBlock) (string, error) {
+	code, err := h.parse(editorJSBlock)
+	if err != nil {
+		return "", err
+	}
+
+	// The fence has to be longer than any run of backticks inside of the code
+	fence := strings.Repeat("`", 3)
+	for _, run := range backtickRuns.FindAllString(code.Code, -1) {
+		if len(run) >= len(fence) {
+			fence = strings.Repeat("`", len(run)+1)
+		}
+	}
+
+	return fmt.Sprintf("%s%s\n%s\n%s", fence, sanitizeCodeLanguage(code.Language), code.Code, fence), nil
+}
+
+// GenerateText generates plain text for CodeBlocks
+func (h *CodeHandler) GenerateText(editorJSBlock EditorJSBlock) (string, error) {
+	code, err := h.parse(editorJSBlock)
+	if err != nil {
+		return "", err
+	}
+
+	return code.Code, nil
+}
+
+var backtickRuns = regexp.MustCompile("`+")
+
+// joinNonEmpty joins the values that aren't empty with sep
+func joinNonEmpty(sep string, values ...string) string {
+	nonEmpty := []string{}
+	for _, value := range values {
+		if value != "" {
+			nonEmpty = append(nonEmpty, value)
+		}
+	}
+	return strings.Join(nonEmpty, sep)
 }
@@ -1,7 +1,10 @@
 package goeditorjs_test
 
 import (
+	"errors"
 	"fmt"
+	"html/template"
+	"strings"
 	"testing"
 
 	"github.com/davidscottmills/goeditorjs"
@@ -195,6 +198,57 @@ func Test_ListHandler_GenerateMarkdown(t *testing.T) {
 	}
 }
 
+func Test_ListHandler_GenerateHTML_Nested(t *testing.T) {
+	blh := &goeditorjs.ListHandler{}
+	testData := []struct {
+		data           string
+		expectedResult string
+	}{
+		{data: `{"style": "unordered", "items": [{"content": "one", "items": [{"content": "one.one", "items": [{"content": "one.one.one", "items": []}]}]}, {"content": "two", "items": []}]}`,
+			expectedResult: "<ul><li>one<ul><li>one.one<ul><li>one.one.one</li></ul></li></ul></li><li>two</li></ul>"},
+		{data: `{"style": "ordered", "meta": {"start": 3, "counterType": "lower-roman"}, "items": [{"content": "three", "meta": {}, "items": [{"content": "nested", "meta": {}, "items": []}]}]}`,
+			expectedResult: `<ol start="3" style="list-style-type:lower-roman"><li>three<ol style="list-style-type:lower-roman"><li>nested</li></ol></li></ol>`},
+		{data: `{"style": "ordered", "meta": {"start": 1, "counterType": "numeric"}, "items": [{"content": "one", "meta": {}, "items": []}]}`,
+			expectedResult: "<ol><li>one</li></ol>"},
+		{data: `{"style": "checklist", "meta": {}, "items": [{"content": "done", "meta": {"checked": true}, "items": []}, {"content": "todo", "meta": {"checked": false}, "items": []}]}`,
+			expectedResult: `<ul><li><label><input type="checkbox" disabled checked/> done</label></li><li><label><input type="checkbox" disabled/> todo</label></li></ul>`},
+	}
+
+	for _, td := range testData {
+		jsonData := []byte(td.data)
+		ejsBlock := goeditorjs.EditorJSBlock{Type: "list", Data: jsonData}
+		html, err := blh.GenerateHTML(ejsBlock)
+		require.NoError(t, err)
+		require.Equal(t, td.expectedResult, html)
+	}
+}
+
+func Test_ListHandler_GenerateMarkdown_Nested(t *testing.T) {
+	blh := &goeditorjs.ListHandler{}
+	testData := []struct {
+		data           string
+		expectedResult string
+	}{
+		{data: `{"style": "unordered", "items": [{"content": "one", "items": [{"content": "one.one", "items": [{"content": "one.one.one", "items": []}]}]}, {"content": "two", "items": []}]}`,
+			expectedResult: "- one\n  - one.one\n    - one.one.one\n- two"},
+		{data: `{"style": "ordered", "meta": {"start": 10}, "items": [{"content": "ten", "meta": {}, "items": [{"content": "nested", "meta": {}, "items": []}]}, {"content": "eleven", "meta": {}, "items": []}]}`,
+			expectedResult: "10. ten\n    1. nested\n10. eleven"},
+		{data: `{"style": "checklist", "meta": {}, "items": [{"content": "done", "meta": {"checked": true}, "items": [{"content": "todo", "meta": {"checked": false}, "items": []}]}]}`,
+			expectedResult: "- [x] done\n  - [ ] todo"},
+		// Non numeric counters fall back to html
+		{data: `{"style": "ordered", "meta": {"counterType": "upper-alpha"}, "items": [{"content": "A", "meta": {}, "items": []}]}`,
+			expectedResult: `<ol style="list-style-type:upper-alpha"><li>A</li></ol>`},
+	}
+
+	for _, td := range testData {
+		jsonData := []byte(td.data)
+		ejsBlock := goeditorjs.EditorJSBlock{Type: "list", Data: jsonData}
+		md, err := blh.GenerateMarkdown(ejsBlock)
+		require.NoError(t, err)
+		require.Equal(t, td.expectedResult, md)
+	}
+}
+
 func Test_CodeBoxHandler_Type(t *testing.T) {
 	h := &goeditorjs.CodeBoxHandler{}
 	require.Equal(t, "codeBox", h.Type())
@@ -363,3 +417,669 @@ func Test_ImageHandler_GenerateMarkdown(t *testing.T) {
 		require.Equal(t, td.expectedResult, result)
 	}
 }
+
+func Test_TableHandler_Type(t *testing.T) {
+	h := &goeditorjs.TableHandler{}
+	require.Equal(t, "table", h.Type())
+}
+
+func Test_TableHandler_GenerateHTML_Returns_Parse_Err(t *testing.T) {
+	h := &goeditorjs.TableHandler{}
+	_, err := h.GenerateHTML(goeditorjs.EditorJSBlock{Type: "table", Data: []byte{}})
+	require.Error(t, err)
+}
+
+func Test_TableHandler_GenerateHTML(t *testing.T) {
+	h := &goeditorjs.TableHandler{}
+	testData := []struct {
+		data           string
+		expectedResult string
+	}{
+		{data: `{"withHeadings": true, "content": [["Name", "Age"], ["Bob", "42"], ["Alice", "<b>37</b>"]]}`,
+			expectedResult: "<table><thead><tr><th>Name</th><th>Age</th></tr></thead><tbody><tr><td>Bob</td><td>42</td></tr><tr><td>Alice</td><td><b>37</b></td></tr></tbody></table>"},
+		{data: `{"withHeadings": false, "content": [["Bob", "42"], ["Alice", "37"]]}`,
+			expectedResult: "<table><tbody><tr><td>Bob</td><td>42</td></tr><tr><td>Alice</td><td>37</td></tr></tbody></table>"},
+		{data: `{"withHeadings": true, "content": [["Name", "Age"]]}`,
+			expectedResult: "<table><thead><tr><th>Name</th><th>Age</th></tr></thead></table>"},
+		{data: `{"content": []}`,
+			expectedResult: "<table></table>"},
+	}
+
+	for _, td := range testData {
+		jsonData := []byte(td.data)
+		ejsBlock := goeditorjs.EditorJSBlock{Type: "table", Data: jsonData}
+		html, _ := h.GenerateHTML(ejsBlock)
+		require.Equal(t, td.expectedResult, html)
+	}
+}
+
+func Test_TableHandler_GenerateMarkdown_Returns_Parse_Err(t *testing.T) {
+	h := &goeditorjs.TableHandler{}
+	_, err := h.GenerateMarkdown(goeditorjs.EditorJSBlock{Type: "table", Data: []byte{}})
+	require.Error(t, err)
+}
+
+func Test_TableHandler_GenerateMarkdown(t *testing.T) {
+	h := &goeditorjs.TableHandler{}
+	testData := []struct {
+		data           string
+		expectedResult string
+	}{
+		{data: `{"withHeadings": true, "content": [["Name", "Age"], ["Bob", "42"], ["Alice", "<b>37</b>"]]}`,
+			expectedResult: "| Name | Age |\n| --- | --- |\n| Bob | 42 |\n| Alice | **37** |"},
+		// No headings
+		{data: `{"withHeadings": false, "content": [["Bob", "42"], ["Alice", "37"]]}`,
+			expectedResult: "|  |  |\n| --- | --- |\n| Bob | 42 |\n| Alice | 37 |"},
+		// Pipes and newlines
+		{data: `{"withHeadings": true, "content": [["a|b", "c"], ["line 1\nline 2", "d"]]}`,
+			expectedResult: "| a\\|b | c |\n| --- | --- |\n| line 1<br>line 2 | d |"},
+		// Ragged rows
+		{data: `{"withHeadings": true, "content": [["a", "b", "c"], ["1"]]}`,
+			expectedResult: "| a | b | c |\n| --- | --- | --- |\n| 1 |  |  |"},
+		// Block content falls back to html
+		{data: `{"withHeadings": false, "content": [["<ul><li>one</li></ul>", "2"]]}`,
+			expectedResult: "<table><tbody><tr><td><ul><li>one</li></ul></td><td>2</td></tr></tbody></table>"},
+		// Empty
+		{data: `{"content": []}`,
+			expectedResult: ""},
+	}
+
+	for _, td := range testData {
+		jsonData := []byte(td.data)
+		ejsBlock := goeditorjs.EditorJSBlock{Type: "table", Data: jsonData}
+		md, _ := h.GenerateMarkdown(ejsBlock)
+		require.Equal(t, td.expectedResult, md)
+	}
+}
+
+func Test_ChecklistHandler_Type(t *testing.T) {
+	h := &goeditorjs.ChecklistHandler{}
+	require.Equal(t, "checklist", h.Type())
+}
+
+func Test_ChecklistHandler_GenerateHTML_Returns_Parse_Err(t *testing.T) {
+	h := &goeditorjs.ChecklistHandler{}
+	_, err := h.GenerateHTML(goeditorjs.EditorJSBlock{Type: "checklist", Data: []byte{}})
+	require.Error(t, err)
+}
+
+func Test_ChecklistHandler_GenerateHTML(t *testing.T) {
+	testData := []struct {
+		handler        *goeditorjs.ChecklistHandler
+		expectedResult string
+	}{
+		// Defaults
+		{handler: &goeditorjs.ChecklistHandler{},
+			expectedResult: `<ul class="cdx-checklist">` +
+				`<li class="cdx-checklist__item cdx-checklist__item--checked"><label class="cdx-checklist__item-text"><input type="checkbox" class="cdx-checklist__item-checkbox" disabled checked/> done</label></li>` +
+				`<li class="cdx-checklist__item"><label class="cdx-checklist__item-text"><input type="checkbox" class="cdx-checklist__item-checkbox" disabled/> todo</label></li>` +
+				`</ul>`},
+		// Custom classes
+		{handler: &goeditorjs.ChecklistHandler{Options: &goeditorjs.ChecklistHandlerOptions{ListClass: "tasks", CheckedItemClass: "done"}},
+			expectedResult: `<ul class="tasks">` +
+				`<li class="done"><label><input type="checkbox" disabled checked/> done</label></li>` +
+				`<li><label><input type="checkbox" disabled/> todo</label></li>` +
+				`</ul>`},
+	}
+
+	for _, td := range testData {
+		jsonData := []byte(`{"items": [{"text": "done", "checked": true}, {"text": "todo", "checked": false}]}`)
+		ejsBlock := goeditorjs.EditorJSBlock{Type: "checklist", Data: jsonData}
+		html, _ := td.handler.GenerateHTML(ejsBlock)
+		require.Equal(t, td.expectedResult, html)
+	}
+}
+
+func Test_ChecklistHandler_GenerateMarkdown_Returns_Parse_Err(t *testing.T) {
+	h := &goeditorjs.ChecklistHandler{}
+	_, err := h.GenerateMarkdown(goeditorjs.EditorJSBlock{Type: "checklist", Data: []byte{}})
+	require.Error(t, err)
+}
+
+func Test_ChecklistHandler_GenerateMarkdown(t *testing.T) {
+	h := &goeditorjs.ChecklistHandler{}
+	jsonData := []byte(`{"items": [{"text": "done", "checked": true}, {"text": "todo", "checked": false}]}`)
+	ejsBlock := goeditorjs.EditorJSBlock{Type: "checklist", Data: jsonData}
+	md, _ := h.GenerateMarkdown(ejsBlock)
+	require.Equal(t, "- [x] done\n- [ ] todo", md)
+}
+
+func Test_QuoteHandler_Type(t *testing.T) {
+	h := &goeditorjs.QuoteHandler{}
+	require.Equal(t, "quote", h.Type())
+}
+
+func Test_QuoteHandler_GenerateHTML_Returns_Parse_Err(t *testing.T) {
+	h := &goeditorjs.QuoteHandler{}
+	_, err := h.GenerateHTML(goeditorjs.EditorJSBlock{Type: "quote", Data: []byte{}})
+	require.Error(t, err)
+}
+
+func Test_QuoteHandler_GenerateHTML(t *testing.T) {
+	h := &goeditorjs.QuoteHandler{}
+	testData := []struct {
+		data           string
+		expectedResult string
+	}{
+		{data: `{"text": "Quote", "caption": "", "alignment": "left"}`,
+			expectedResult: "<blockquote>Quote</blockquote>"},
+		{data: `{"text": "Quote", "caption": "Author", "alignment": "left"}`,
+			expectedResult: "<figure><blockquote>Quote</blockquote><figcaption><cite>Author</cite></figcaption></figure>"},
+		{data: `{"text": "Quote", "caption": "", "alignment": "center"}`,
+			expectedResult: `<blockquote style="text-align:center">Quote</blockquote>`},
+		{data: `{"text": "Quote", "caption": "Author", "alignment": "center"}`,
+			expectedResult: `<figure style="text-align:center"><blockquote>Quote</blockquote><figcaption><cite>Author</cite></figcaption></figure>`},
+	}
+
+	for _, td := range testData {
+		jsonData := []byte(td.data)
+		ejsBlock := goeditorjs.EditorJSBlock{Type: "quote", Data: jsonData}
+		html, _ := h.GenerateHTML(ejsBlock)
+		require.Equal(t, td.expectedResult, html)
+	}
+}
+
+func Test_QuoteHandler_GenerateMarkdown_Returns_Parse_Err(t *testing.T) {
+	h := &goeditorjs.QuoteHandler{}
+	_, err := h.GenerateMarkdown(goeditorjs.EditorJSBlock{Type: "quote", Data: []byte{}})
+	require.Error(t, err)
+}
+
+func Test_QuoteHandler_GenerateMarkdown(t *testing.T) {
+	h := &goeditorjs.QuoteHandler{}
+	testData := []struct {
+		data           string
+		expectedResult string
+	}{
+		{data: `{"text": "Quote", "caption": "", "alignment": "left"}`,
+			expectedResult: "> Quote"},
+		{data: `{"text": "Line 1<br>Line 2<br/><br>Line 4", "caption": "Author", "alignment": "left"}`,
+			expectedResult: "> Line 1\\\n> Line 2\n>\n> Line 4\n>\n> — Author"},
+		{data: `{"text": "line one<br>line two \\<br>line three", "caption": "", "alignment": "left"}`,
+			expectedResult: "> line one\\\n> line two \\\\\\\n> line three"},
+		{data: `{"text": "Quote", "caption": "Author", "alignment": "center"}`,
+			expectedResult: `<figure style="text-align:center"><blockquote>Quote</blockquote><figcaption><cite>Author</cite></figcaption></figure>`},
+	}
+
+	for _, td := range testData {
+		jsonData := []byte(td.data)
+		ejsBlock := goeditorjs.EditorJSBlock{Type: "quote", Data: jsonData}
+		md, _ := h.GenerateMarkdown(ejsBlock)
+		require.Equal(t, td.expectedResult, md)
+	}
+}
+
+func Test_WarningHandler_Type(t *testing.T) {
+	h := &goeditorjs.WarningHandler{}
+	require.Equal(t, "warning", h.Type())
+}
+
+func Test_WarningHandler_GenerateHTML_Returns_Parse_Err(t *testing.T) {
+	h := &goeditorjs.WarningHandler{}
+	_, err := h.GenerateHTML(goeditorjs.EditorJSBlock{Type: "warning", Data: []byte{}})
+	require.Error(t, err)
+}
+
+func Test_WarningHandler_GenerateHTML(t *testing.T) {
+	testData := []struct {
+		handler        *goeditorjs.WarningHandler
+		data           string
+		expectedResult string
+	}{
+		{handler: &goeditorjs.WarningHandler{},
+			data:           `{"title": "Note", "message": "Be careful"}`,
+			expectedResult: `<div class="cdx-warning" role="alert"><p class="cdx-warning__title">Note</p><p class="cdx-warning__message">Be careful</p></div>`},
+		{handler: &goeditorjs.WarningHandler{},
+			data:           `{"title": "", "message": "Be careful"}`,
+			expectedResult: `<div class="cdx-warning" role="alert"><p class="cdx-warning__message">Be careful</p></div>`},
+		{handler: &goeditorjs.WarningHandler{Options: &goeditorjs.WarningHandlerOptions{ContainerClass: "callout", Role: "note"}},
+			data:           `{"title": "Note", "message": "Be careful"}`,
+			expectedResult: `<div class="callout" role="note"><p>Note</p><p>Be careful</p></div>`},
+	}
+
+	for _, td := range testData {
+		jsonData := []byte(td.data)
+		ejsBlock := goeditorjs.EditorJSBlock{Type: "warning", Data: jsonData}
+		html, _ := td.handler.GenerateHTML(ejsBlock)
+		require.Equal(t, td.expectedResult, html)
+	}
+}
+
+func Test_WarningHandler_GenerateMarkdown_Returns_Parse_Err(t *testing.T) {
+	h := &goeditorjs.WarningHandler{}
+	_, err := h.GenerateMarkdown(goeditorjs.EditorJSBlock{Type: "warning", Data: []byte{}})
+	require.Error(t, err)
+}
+
+func Test_WarningHandler_GenerateMarkdown(t *testing.T) {
+	testData := []struct {
+		handler        *goeditorjs.WarningHandler
+		data           string
+		expectedResult string
+	}{
+		{handler: &goeditorjs.WarningHandler{},
+			data:           `{"title": "Note", "message": "Be careful<br>Really"}`,
+			expectedResult: "> [!WARNING]\n> **Note**\n>\n> Be careful\\\n> Really"},
+		{handler: &goeditorjs.WarningHandler{},
+			data:           `{"title": "", "message": "Be careful"}`,
+			expectedResult: "> [!WARNING]\n> Be careful"},
+		{handler: &goeditorjs.WarningHandler{Options: &goeditorjs.WarningHandlerOptions{MarkdownStyle: goeditorjs.WarningStyleMkDocs}},
+			data:           `{"title": "Say \"hi\"", "message": "Be careful<br>Really"}`,
+			expectedResult: "!!! warning \"Say &quot;hi&quot;\"\n    Be careful\\\n    Really"},
+		{handler: &goeditorjs.WarningHandler{Options: &goeditorjs.WarningHandlerOptions{MarkdownStyle: goeditorjs.WarningStyleMkDocs, Kind: "danger"}},
+			data:           `{"title": "", "message": "Be careful"}`,
+			expectedResult: "!!! danger\n    Be careful"},
+		{handler: &goeditorjs.WarningHandler{Options: &goeditorjs.WarningHandlerOptions{MarkdownStyle: goeditorjs.WarningStyleDocusaurus}},
+			data:           `{"title": "Note", "message": "Be careful<br>Really<br><br>Truly"}`,
+			expectedResult: ":::warning[Note]\n\nBe careful\\\nReally\n\nTruly\n\n:::"},
+		{handler: &goeditorjs.WarningHandler{Options: &goeditorjs.WarningHandlerOptions{MarkdownStyle: goeditorjs.WarningStyleDocusaurus, Kind: "caution"}},
+			data:           `{"title": "", "message": "Be careful"}`,
+			expectedResult: ":::caution\n\nBe careful\n\n:::"},
+	}
+
+	for _, td := range testData {
+		jsonData := []byte(td.data)
+		ejsBlock := goeditorjs.EditorJSBlock{Type: "warning", Data: jsonData}
+		md, _ := td.handler.GenerateMarkdown(ejsBlock)
+		require.Equal(t, td.expectedResult, md)
+	}
+}
+
+func Test_EmbedHandler_Type(t *testing.T) {
+	h := &goeditorjs.EmbedHandler{}
+	require.Equal(t, "embed", h.Type())
+}
+
+func Test_EmbedHandler_GenerateHTML_Returns_Parse_Err(t *testing.T) {
+	h := &goeditorjs.EmbedHandler{}
+	_, err := h.GenerateHTML(goeditorjs.EditorJSBlock{Type: "embed", Data: []byte{}})
+	require.Error(t, err)
+}
+
+func Test_EmbedHandler_GenerateHTML(t *testing.T) {
+	custom := &goeditorjs.EmbedService{
+		Hosts:    []string{"example.com"},
+		Template: template.Must(template.New("custom").Parse(`<video src="{{.Embed}}"></video>`))}
+
+	testData := []struct {
+		handler        *goeditorjs.EmbedHandler
+		data           string
+		expectedResult string
+	}{
+		// Responsive iframe with caption
+		{handler: &goeditorjs.EmbedHandler{},
+			data: `{"service": "youtube", "source": "https://www.youtube.com/watch?v=abc", "embed": "https://www.youtube.com/embed/abc", "width": 400, "height": 300, "caption": "Video"}`,
+			expectedResult: `<figure class="embed-tool embed-tool--youtube"><div style="position:relative;padding-bottom:75.00%;height:0;overflow:hidden">` +
+				`<iframe src="https://www.youtube.com/embed/abc" width="400" height="300" loading="lazy" sandbox="allow-scripts allow-popups allow-presentation" frameborder="0" allowfullscreen style="position:absolute;top:0;left:0;width:100%;height:100%"></iframe>` +
+				`</div><figcaption class="embed-tool__caption">Video</figcaption></figure>`},
+		// Non responsive without sandbox
+		{handler: &goeditorjs.EmbedHandler{Options: &goeditorjs.EmbedHandlerOptions{}},
+			data:           `{"service": "twitter", "source": "https://twitter.com/user/status/1", "embed": "https://twitframe.com/show?url=https://twitter.com/user/status/1", "width": 600, "height": 300, "caption": ""}`,
+			expectedResult: `<figure><iframe src="https://twitframe.com/show?url=https://twitter.com/user/status/1" width="600" height="300" loading="lazy" frameborder="0" allowfullscreen></iframe></figure>`},
+		// Registered service
+		{handler: &goeditorjs.EmbedHandler{Options: &goeditorjs.EmbedHandlerOptions{Services: map[string]*goeditorjs.EmbedService{"custom": custom}}},
+			data:           `{"service": "custom", "source": "https://example.com/v/1", "embed": "https://example.com/v/1.mp4", "width": 600, "height": 300, "caption": ""}`,
+			expectedResult: `<figure><video src="https://example.com/v/1.mp4"></video></figure>`},
+		// Host not allowed for the service degrades to a link
+		{handler: &goeditorjs.EmbedHandler{},
+			data:           `{"service": "youtube", "source": "https://www.youtube.com/watch?v=abc", "embed": "https://evil.com/embed/abc", "width": 400, "height": 300, "caption": ""}`,
+			expectedResult: `<p><a href="https://www.youtube.com/watch?v=abc">https://www.youtube.com/watch?v=abc</a></p>`},
+		// Service not in the allowlist degrades to a link
+		{handler: &goeditorjs.EmbedHandler{Options: &goeditorjs.EmbedHandlerOptions{AllowedServices: []string{"vimeo"}}},
+			data:           `{"service": "youtube", "source": "https://www.youtube.com/watch?v=abc", "embed": "https://www.youtube.com/embed/abc", "width": 400, "height": 300, "caption": "Video"}`,
+			expectedResult: `<p><a href="https://www.youtube.com/watch?v=abc">Video</a></p>`},
+		// Unsafe source
+		{handler: &goeditorjs.EmbedHandler{},
+			data:           `{"service": "unknown", "source": "javascript:alert(1)", "embed": "javascript:alert(1)", "width": 400, "height": 300, "caption": ""}`,
+			expectedResult: ``},
+	}
+
+	for _, td := range testData {
+		jsonData := []byte(td.data)
+		ejsBlock := goeditorjs.EditorJSBlock{Type: "embed", Data: jsonData}
+		html, err := td.handler.GenerateHTML(ejsBlock)
+		require.NoError(t, err)
+		require.Equal(t, td.expectedResult, html)
+	}
+}
+
+func Test_EmbedHandler_GenerateHTML_Rejects_Unknown(t *testing.T) {
+	h := &goeditorjs.EmbedHandler{Options: &goeditorjs.EmbedHandlerOptions{RejectUnknown: true}}
+	jsonData := []byte(`{"service": "unknown", "source": "https://example.com", "embed": "https://example.com", "width": 400, "height": 300, "caption": ""}`)
+	_, err := h.GenerateHTML(goeditorjs.EditorJSBlock{Type: "embed", Data: jsonData})
+	require.True(t, errors.Is(err, goeditorjs.ErrEmbedNotAllowed))
+	_, err = h.GenerateMarkdown(goeditorjs.EditorJSBlock{Type: "embed", Data: jsonData})
+	require.True(t, errors.Is(err, goeditorjs.ErrEmbedNotAllowed))
+}
+
+func Test_EmbedHandler_GenerateMarkdown_Returns_Parse_Err(t *testing.T) {
+	h := &goeditorjs.EmbedHandler{}
+	_, err := h.GenerateMarkdown(goeditorjs.EditorJSBlock{Type: "embed", Data: []byte{}})
+	require.Error(t, err)
+}
+
+func Test_EmbedHandler_GenerateMarkdown(t *testing.T) {
+	testData := []struct {
+		handler        *goeditorjs.EmbedHandler
+		data           string
+		expectedResult string
+	}{
+		{handler: &goeditorjs.EmbedHandler{},
+			data:           `{"service": "youtube", "source": "https://www.youtube.com/watch?v=abc", "embed": "https://www.youtube.com/embed/abc", "width": 400, "height": 300, "caption": "Video"}`,
+			expectedResult: `[![Video](https://img.youtube.com/vi/abc/hqdefault.jpg)](https://www.youtube.com/watch?v=abc)`},
+		{handler: &goeditorjs.EmbedHandler{Options: &goeditorjs.EmbedHandlerOptions{}},
+			data:           `{"service": "youtube", "source": "https://www.youtube.com/watch?v=abc", "embed": "https://www.youtube.com/embed/abc", "width": 400, "height": 300, "caption": "Video"}`,
+			expectedResult: `[Video](https://www.youtube.com/watch?v=abc)`},
+		{handler: &goeditorjs.EmbedHandler{},
+			data:           `{"service": "vimeo", "source": "https://vimeo.com/1", "embed": "https://player.vimeo.com/video/1", "width": 400, "height": 300, "caption": ""}`,
+			expectedResult: `[https://vimeo.com/1](https://vimeo.com/1)`},
+		{handler: &goeditorjs.EmbedHandler{},
+			data:           `{"service": "unknown", "source": "javascript:alert(1)", "embed": "javascript:alert(1)", "width": 400, "height": 300, "caption": ""}`,
+			expectedResult: ``},
+		{handler: &goeditorjs.EmbedHandler{},
+			data:           `{"service": "unknown", "source": "https://example.com/a (b)", "embed": "https://example.com/a", "width": 400, "height": 300, "caption": "<b>x</b>](javascript:alert(1))"}`,
+			expectedResult: `[**x**\](javascript:alert(1))](<https://example.com/a (b)>)`},
+		{handler: &goeditorjs.EmbedHandler{},
+			data:           `{"service": "unknown", "source": "https://example.com/a_b", "embed": "https://example.com/a", "width": 400, "height": 300, "caption": ""}`,
+			expectedResult: `[https://example.com/a\_b](https://example.com/a_b)`},
+	}
+
+	for _, td := range testData {
+		jsonData := []byte(td.data)
+		ejsBlock := goeditorjs.EditorJSBlock{Type: "embed", Data: jsonData}
+		md, err := td.handler.GenerateMarkdown(ejsBlock)
+		require.NoError(t, err)
+		require.Equal(t, td.expectedResult, md)
+	}
+}
+
+func Test_LinkToolHandler_Type(t *testing.T) {
+	h := &goeditorjs.LinkToolHandler{}
+	require.Equal(t, "linkTool", h.Type())
+}
+
+func Test_LinkToolHandler_GenerateHTML_Returns_Parse_Err(t *testing.T) {
+	h := &goeditorjs.LinkToolHandler{}
+	_, err := h.GenerateHTML(goeditorjs.EditorJSBlock{Type: "linkTool", Data: []byte{}})
+	require.Error(t, err)
+}
+
+func Test_LinkToolHandler_GenerateHTML(t *testing.T) {
+	testData := []struct {
+		handler        *goeditorjs.LinkToolHandler
+		data           string
+		expectedResult string
+	}{
+		// Full test with defaults
+		{handler: &goeditorjs.LinkToolHandler{},
+			data: `{"link": "https://codex.so/about", "meta": {"title": "CodeX & co", "description": "Club of web-development", "site_name": "CodeX", "image": {"url": "https://codex.so/public/app/img/meta_img.png"}}}`,
+			expectedResult: `<a class="link-tool__content" href="https://codex.so/about">` +
+				`<img class="link-tool__image" src="https://codex.so/public/app/img/meta_img.png" alt=""/>` +
+				`<div class="link-tool__title">CodeX &amp; co</div>` +
+				`<p class="link-tool__description">Club of web-development</p>` +
+				`<span class="link-tool__anchor">CodeX</span></a>`},
+		// No meta, rel and target
+		{handler: &goeditorjs.LinkToolHandler{Options: &goeditorjs.LinkToolHandlerOptions{Rel: "noopener nofollow", Target: "_blank"}},
+			data:           `{"link": "https://codex.so/about", "meta": {}}`,
+			expectedResult: `<a href="https://codex.so/about" rel="noopener nofollow" target="_blank"><div>https://codex.so/about</div><span>codex.so</span></a>`},
+		// Unsafe link
+		{handler: &goeditorjs.LinkToolHandler{Options: &goeditorjs.LinkToolHandlerOptions{}},
+			data:           `{"link": "javascript:alert(1)", "meta": {"title": "Title"}}`,
+			expectedResult: `<div><div>Title</div></div>`},
+	}
+
+	for _, td := range testData {
+		jsonData := []byte(td.data)
+		ejsBlock := goeditorjs.EditorJSBlock{Type: "linkTool", Data: jsonData}
+		html, _ := td.handler.GenerateHTML(ejsBlock)
+		require.Equal(t, td.expectedResult, html)
+	}
+}
+
+func Test_LinkToolHandler_GenerateMarkdown_Returns_Parse_Err(t *testing.T) {
+	h := &goeditorjs.LinkToolHandler{}
+	_, err := h.GenerateMarkdown(goeditorjs.EditorJSBlock{Type: "linkTool", Data: []byte{}})
+	require.Error(t, err)
+}
+
+func Test_LinkToolHandler_GenerateMarkdown(t *testing.T) {
+	h := &goeditorjs.LinkToolHandler{}
+	testData := []struct {
+		data           string
+		expectedResult string
+	}{
+		{data: `{"link": "https://codex.so/about", "meta": {"title": "CodeX", "description": "Club of web-development"}}`,
+			expectedResult: "[CodeX](https://codex.so/about)\n\n> Club of web-development"},
+		{data: `{"link": "https://codex.so/about", "meta": {}}`,
+			expectedResult: "[https://codex.so/about](https://codex.so/about)"},
+	}
+
+	for _, td := range testData {
+		jsonData := []byte(td.data)
+		ejsBlock := goeditorjs.EditorJSBlock{Type: "linkTool", Data: jsonData}
+		md, _ := h.GenerateMarkdown(ejsBlock)
+		require.Equal(t, td.expectedResult, md)
+	}
+}
+
+func Test_AttachesHandler_Type(t *testing.T) {
+	h := &goeditorjs.AttachesHandler{}
+	require.Equal(t, "attaches", h.Type())
+}
+
+func Test_AttachesHandler_GenerateHTML_Returns_Parse_Err(t *testing.T) {
+	h := &goeditorjs.AttachesHandler{}
+	_, err := h.GenerateHTML(goeditorjs.EditorJSBlock{Type: "attaches", Data: []byte{}})
+	require.Error(t, err)
+}
+
+func Test_AttachesHandler_GenerateHTML(t *testing.T) {
+	testData := []struct {
+		handler        *goeditorjs.AttachesHandler
+		data           string
+		expectedResult string
+	}{
+		// Full test with defaults
+		{handler: &goeditorjs.AttachesHandler{},
+			data: `{"file": {"url": "https://example.com/report.pdf", "name": "report.pdf", "size": 1572864, "extension": "pdf"}, "title": "Report"}`,
+			expectedResult: `<div class="cdx-attaches"><span class="cdx-attaches__file-icon">PDF</span>` +
+				`<a class="cdx-attaches__title" href="https://example.com/report.pdf" download="report.pdf">Report</a>` +
+				`<span class="cdx-attaches__size">1.5 MB</span></div>`},
+		// Url hook, locale and extension from the name
+		{handler: &goeditorjs.AttachesHandler{Options: &goeditorjs.AttachesHandlerOptions{
+			FileURL: func(url string) string {
+				return strings.Replace(url, "https://example.com", "https://cdn.example.com", 1)
+			},
+			Locale: goeditorjs.FileSizeLocaleFrench}},
+			data:           `{"file": {"url": "https://example.com/notes.txt", "name": "notes.txt", "size": 2048}, "title": ""}`,
+			expectedResult: `<div><span>TXT</span><a href="https://cdn.example.com/notes.txt" download="notes.txt">notes.txt</a><span>2 Ko</span></div>`},
+		// Unsafe url
+		{handler: &goeditorjs.AttachesHandler{Options: &goeditorjs.AttachesHandlerOptions{}},
+			data:           `{"file": {"url": "javascript:alert(1)"}, "title": "<b>Title</b>"}`,
+			expectedResult: `<div><span>&lt;b&gt;Title&lt;/b&gt;</span></div>`},
+	}
+
+	for _, td := range testData {
+		jsonData := []byte(td.data)
+		ejsBlock := goeditorjs.EditorJSBlock{Type: "attaches", Data: jsonData}
+		html, _ := td.handler.GenerateHTML(ejsBlock)
+		require.Equal(t, td.expectedResult, html)
+	}
+}
+
+func Test_AttachesHandler_GenerateMarkdown_Returns_Parse_Err(t *testing.T) {
+	h := &goeditorjs.AttachesHandler{}
+	_, err := h.GenerateMarkdown(goeditorjs.EditorJSBlock{Type: "attaches", Data: []byte{}})
+	require.Error(t, err)
+}
+
+func Test_AttachesHandler_GenerateMarkdown(t *testing.T) {
+	h := &goeditorjs.AttachesHandler{}
+	testData := []struct {
+		data           string
+		expectedResult string
+	}{
+		{data: `{"file": {"url": "https://example.com/report.pdf", "name": "report.pdf", "size": 1572864, "extension": "pdf"}, "title": "Report"}`,
+			expectedResult: "[Report](https://example.com/report.pdf) (1.5 MB)"},
+		{data: `{"file": {"url": "/uploads/report.pdf", "name": "report.pdf"}, "title": ""}`,
+			expectedResult: "[report.pdf](/uploads/report.pdf)"},
+	}
+
+	for _, td := range testData {
+		jsonData := []byte(td.data)
+		ejsBlock := goeditorjs.EditorJSBlock{Type: "attaches", Data: jsonData}
+		md, _ := h.GenerateMarkdown(ejsBlock)
+		require.Equal(t, td.expectedResult, md)
+	}
+}
+
+func Test_FormatFileSize(t *testing.T) {
+	testData := []struct {
+		size           float64
+		locale         *goeditorjs.FileSizeLocale
+		expectedResult string
+	}{
+		{size: 0, expectedResult: "0 B"},
+		{size: 512, expectedResult: "512 B"},
+		{size: 1024, expectedResult: "1 KB"},
+		{size: 1536, expectedResult: "1.5 KB"},
+		{size: 1536, locale: goeditorjs.FileSizeLocaleGerman, expectedResult: "1,5 KB"},
+		{size: 3 * 1024 * 1024 * 1024, expectedResult: "3 GB"},
+		{size: 2048 * 1024 * 1024 * 1024 * 1024, expectedResult: "2048 TB"},
+	}
+
+	for _, td := range testData {
+		require.Equal(t, td.expectedResult, goeditorjs.FormatFileSize(td.size, td.locale))
+	}
+}
+
+func Test_DelimiterHandler_Type(t *testing.T) {
+	h := &goeditorjs.DelimiterHandler{}
+	require.Equal(t, "delimiter", h.Type())
+}
+
+func Test_DelimiterHandler_GenerateHTML(t *testing.T) {
+	testData := []struct {
+		handler        *goeditorjs.DelimiterHandler
+		expectedResult string
+	}{
+		{handler: &goeditorjs.DelimiterHandler{}, expectedResult: "<hr/>"},
+		{handler: &goeditorjs.DelimiterHandler{Options: &goeditorjs.DelimiterHandlerOptions{HTML: `<div class="ce-delimiter">***</div>`}},
+			expectedResult: `<div class="ce-delimiter">***</div>`},
+		{handler: &goeditorjs.DelimiterHandler{Options: &goeditorjs.DelimiterHandlerOptions{Markdown: "---"}}, expectedResult: "<hr/>"},
+	}
+
+	for _, td := range testData {
+		ejsBlock := goeditorjs.EditorJSBlock{Type: "delimiter", Data: []byte(`{}`)}
+		html, _ := td.handler.GenerateHTML(ejsBlock)
+		require.Equal(t, td.expectedResult, html)
+	}
+}
+
+func Test_DelimiterHandler_GenerateMarkdown(t *testing.T) {
+	testData := []struct {
+		handler        *goeditorjs.DelimiterHandler
+		expectedResult string
+	}{
+		{handler: &goeditorjs.DelimiterHandler{}, expectedResult: "***"},
+		{handler: &goeditorjs.DelimiterHandler{Options: &goeditorjs.DelimiterHandlerOptions{Markdown: "---"}}, expectedResult: "---"},
+	}
+
+	for _, td := range testData {
+		ejsBlock := goeditorjs.EditorJSBlock{Type: "delimiter", Data: []byte(`{}`)}
+		md, _ := td.handler.GenerateMarkdown(ejsBlock)
+		require.Equal(t, td.expectedResult, md)
+	}
+}
+
+func Test_CodeHandler_Type(t *testing.T) {
+	h := &goeditorjs.CodeHandler{}
+	require.Equal(t, "code", h.Type())
+}
+
+func Test_CodeHandler_GenerateHTML_Returns_Parse_Err(t *testing.T) {
+	h := &goeditorjs.CodeHandler{}
+	_, err := h.GenerateHTML(goeditorjs.EditorJSBlock{Type: "code", Data: []byte{}})
+	require.Error(t, err)
+}
+
+func Test_CodeHandler_GenerateHTML(t *testing.T) {
+	testData := []struct {
+		handler        *goeditorjs.CodeHandler
+		data           string
+		expectedResult string
+	}{
+		{handler: &goeditorjs.CodeHandler{},
+			data:           `{"code": "if a < b && c {\n}"}`,
+			expectedResult: "<pre><code>if a &lt; b &amp;&amp; c {\n}</code></pre>"},
+		{handler: &goeditorjs.CodeHandler{},
+			data:           `{"code": "package main", "language": "go"}`,
+			expectedResult: `<pre><code class="language-go">package main</code></pre>`},
+		{handler: &goeditorjs.CodeHandler{Options: &goeditorjs.CodeHandlerOptions{LanguageField: "lang", ClassPrefix: "lang-"}},
+			data:           `{"code": "package main", "lang": "go"}`,
+			expectedResult: `<pre><code class="lang-go">package main</code></pre>`},
+		{handler: &goeditorjs.CodeHandler{Options: &goeditorjs.CodeHandlerOptions{LanguageField: "language", ClassPrefix: "language-", DefaultLanguage: "plaintext"}},
+			data:           `{"code": "text"}`,
+			expectedResult: `<pre><code class="language-plaintext">text</code></pre>`},
+	}
+
+	for _, td := range testData {
+		jsonData := []byte(td.data)
+		ejsBlock := goeditorjs.EditorJSBlock{Type: "code", Data: jsonData}
+		html, _ := td.handler.GenerateHTML(ejsBlock)
+		require.Equal(t, td.expectedResult, html)
+	}
+}
+
+func Test_CodeHandler_GenerateMarkdown_Returns_Parse_Err(t *testing.T) {
+	h := &goeditorjs.CodeHandler{}
+	_, err := h.GenerateMarkdown(goeditorjs.EditorJSBlock{Type: "code", Data: []byte{}})
+	require.Error(t, err)
+}
+
+func Test_CodeHandler_GenerateMarkdown(t *testing.T) {
+	h := &goeditorjs.CodeHandler{}
+	testData := []struct {
+		data           string
+		expectedResult string
+	}{
+		{data: `{"code": "fmt.Println(\"Hello World\")", "language": "go"}`,
+			expectedResult: "```go\nfmt.Println(\"Hello World\")\n```"},
+		{data: `{"code": "Use ` + "``" + ` for inline code"}`,
+			expectedResult: "```\nUse `` for inline code\n```"},
+		{data: `{"code": "` + "```" + `go\n` + "```" + `"}`,
+			expectedResult: "````\n```go\n```\n````"},
+		{data: `{"code": "x", "language": "go` + "```" + ` x"}`, expectedResult: "```gox\nx\n```"},
+	}
+
+	for _, td := range testData {
+		jsonData := []byte(td.data)
+		ejsBlock := goeditorjs.EditorJSBlock{Type: "code", Data: jsonData}
+		md, _ := h.GenerateMarkdown(ejsBlock)
+		require.Equal(t, td.expectedResult, md)
+	}
+}
+
+func Test_Handlers_GenerateText_Returns_Parse_Err(t *testing.T) {
+	handlers := []goeditorjs.TextBlockHandler{&goeditorjs.HeaderHandler{}, &goeditorjs.ParagraphHandler{}, &goeditorjs.ListHandler{},
+		&goeditorjs.CodeBoxHandler{}, &goeditorjs.RawHTMLHandler{}, &goeditorjs.ImageHandler{}, &goeditorjs.TableHandler{},
+		&goeditorjs.ChecklistHandler{}, &goeditorjs.QuoteHandler{}, &goeditorjs.WarningHandler{}, &goeditorjs.EmbedHandler{},
+		&goeditorjs.LinkToolHandler{}, &goeditorjs.AttachesHandler{}, &goeditorjs.CodeHandler{}}
+
+	for _, h := range handlers {
+		_, err := h.GenerateText(goeditorjs.EditorJSBlock{Type: h.Type(), Data: []byte{}})
+		require.Error(t, err, h.Type())
+	}
+}
+
+func Test_DelimiterHandler_GenerateText(t *testing.T) {
+	h := &goeditorjs.DelimiterHandler{}
+	text, err := h.GenerateText(goeditorjs.EditorJSBlock{Type: "delimiter"})
+	require.NoError(t, err)
+	require.Equal(t, "***", text)
+
+	h = &goeditorjs.DelimiterHandler{Options: &goeditorjs.DelimiterHandlerOptions{Text: "~"}}
+	text, err = h.GenerateText(goeditorjs.EditorJSBlock{Type: "delimiter"})
+	require.NoError(t, err)
+	require.Equal(t, "~", text)
+}
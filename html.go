@@ -1,12 +1,39 @@
 package goeditorjs
 
 import (
-	"fmt"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"sync"
 )
 
 // HTMLEngine is the engine that creates the HTML from EditorJS blocks
+// Handlers can be registered while the engine is generating.
 type HTMLEngine struct {
 	BlockHandlers map[string]HTMLBlockHandler
+	TuneHandlers  map[string]HTMLTuneHandler
+	// Registry, if set, provides the handlers for block types that aren't in BlockHandlers
+	Registry *Registry
+	// UnknownBlockPolicy decides what happens to blocks without a registered handler. Defaults to UnknownBlockFail.
+	UnknownBlockPolicy UnknownBlockPolicy
+	// FallbackHandler generates html for blocks without a registered handler when using UnknownBlockFallback
+	FallbackHandler HTMLBlockHandler
+	// OnUnknownBlock, if set, is called with every block that doesn't have a registered handler
+	OnUnknownBlock func(editorJSBlock EditorJSBlock)
+	// ContinueOnError generates every block that can be generated and returns BlockErrors for the blocks that can't.
+	// Otherwise generation stops at the first block that fails with a *BlockError.
+	ContinueOnError bool
+	// SanitizePolicy, if set, is used by the built-in handlers to sanitize the content of blocks.
+	// It's passed to handlers in the context, custom handlers can get it with SanitizePolicyFromContext.
+	// The comment written for UnknownBlockPlaceholder isn't sanitized, it only keeps the letters, digits, "_" and "-" of the block type.
+	SanitizePolicy *SanitizePolicy
+	// BlockSanitizePolicies override SanitizePolicy for block types. A nil policy turns sanitization off for the block type.
+	BlockSanitizePolicies map[string]*SanitizePolicy
+
+	mu sync.RWMutex
 }
 
 // HTMLBlockHandler is an interface for a plugable EditorJS HTML generator
@@ -15,37 +42,295 @@ type HTMLBlockHandler interface {
 	GenerateHTML(editorJSBlock EditorJSBlock) (string, error)
 }
 
+// ContextHTMLBlockHandler is an interface for a plugable EditorJS html generator that is given the context of the generation,
+// e.g. to read request scoped values. The engine calls GenerateHTMLContext instead of GenerateHTML for handlers implementing it.
+type ContextHTMLBlockHandler interface {
+	HTMLBlockHandler
+	GenerateHTMLContext(ctx context.Context, editorJSBlock EditorJSBlock) (string, error)
+}
+
+// HTMLBlockWriter is an interface for a plugable EditorJS HTML generator that writes the html to an io.Writer.
+// HTMLBlockHandlers registered with the engine that also implement HTMLBlockWriter are written using WriteHTML.
+type HTMLBlockWriter interface {
+	Type() string // Type returns the type the block writer supports as a string
+	WriteHTML(w io.Writer, editorJSBlock EditorJSBlock) error
+}
+
+// HTMLTuneHandler is an interface for a plugable EditorJS block tune HTML generator.
+// It's given the html generated for a block that has the tune and returns the tuned html, or ErrSkipBlock to suppress the block.
+type HTMLTuneHandler interface {
+	TuneHandler
+	TuneHTML(tuneData json.RawMessage, editorJSBlock EditorJSBlock, html string) (string, error)
+}
+
 // NewHTMLEngine creates a new HTMLEngine
 func NewHTMLEngine() *HTMLEngine {
 	bhs := make(map[string]HTMLBlockHandler)
-	return &HTMLEngine{BlockHandlers: bhs}
+	ths := make(map[string]HTMLTuneHandler)
+	return &HTMLEngine{BlockHandlers: bhs, TuneHandlers: ths}
+}
+
+// NewHTMLEngineFromRegistry creates a new HTMLEngine that uses the handlers of the registry
+func NewHTMLEngineFromRegistry(registry *Registry) *HTMLEngine {
+	htmlEngine := NewHTMLEngine()
+	htmlEngine.Registry = registry
+	return htmlEngine
+}
+
+// NewDefaultHTMLEngine creates a new HTMLEngine with all the built-in block handlers and tune handlers registered
+func NewDefaultHTMLEngine() *HTMLEngine {
+	htmlEngine := NewHTMLEngineFromRegistry(NewDefaultRegistry())
+	htmlEngine.RegisterTuneHandlers(&AlignmentTuneHandler{}, &AnchorTuneHandler{}, &HiddenTuneHandler{})
+	return htmlEngine
+}
+
+// NewHTMLBlockWriter adapts an HTMLBlockHandler to an HTMLBlockWriter
+func NewHTMLBlockWriter(handler HTMLBlockHandler) HTMLBlockWriter {
+	return &htmlBlockHandlerWriter{HTMLBlockHandler: handler}
+}
+
+// htmlBlockHandlerWriter adapts an HTMLBlockHandler to an HTMLBlockWriter
+type htmlBlockHandlerWriter struct {
+	HTMLBlockHandler
+}
+
+// WriteHTML writes the html generated by the handler to w
+func (h *htmlBlockHandlerWriter) WriteHTML(w io.Writer, editorJSBlock EditorJSBlock) error {
+	html, err := h.GenerateHTML(editorJSBlock)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, html)
+	return err
+}
+
+// GenerateHTMLContext passes the context on to the handler if it implements ContextHTMLBlockHandler
+func (h *htmlBlockHandlerWriter) GenerateHTMLContext(ctx context.Context, editorJSBlock EditorJSBlock) (string, error) {
+	if contextHandler, ok := h.HTMLBlockHandler.(ContextHTMLBlockHandler); ok {
+		return contextHandler.GenerateHTMLContext(ctx, editorJSBlock)
+	}
+	return h.GenerateHTML(editorJSBlock)
+}
+
+// htmlBlockWriterHandler adapts an HTMLBlockWriter to an HTMLBlockHandler
+type htmlBlockWriterHandler struct {
+	HTMLBlockWriter
+}
+
+// GenerateHTML returns the html written by the writer
+func (h *htmlBlockWriterHandler) GenerateHTML(editorJSBlock EditorJSBlock) (string, error) {
+	html := &strings.Builder{}
+	err := h.WriteHTML(html, editorJSBlock)
+	return html.String(), err
 }
 
 // RegisterBlockHandlers registers or overrides a block handlers for blockType given by HTMLBlockHandler.Type()
 func (htmlEngine *HTMLEngine) RegisterBlockHandlers(handlers ...HTMLBlockHandler) {
+	htmlEngine.mu.Lock()
+	defer htmlEngine.mu.Unlock()
+	if htmlEngine.BlockHandlers == nil {
+		htmlEngine.BlockHandlers = make(map[string]HTMLBlockHandler)
+	}
 	for _, bh := range handlers {
 		htmlEngine.BlockHandlers[bh.Type()] = bh
 	}
 }
 
+// RegisterBlockWriters registers or overrides a block writers for blockType given by HTMLBlockWriter.Type()
+func (htmlEngine *HTMLEngine) RegisterBlockWriters(writers ...HTMLBlockWriter) {
+	htmlEngine.mu.Lock()
+	defer htmlEngine.mu.Unlock()
+	if htmlEngine.BlockHandlers == nil {
+		htmlEngine.BlockHandlers = make(map[string]HTMLBlockHandler)
+	}
+	for _, bw := range writers {
+		if bh, ok := bw.(HTMLBlockHandler); ok {
+			htmlEngine.BlockHandlers[bw.Type()] = bh
+		} else {
+			htmlEngine.BlockHandlers[bw.Type()] = &htmlBlockWriterHandler{HTMLBlockWriter: bw}
+		}
+	}
+}
+
+// RegisterTuneHandlers registers or overrides tune handlers for the tune given by HTMLTuneHandler.Type()
+func (htmlEngine *HTMLEngine) RegisterTuneHandlers(handlers ...HTMLTuneHandler) {
+	htmlEngine.mu.Lock()
+	defer htmlEngine.mu.Unlock()
+	if htmlEngine.TuneHandlers == nil {
+		htmlEngine.TuneHandlers = make(map[string]HTMLTuneHandler)
+	}
+	for _, th := range handlers {
+		htmlEngine.TuneHandlers[th.Type()] = th
+	}
+}
+
 // GenerateHTML generates html from the editorJS using configured set of HTML handlers
 func (htmlEngine *HTMLEngine) GenerateHTML(editorJSData string) (string, error) {
-	result := ""
-	ejs, err := parseEditorJSON(editorJSData)
+	document, err := ParseDocument(editorJSData)
 	if err != nil {
 		return "", err
 	}
-	for _, block := range ejs.Blocks {
-		if generator, ok := htmlEngine.BlockHandlers[block.Type]; ok {
-			html, err := generator.GenerateHTML(block)
+	return htmlEngine.GenerateHTMLFromDocument(document)
+}
+
+// GenerateHTMLContext generates html from the editorJS using configured set of HTML handlers.
+// The context is passed to handlers implementing ContextHTMLBlockHandler, and generation stops when the context is done.
+func (htmlEngine *HTMLEngine) GenerateHTMLContext(ctx context.Context, editorJSData string) (string, error) {
+	document, err := ParseDocument(editorJSData)
+	if err != nil {
+		return "", err
+	}
+	return htmlEngine.GenerateHTMLFromDocumentContext(ctx, document)
+}
+
+// GenerateHTMLFromDocument generates html from a parsed Document using configured set of HTML handlers
+func (htmlEngine *HTMLEngine) GenerateHTMLFromDocument(document *Document) (string, error) {
+	result := &strings.Builder{}
+	err := htmlEngine.RenderHTMLFromDocument(result, document)
+	return result.String(), err
+}
+
+// GenerateHTMLFromDocumentContext generates html from a parsed Document using configured set of HTML handlers.
+// The context is passed to handlers implementing ContextHTMLBlockHandler, and generation stops when the context is done.
+func (htmlEngine *HTMLEngine) GenerateHTMLFromDocumentContext(ctx context.Context, document *Document) (string, error) {
+	result := &strings.Builder{}
+	err := htmlEngine.renderDocument(ctx, result, document)
+	return result.String(), err
+}
+
+// RenderHTML reads editorJS data from r and writes the html to w using configured set of HTML handlers
+func (htmlEngine *HTMLEngine) RenderHTML(w io.Writer, r io.Reader) error {
+	document, err := decodeDocument(r)
+	if err != nil {
+		return err
+	}
+	return htmlEngine.RenderHTMLFromDocument(w, document)
+}
+
+// RenderHTMLContext reads editorJS data from r and writes the html to w using configured set of HTML handlers.
+// The context is passed to handlers implementing ContextHTMLBlockHandler, and rendering stops when the context is done.
+func (htmlEngine *HTMLEngine) RenderHTMLContext(ctx context.Context, w io.Writer, r io.Reader) error {
+	document, err := decodeDocument(r)
+	if err != nil {
+		return err
+	}
+	return htmlEngine.RenderHTMLFromDocumentContext(ctx, w, document)
+}
+
+// RenderHTMLFromDocument writes the html for a parsed Document to w using configured set of HTML handlers
+func (htmlEngine *HTMLEngine) RenderHTMLFromDocument(w io.Writer, document *Document) error {
+	return htmlEngine.renderDocument(context.Background(), w, document)
+}
+
+// RenderHTMLFromDocumentContext writes the html for a parsed Document to w using configured set of HTML handlers.
+// The context is passed to handlers implementing ContextHTMLBlockHandler, and rendering stops when the context is done.
+func (htmlEngine *HTMLEngine) RenderHTMLFromDocumentContext(ctx context.Context, w io.Writer, document *Document) error {
+	return htmlEngine.renderDocument(ctx, w, document)
+}
+
+// renderDocument writes the html for a parsed Document to w
+func (htmlEngine *HTMLEngine) renderDocument(ctx context.Context, w io.Writer, document *Document) error {
+	return writeBlocks(ctx, w, document.Blocks, "", htmlEngine.ContinueOnError, htmlEngine.writeBlock)
+}
+
+// writeBlock writes the html for a single block to buf
+func (htmlEngine *HTMLEngine) writeBlock(ctx context.Context, buf *bytes.Buffer, block EditorJSBlock) (bool, error) {
+	generator, ok := htmlEngine.blockHandler(block.Type)
+	if !ok {
+		switch unknownBlock(htmlEngine.UnknownBlockPolicy, htmlEngine.FallbackHandler != nil, htmlEngine.OnUnknownBlock, block) {
+		case UnknownBlockSkip:
+			return false, nil
+		case UnknownBlockPlaceholder:
+			buf.WriteString(unknownBlockPlaceholder(block))
+			return true, nil
+		case UnknownBlockFallback:
+			generator = htmlEngine.FallbackHandler
+		default:
+			return false, errBlockHandlerNotFound(block)
+		}
+	}
+
+	if policy, ok := htmlEngine.sanitizePolicy(block.Type); ok {
+		ctx = ContextWithSanitizePolicy(ctx, policy)
+	}
+
+	if contextGenerator, ok := generator.(ContextHTMLBlockHandler); ok {
+		output, err := contextGenerator.GenerateHTMLContext(ctx, block)
+		if err != nil {
+			return false, err
+		}
+		buf.WriteString(output)
+	} else if writer, ok := generator.(HTMLBlockWriter); ok {
+		if err := writer.WriteHTML(buf, block); err != nil {
+			return false, err
+		}
+	} else {
+		html, err := generator.GenerateHTML(block)
+		if err != nil {
+			return false, err
+		}
+		buf.WriteString(html)
+	}
+
+	if len(block.Tunes) == 0 {
+		return true, nil
+	}
+
+	html, err := htmlEngine.applyTunes(block, buf.String())
+	buf.Reset()
+	if errors.Is(err, ErrSkipBlock) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	buf.WriteString(html)
+	return true, nil
+}
+
+// sanitizePolicy returns the SanitizePolicy for the block type and whether one is configured
+func (htmlEngine *HTMLEngine) sanitizePolicy(blockType string) (*SanitizePolicy, bool) {
+	if policy, ok := htmlEngine.BlockSanitizePolicies[blockType]; ok {
+		return policy, true
+	}
+	return htmlEngine.SanitizePolicy, htmlEngine.SanitizePolicy != nil
+}
+
+// blockHandler returns the handler for the block type from BlockHandlers, or from the Registry if it has one the engine can use
+func (htmlEngine *HTMLEngine) blockHandler(blockType string) (HTMLBlockHandler, bool) {
+	htmlEngine.mu.RLock()
+	generator, ok := htmlEngine.BlockHandlers[blockType]
+	registry := htmlEngine.Registry
+	htmlEngine.mu.RUnlock()
+	if ok || registry == nil {
+		return generator, ok
+	}
+
+	if h, ok := registry.Lookup(blockType); ok {
+		generator, ok = h.(HTMLBlockHandler)
+		return generator, ok
+	}
+	return nil, false
+}
+
+// tuneHandler returns the registered tune handler for the tune
+func (htmlEngine *HTMLEngine) tuneHandler(name string) (HTMLTuneHandler, bool) {
+	htmlEngine.mu.RLock()
+	defer htmlEngine.mu.RUnlock()
+	tuneHandler, ok := htmlEngine.TuneHandlers[name]
+	return tuneHandler, ok
+}
+
+// applyTunes applies the registered tune handlers for the tunes of the block, in order of tune name
+func (htmlEngine *HTMLEngine) applyTunes(block EditorJSBlock, html string) (string, error) {
+	for _, name := range tuneNames(block) {
+		if tuneHandler, ok := htmlEngine.tuneHandler(name); ok {
+			var err error
+			html, err = tuneHandler.TuneHTML(block.Tunes[name], block, html)
 			if err != nil {
-				return result, err
+				return "", err
 			}
-			result += html
-		} else {
-			return "", fmt.Errorf("%w, Block Type: %s", ErrBlockHandlerNotFound, block.Type)
 		}
 	}
-
-	return result, nil
+	return html, nil
 }
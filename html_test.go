@@ -1,7 +1,12 @@
 package goeditorjs_test
 
 import (
+	"bytes"
+	"context"
 	"errors"
+	"fmt"
+	"io"
+	"strings"
 	"testing"
 
 	"github.com/davidscottmills/goeditorjs"
@@ -61,7 +66,11 @@ func Test_GenerateHTML_Returns_Err_From_Handler(t *testing.T) {
 	eng.BlockHandlers["header"] = bh
 	_, err := eng.GenerateHTML(editorJSData)
 	require.Error(t, err)
-	require.Equal(t, mockErr, err)
+	require.True(t, errors.Is(err, mockErr))
+	blockErr := &goeditorjs.BlockError{}
+	require.True(t, errors.As(err, &blockErr))
+	require.Equal(t, 0, blockErr.Index)
+	require.Equal(t, "header", blockErr.Type)
 	bh.AssertCalled(t, "GenerateHTML", mock.Anything)
 }
 
@@ -76,3 +85,239 @@ func Test_GenerateHTML_Result_Includes_Handler_Result(t *testing.T) {
 	require.Contains(t, result, handlerResult)
 	bh.AssertCalled(t, "GenerateHTML", mock.Anything)
 }
+
+func Test_GenerateHTMLFromDocument(t *testing.T) {
+	document, err := goeditorjs.ParseDocument(`{"time": 1607709186831,"blocks": [{"id": "a","type": "header","data": {"text": "Heading 1","level": 1}},{"id": "b","type": "paragraph","data": {"text": "Text","alignment": "left"}}],"version": "2.22.2"}`)
+	require.NoError(t, err)
+	// Drop the header before rendering
+	document.Blocks = document.Blocks[1:]
+
+	eng := goeditorjs.NewHTMLEngine()
+	eng.RegisterBlockHandlers(&goeditorjs.HeaderHandler{}, &goeditorjs.ParagraphHandler{})
+	result, err := eng.GenerateHTMLFromDocument(document)
+	require.NoError(t, err)
+	require.Equal(t, "<p>Text</p>", result)
+}
+
+func Test_GenerateHTML_UnknownBlockPolicy(t *testing.T) {
+	editorJSData := `{"blocks": [{"type": "header","data": {"text": "Heading 1","level": 1}},{"type": "unknown--type","data": {}},{"type": "paragraph","data": {"text": "Text","alignment": "left"}}]}`
+	fallback := &mockHTMLBlockHandler{typeName: "fallback"}
+	fallback.On("GenerateHTML", mock.Anything).Return("<div>fallback</div>", nil)
+
+	testData := []struct {
+		policy         goeditorjs.UnknownBlockPolicy
+		fallback       goeditorjs.HTMLBlockHandler
+		expectedResult string
+		expectErr      bool
+	}{
+		{policy: goeditorjs.UnknownBlockFail, expectErr: true},
+		{policy: goeditorjs.UnknownBlockSkip, expectedResult: "<h1>Heading 1</h1><p>Text</p>"},
+		{policy: goeditorjs.UnknownBlockPlaceholder, expectedResult: `<h1>Heading 1</h1><!-- goeditorjs: no handler for block type "unknown-type" --><p>Text</p>`},
+		{policy: goeditorjs.UnknownBlockFallback, fallback: fallback, expectedResult: "<h1>Heading 1</h1><div>fallback</div><p>Text</p>"},
+		{policy: goeditorjs.UnknownBlockFallback, expectErr: true},
+	}
+
+	for _, td := range testData {
+		unknownTypes := []string{}
+		eng := goeditorjs.NewHTMLEngine()
+		eng.RegisterBlockHandlers(&goeditorjs.HeaderHandler{}, &goeditorjs.ParagraphHandler{})
+		eng.UnknownBlockPolicy = td.policy
+		eng.FallbackHandler = td.fallback
+		eng.OnUnknownBlock = func(editorJSBlock goeditorjs.EditorJSBlock) {
+			unknownTypes = append(unknownTypes, editorJSBlock.Type)
+		}
+
+		result, err := eng.GenerateHTML(editorJSData)
+		if td.expectErr {
+			require.True(t, errors.Is(err, goeditorjs.ErrBlockHandlerNotFound))
+		} else {
+			require.NoError(t, err)
+			require.Equal(t, td.expectedResult, result)
+		}
+		require.Equal(t, []string{"unknown--type"}, unknownTypes)
+	}
+}
+
+func Test_GenerateHTML_UnknownBlockPlaceholder_Escapes_Type(t *testing.T) {
+	editorJSData := `{"blocks": [{"type": "---><img src=x onerror=alert(1)>","data": {}},{"type": "paragraph","data": {"text": "Text","alignment": "left"}}]}`
+	eng := goeditorjs.NewHTMLEngine()
+	eng.RegisterBlockHandlers(&goeditorjs.ParagraphHandler{})
+	eng.UnknownBlockPolicy = goeditorjs.UnknownBlockPlaceholder
+
+	result, err := eng.GenerateHTML(editorJSData)
+	require.NoError(t, err)
+	require.Equal(t, `<!-- goeditorjs: no handler for block type "-__img_src_x_onerror_alert_1__" --><p>Text</p>`, result)
+}
+
+func Test_GenerateHTML_Returns_Partial_Result_On_Err(t *testing.T) {
+	editorJSData := `{"blocks": [{"id": "a","type": "header","data": {"text": "Heading 1","level": 1}},{"id": "b","type": "paragraph","data": []},{"id": "c","type": "unknown","data": {}},{"id": "d","type": "paragraph","data": {"text": "Text","alignment": "left"}}]}`
+	eng := goeditorjs.NewHTMLEngine()
+	eng.RegisterBlockHandlers(&goeditorjs.HeaderHandler{}, &goeditorjs.ParagraphHandler{})
+
+	result, err := eng.GenerateHTML(editorJSData)
+	require.Equal(t, "<h1>Heading 1</h1>", result)
+	blockErr := &goeditorjs.BlockError{}
+	require.True(t, errors.As(err, &blockErr))
+	require.Equal(t, 1, blockErr.Index)
+	require.Equal(t, "b", blockErr.ID)
+	require.Equal(t, "paragraph", blockErr.Type)
+	require.Equal(t, "block 1 (id: b, type: paragraph): "+blockErr.Err.Error(), err.Error())
+}
+
+func Test_GenerateHTML_ContinueOnError(t *testing.T) {
+	editorJSData := `{"blocks": [{"id": "a","type": "header","data": {"text": "Heading 1","level": 1}},{"id": "b","type": "paragraph","data": []},{"id": "c","type": "unknown","data": {}},{"id": "d","type": "paragraph","data": {"text": "Text","alignment": "left"}}]}`
+	eng := goeditorjs.NewHTMLEngine()
+	eng.RegisterBlockHandlers(&goeditorjs.HeaderHandler{}, &goeditorjs.ParagraphHandler{})
+	eng.ContinueOnError = true
+
+	result, err := eng.GenerateHTML(editorJSData)
+	require.Equal(t, "<h1>Heading 1</h1><p>Text</p>", result)
+	blockErrs := goeditorjs.BlockErrors{}
+	require.True(t, errors.As(err, &blockErrs))
+	require.Len(t, blockErrs, 2)
+	require.Equal(t, 1, blockErrs[0].Index)
+	require.Equal(t, 2, blockErrs[1].Index)
+	require.True(t, errors.Is(err, goeditorjs.ErrBlockHandlerNotFound))
+}
+
+type testHTMLBlockWriter struct{}
+
+func (*testHTMLBlockWriter) Type() string {
+	return "header"
+}
+
+func (*testHTMLBlockWriter) WriteHTML(w io.Writer, editorJSBlock goeditorjs.EditorJSBlock) error {
+	_, err := fmt.Fprintf(w, "<h1>%s</h1>", editorJSBlock.ID)
+	return err
+}
+
+type failingWriter struct{}
+
+func (*failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+func Test_RenderHTML(t *testing.T) {
+	editorJSData := `{"blocks": [{"id": "a","type": "header","data": {}},{"id": "b","type": "paragraph","data": {"text": "Text","alignment": "left"}}]}`
+	eng := goeditorjs.NewHTMLEngine()
+	eng.RegisterBlockWriters(&testHTMLBlockWriter{}, goeditorjs.NewHTMLBlockWriter(&goeditorjs.ParagraphHandler{}))
+
+	w := &bytes.Buffer{}
+	err := eng.RenderHTML(w, strings.NewReader(editorJSData))
+	require.NoError(t, err)
+	require.Equal(t, "<h1>a</h1><p>Text</p>", w.String())
+
+	result, err := eng.GenerateHTML(editorJSData)
+	require.NoError(t, err)
+	require.Equal(t, "<h1>a</h1><p>Text</p>", result)
+}
+
+func Test_RenderHTML_Returns_Parse_Err(t *testing.T) {
+	eng := goeditorjs.NewHTMLEngine()
+	err := eng.RenderHTML(&bytes.Buffer{}, strings.NewReader(``))
+	require.Error(t, err)
+}
+
+func Test_RenderHTML_Returns_Write_Err(t *testing.T) {
+	editorJSData := `{"blocks": [{"type": "paragraph","data": {"text": "Text","alignment": "left"}}]}`
+	eng := goeditorjs.NewHTMLEngine()
+	eng.RegisterBlockHandlers(&goeditorjs.ParagraphHandler{})
+	err := eng.RenderHTML(&failingWriter{}, strings.NewReader(editorJSData))
+	require.EqualError(t, err, "write failed")
+}
+
+func Test_NewHTMLBlockWriter(t *testing.T) {
+	w := goeditorjs.NewHTMLBlockWriter(&goeditorjs.HeaderHandler{})
+	require.Equal(t, "header", w.Type())
+
+	buf := &bytes.Buffer{}
+	err := w.WriteHTML(buf, goeditorjs.EditorJSBlock{Type: "header", Data: []byte(`{"text": "Heading","level": 2}`)})
+	require.NoError(t, err)
+	require.Equal(t, "<h2>Heading</h2>", buf.String())
+
+	err = w.WriteHTML(buf, goeditorjs.EditorJSBlock{Type: "header", Data: []byte{}})
+	require.Error(t, err)
+}
+
+type testContextKey struct{}
+
+type testContextHTMLBlockHandler struct{}
+
+func (*testContextHTMLBlockHandler) Type() string {
+	return "paragraph"
+}
+
+func (*testContextHTMLBlockHandler) GenerateHTML(editorJSBlock goeditorjs.EditorJSBlock) (string, error) {
+	return "", errors.New("GenerateHTML should not be called")
+}
+
+func (*testContextHTMLBlockHandler) GenerateHTMLContext(ctx context.Context, editorJSBlock goeditorjs.EditorJSBlock) (string, error) {
+	user, _ := ctx.Value(testContextKey{}).(string)
+	return "<p>" + user + "</p>", nil
+}
+
+func Test_GenerateHTMLContext_Passes_Context_To_Handler(t *testing.T) {
+	editorJSData := `{"blocks": [{"type": "paragraph","data": {"text": "Text"}}]}`
+	eng := goeditorjs.NewHTMLEngine()
+	eng.RegisterBlockHandlers(&testContextHTMLBlockHandler{})
+
+	ctx := context.WithValue(context.Background(), testContextKey{}, "gopher")
+	result, err := eng.GenerateHTMLContext(ctx, editorJSData)
+	require.NoError(t, err)
+	require.Equal(t, "<p>gopher</p>", result)
+
+	w := &bytes.Buffer{}
+	err = eng.RenderHTMLContext(ctx, w, strings.NewReader(editorJSData))
+	require.NoError(t, err)
+	require.Equal(t, "<p>gopher</p>", w.String())
+
+	document, err := goeditorjs.ParseDocument(editorJSData)
+	require.NoError(t, err)
+	result, err = eng.GenerateHTMLFromDocumentContext(ctx, document)
+	require.NoError(t, err)
+	require.Equal(t, "<p>gopher</p>", result)
+
+	w.Reset()
+	err = eng.RenderHTMLFromDocumentContext(ctx, w, document)
+	require.NoError(t, err)
+	require.Equal(t, "<p>gopher</p>", w.String())
+}
+
+func Test_GenerateHTMLFromDocumentContext_Stops_When_Cancelled(t *testing.T) {
+	document, err := goeditorjs.ParseDocument(`{"blocks": [{"type": "paragraph","data": {"text": "Text"}}]}`)
+	require.NoError(t, err)
+	eng := goeditorjs.NewHTMLEngine()
+	eng.RegisterBlockHandlers(&goeditorjs.ParagraphHandler{})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	result, err := eng.GenerateHTMLFromDocumentContext(ctx, document)
+	require.Equal(t, context.Canceled, err)
+	require.Equal(t, "", result)
+
+	w := &bytes.Buffer{}
+	err = eng.RenderHTMLFromDocumentContext(ctx, w, document)
+	require.Equal(t, context.Canceled, err)
+	require.Equal(t, "", w.String())
+}
+
+func Test_GenerateHTMLContext_Stops_When_Cancelled(t *testing.T) {
+	editorJSData := `{"blocks": [{"type": "paragraph","data": {"text": "One"}},{"type": "paragraph","data": {"text": "Two"}}]}`
+	ctx, cancel := context.WithCancel(context.Background())
+	handler := &mockHTMLBlockHandler{typeName: "paragraph"}
+	handler.On("GenerateHTML", mock.Anything).Run(func(mock.Arguments) { cancel() }).Return("One", nil).Once()
+	eng := goeditorjs.NewHTMLEngine()
+	eng.ContinueOnError = true
+	eng.RegisterBlockHandlers(handler)
+
+	result, err := eng.GenerateHTMLContext(ctx, editorJSData)
+	require.Equal(t, context.Canceled, err)
+	require.Equal(t, "One", result)
+	handler.AssertExpectations(t)
+}
+
+func Test_GenerateHTMLContext_Returns_Parse_Err(t *testing.T) {
+	eng := goeditorjs.NewHTMLEngine()
+	_, err := eng.GenerateHTMLContext(context.Background(), ``)
+	require.Error(t, err)
+}
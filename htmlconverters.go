@@ -0,0 +1,398 @@
+package goeditorjs
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// HeaderElementConverter is the default converter of h1 to h6 elements to header blocks
+type HeaderElementConverter struct{}
+
+// Elements "h1" to "h6"
+func (*HeaderElementConverter) Elements() []string {
+	return []string{"h1", "h2", "h3", "h4", "h5", "h6"}
+}
+
+// ConvertHTML converts a heading into a header block
+func (*HeaderElementConverter) ConvertHTML(importer *HTMLImporter, node *HTMLNode) ([]EditorJSBlock, error) {
+	text := importer.ConvertInline(node)
+	if text == "" {
+		return nil, nil
+	}
+
+	level, _ := strconv.Atoi(node.Tag[1:])
+	return []EditorJSBlock{newImportBlock("header", &header{Text: text, Level: level})}, nil
+}
+
+// ParagraphElementConverter is the default converter of p elements to paragraph blocks
+type ParagraphElementConverter struct{}
+
+// Elements "p"
+func (*ParagraphElementConverter) Elements() []string {
+	return []string{"p"}
+}
+
+// ConvertHTML converts a paragraph into a paragraph block, using the text-align style as its alignment.
+// A paragraph holding block level elements, like images, is converted into several blocks.
+func (*ParagraphElementConverter) ConvertHTML(importer *HTMLImporter, node *HTMLNode) ([]EditorJSBlock, error) {
+	for _, child := range node.Children {
+		if !importer.isInline(child) {
+			return importer.ConvertBlocks(node.Children)
+		}
+	}
+
+	text := importer.ConvertInline(node)
+	if text == "" {
+		return nil, nil
+	}
+
+	return []EditorJSBlock{newImportBlock("paragraph", &paragraph{Text: text, Alignment: htmlAlignment(node)})}, nil
+}
+
+// ListElementConverter is the default converter of ul and ol elements to list and checklist blocks
+type ListElementConverter struct{}
+
+// Elements "ul" and "ol"
+func (*ListElementConverter) Elements() []string {
+	return []string{"ul", "ol"}
+}
+
+// htmlListCounterTypes maps the type attribute of ordered lists to counter types
+var htmlListCounterTypes = map[string]string{"a": "lower-alpha", "A": "upper-alpha", "i": "lower-roman", "I": "upper-roman"}
+
+// ConvertHTML converts a list into a list block. A list whose items all have a checkbox is a checklist,
+// a checklist block if none of its items have nested items.
+func (h *ListElementConverter) ConvertHTML(importer *HTMLImporter, node *HTMLNode) ([]EditorJSBlock, error) {
+	items, checkboxes := h.items(importer, node)
+	if len(items) == 0 {
+		return nil, nil
+	}
+
+	if checkboxes {
+		nested := false
+		checklistData := &checklist{Items: []checklistItem{}}
+		for _, item := range items {
+			nested = nested || len(item.Items) > 0
+			checklistData.Items = append(checklistData.Items, checklistItem{Text: item.Content, Checked: item.Meta.Checked})
+		}
+		if !nested {
+			return []EditorJSBlock{newImportBlock("checklist", checklistData)}, nil
+		}
+		return []EditorJSBlock{newImportBlock("list", &list{Style: "checklist", Items: items})}, nil
+	}
+
+	list := &list{Style: "unordered", Items: items}
+	if node.Tag == "ol" {
+		list.Style = "ordered"
+		if start, err := strconv.Atoi(attributeOrEmpty(node, "start")); err == nil {
+			list.Meta.Start = start
+		}
+		list.Meta.CounterType = htmlListCounterTypes[attributeOrEmpty(node, "type")]
+		if counterType := htmlStyleProperty(node, "list-style-type"); counterType != "" {
+			list.Meta.CounterType = counterType
+		}
+	}
+	return []EditorJSBlock{newImportBlock("list", list)}, nil
+}
+
+// items converts the li children of a list, the items of nested lists become the nested items.
+// It also reports whether every item, including the nested items, has a checkbox.
+func (h *ListElementConverter) items(importer *HTMLImporter, node *HTMLNode) ([]listItem, bool) {
+	items := []listItem{}
+	checkboxes := true
+	for _, child := range node.Children {
+		if child.Tag != "li" {
+			continue
+		}
+
+		item := listItem{Items: []listItem{}}
+		content := []*HTMLNode{}
+		for _, grandchild := range child.Children {
+			if grandchild.Tag == "ul" || grandchild.Tag == "ol" {
+				nested, nestedCheckboxes := h.items(importer, grandchild)
+				item.Items = append(item.Items, nested...)
+				checkboxes = checkboxes && nestedCheckboxes
+				continue
+			}
+			content = append(content, grandchild)
+		}
+
+		item.Content = importer.inlineHTML(content)
+		checkbox := (&HTMLNode{Children: content}).Find("input")
+		if checkbox != nil && attributeOrEmpty(checkbox, "type") == "checkbox" {
+			_, item.Meta.Checked = checkbox.Attribute("checked")
+		} else {
+			checkboxes = false
+		}
+		items = append(items, item)
+	}
+
+	return items, checkboxes && len(items) > 0
+}
+
+// ImageElementConverter is the default converter of img elements to image blocks
+type ImageElementConverter struct {
+	// Options are the classes of images that are stretched, have a border or a background.
+	// If not provided, DefaultImageHandlerOptions will be used.
+	Options *ImageHandlerOptions
+}
+
+// Elements "img"
+func (*ImageElementConverter) Elements() []string {
+	return []string{"img"}
+}
+
+func (h *ImageElementConverter) options() *ImageHandlerOptions {
+	if h.Options == nil {
+		return DefaultImageHandlerOptions
+	}
+	return h.Options
+}
+
+// ConvertHTML converts an image into an image block with the alt text as its caption
+func (h *ImageElementConverter) ConvertHTML(importer *HTMLImporter, node *HTMLNode) ([]EditorJSBlock, error) {
+	src := attributeOrEmpty(node, "src")
+	if src == "" {
+		return nil, nil
+	}
+
+	options := h.options()
+	image := &image{
+		File:           file{URL: src},
+		Caption:        escapeHTMLText(attributeOrEmpty(node, "alt")),
+		Stretched:      options.StretchClass != "" && node.HasClass(options.StretchClass),
+		WithBorder:     options.BorderClass != "" && node.HasClass(options.BorderClass),
+		WithBackground: options.BackgroundClass != "" && node.HasClass(options.BackgroundClass),
+	}
+	return []EditorJSBlock{newImportBlock("image", image)}, nil
+}
+
+// FigureElementConverter is the default converter of figure elements
+type FigureElementConverter struct{}
+
+// Elements "figure"
+func (*FigureElementConverter) Elements() []string {
+	return []string{"figure"}
+}
+
+// ConvertHTML converts the content of a figure, the figcaption becomes the caption of the first image or quote block of the content
+func (*FigureElementConverter) ConvertHTML(importer *HTMLImporter, node *HTMLNode) ([]EditorJSBlock, error) {
+	caption := ""
+	content := []*HTMLNode{}
+	for _, child := range node.Children {
+		if child.Tag == "figcaption" {
+			caption = importer.ConvertInline(child)
+			continue
+		}
+		content = append(content, child)
+	}
+
+	blocks, err := importer.ConvertBlocks(content)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, block := range blocks {
+		switch block.Type {
+		case "image":
+			image := &image{}
+			if err := json.Unmarshal(block.Data, image); err != nil {
+				return nil, err
+			}
+			if caption != "" {
+				image.Caption = caption
+			}
+			blocks[i] = newImportBlock("image", image)
+		case "quote":
+			quote := &quote{}
+			if err := json.Unmarshal(block.Data, quote); err != nil {
+				return nil, err
+			}
+			if caption != "" {
+				quote.Caption = caption
+			}
+			if alignment := htmlAlignment(node); alignment != "left" {
+				quote.Alignment = alignment
+			}
+			blocks[i] = newImportBlock("quote", quote)
+		default:
+			continue
+		}
+		break
+	}
+
+	return blocks, nil
+}
+
+// QuoteElementConverter is the default converter of blockquote elements to quote blocks
+type QuoteElementConverter struct{}
+
+// Elements "blockquote"
+func (*QuoteElementConverter) Elements() []string {
+	return []string{"blockquote"}
+}
+
+// ConvertHTML converts a block quote into a quote block. A footer or a cite element after the text is the caption of the quote.
+func (*QuoteElementConverter) ConvertHTML(importer *HTMLImporter, node *HTMLNode) ([]EditorJSBlock, error) {
+	content := node.Children
+	caption := ""
+	for i := len(content) - 1; i >= 0; i-- {
+		if content[i].IsText() && strings.TrimSpace(content[i].Text) == "" {
+			continue
+		}
+		if content[i].Tag == "footer" || content[i].Tag == "cite" {
+			caption = importer.ConvertInline(content[i])
+			content = content[:i]
+		}
+		break
+	}
+
+	text := importer.inlineHTML(content)
+	if text == "" && caption == "" {
+		return nil, nil
+	}
+
+	return []EditorJSBlock{newImportBlock("quote", &quote{Text: text, Caption: caption, Alignment: htmlAlignment(node)})}, nil
+}
+
+// TableElementConverter is the default converter of table elements to table blocks
+type TableElementConverter struct{}
+
+// Elements "table"
+func (*TableElementConverter) Elements() []string {
+	return []string{"table"}
+}
+
+// ConvertHTML converts a table into a table block. It has headings if it has a thead or its first row only has th cells.
+func (*TableElementConverter) ConvertHTML(importer *HTMLImporter, node *HTMLNode) ([]EditorJSBlock, error) {
+	rows := []*HTMLNode{}
+	withHeadings := false
+	for _, child := range node.Children {
+		switch child.Tag {
+		case "tr":
+			rows = append(rows, child)
+		case "thead", "tbody", "tfoot":
+			for _, row := range child.Children {
+				if row.Tag == "tr" {
+					rows = append(rows, row)
+					withHeadings = withHeadings || (child.Tag == "thead" && len(rows) == 1)
+				}
+			}
+		}
+	}
+
+	content := [][]string{}
+	columns := 0
+	for i, row := range rows {
+		cells := []string{}
+		headings := true
+		for _, cell := range row.Children {
+			if cell.Tag == "td" || cell.Tag == "th" {
+				cells = append(cells, importer.ConvertInline(cell))
+				headings = headings && cell.Tag == "th"
+			}
+		}
+		if i == 0 && headings && len(cells) > 0 {
+			withHeadings = true
+		}
+		if len(cells) > columns {
+			columns = len(cells)
+		}
+		content = append(content, cells)
+	}
+
+	if columns == 0 {
+		return nil, nil
+	}
+
+	for i, cells := range content {
+		for len(cells) < columns {
+			cells = append(cells, "")
+		}
+		content[i] = cells
+	}
+	return []EditorJSBlock{newImportBlock("table", &table{WithHeadings: withHeadings, Content: content})}, nil
+}
+
+// CodeElementConverter is the default converter of pre elements to code blocks
+type CodeElementConverter struct {
+	// BlockType is the type of the blocks, "code" or "codeBox". Defaults to "code".
+	BlockType string
+}
+
+// Elements "pre"
+func (*CodeElementConverter) Elements() []string {
+	return []string{"pre"}
+}
+
+// ConvertHTML converts preformatted text into a code block.
+// The language is taken from a "language-" or "lang-" class of the pre element or its code element.
+func (h *CodeElementConverter) ConvertHTML(importer *HTMLImporter, node *HTMLNode) ([]EditorJSBlock, error) {
+	language := codeLanguage(node)
+	if code := node.Find("code"); code != nil && language == "" {
+		language = codeLanguage(code)
+	}
+
+	// A newline right after the start tag isn't part of the content.
+	// Editors put the lines of code in block level elements, they become line breaks.
+	code := strings.TrimPrefix(htmlToText(node.InnerHTML(), true), "\n")
+	return []EditorJSBlock{newCodeBlock(h.BlockType, code, language)}, nil
+}
+
+// codeLanguage returns the language of the "language-" or "lang-" class of the node, or of its only class
+func codeLanguage(node *HTMLNode) string {
+	classes := strings.Fields(attributeOrEmpty(node, "class"))
+	for _, class := range classes {
+		for _, prefix := range []string{"language-", "lang-"} {
+			if strings.HasPrefix(class, prefix) {
+				return strings.TrimPrefix(class, prefix)
+			}
+		}
+	}
+	if len(classes) == 1 {
+		return classes[0]
+	}
+	return ""
+}
+
+// DelimiterElementConverter is the default converter of hr elements to delimiter blocks
+type DelimiterElementConverter struct{}
+
+// Elements "hr"
+func (*DelimiterElementConverter) Elements() []string {
+	return []string{"hr"}
+}
+
+// ConvertHTML converts a thematic break into a delimiter block
+func (*DelimiterElementConverter) ConvertHTML(importer *HTMLImporter, node *HTMLNode) ([]EditorJSBlock, error) {
+	return []EditorJSBlock{{Type: "delimiter", Data: json.RawMessage(`{}`)}}, nil
+}
+
+// attributeOrEmpty returns the value of the named attribute of the node, or "" if it doesn't have it
+func attributeOrEmpty(node *HTMLNode, name string) string {
+	value, _ := node.Attribute(name)
+	return value
+}
+
+// htmlAlignment returns the text-align style or align attribute of the node, or "left" if it has neither
+func htmlAlignment(node *HTMLNode) string {
+	if alignment := htmlStyleProperty(node, "text-align"); alignment != "" {
+		return alignment
+	}
+	if alignment := strings.ToLower(attributeOrEmpty(node, "align")); alignment != "" {
+		return alignment
+	}
+	return "left"
+}
+
+// htmlStyleProperty returns the lower case value of a property of the style attribute of the node, or "" if it isn't set
+func htmlStyleProperty(node *HTMLNode, property string) string {
+	for _, declaration := range strings.Split(attributeOrEmpty(node, "style"), ";") {
+		parts := strings.SplitN(declaration, ":", 2)
+		if len(parts) == 2 && strings.EqualFold(strings.TrimSpace(parts[0]), property) {
+			return strings.ToLower(strings.TrimSpace(parts[1]))
+		}
+	}
+	return ""
+}
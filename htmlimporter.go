@@ -0,0 +1,424 @@
+package goeditorjs
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// UnknownElementPolicy decides what the HTMLImporter does with a block level element that doesn't have a registered converter
+type UnknownElementPolicy int
+
+const (
+	// UnknownElementUnwrap converts the children of the element in its place. This is the default.
+	UnknownElementUnwrap UnknownElementPolicy = iota
+	// UnknownElementDrop leaves the element and its children out of the document
+	UnknownElementDrop
+	// UnknownElementRaw keeps the element as a raw html block
+	UnknownElementRaw
+)
+
+// HTMLImporter converts html into editor.js documents.
+// Block level elements are converted into blocks by the ElementConverters, consecutive text and other elements become paragraphs.
+// Converters can be registered while the importer is converting.
+type HTMLImporter struct {
+	ElementConverters map[string]HTMLElementConverter
+	// UnknownElementPolicy decides what happens to block level elements without a registered converter. Defaults to UnknownElementUnwrap.
+	// Other elements without a converter, like inline and custom elements, are always part of the text of a block.
+	UnknownElementPolicy UnknownElementPolicy
+	// OnUnknownElement, if set, is called with every block level element that doesn't have a registered converter
+	OnUnknownElement func(node *HTMLNode)
+	// InlinePolicy sanitizes the inline html of the text of blocks. If nil, all inline markup is kept.
+	InlinePolicy *SanitizePolicy
+
+	mu sync.RWMutex
+}
+
+// HTMLElementConverter is an interface for a plugable converter of html elements to EditorJS blocks
+type HTMLElementConverter interface {
+	Elements() []string // Elements returns the lower case tag names of the elements the converter supports
+	ConvertHTML(importer *HTMLImporter, node *HTMLNode) ([]EditorJSBlock, error)
+}
+
+// NewHTMLImporter creates a new HTMLImporter that keeps the inline markup allowed by NewSanitizePolicy
+func NewHTMLImporter() *HTMLImporter {
+	ecs := make(map[string]HTMLElementConverter)
+	return &HTMLImporter{ElementConverters: ecs, InlinePolicy: NewSanitizePolicy()}
+}
+
+// NewDefaultHTMLImporter creates a new HTMLImporter with all the built-in element converters registered
+func NewDefaultHTMLImporter() *HTMLImporter {
+	importer := NewHTMLImporter()
+	importer.RegisterElementConverters(
+		&HeaderElementConverter{},
+		&ParagraphElementConverter{},
+		&ListElementConverter{},
+		&ImageElementConverter{},
+		&FigureElementConverter{},
+		&QuoteElementConverter{},
+		&TableElementConverter{},
+		&CodeElementConverter{},
+		&DelimiterElementConverter{},
+	)
+	return importer
+}
+
+// RegisterElementConverters registers or overrides element converters for the elements given by HTMLElementConverter.Elements()
+func (importer *HTMLImporter) RegisterElementConverters(converters ...HTMLElementConverter) {
+	importer.mu.Lock()
+	defer importer.mu.Unlock()
+	if importer.ElementConverters == nil {
+		importer.ElementConverters = make(map[string]HTMLElementConverter)
+	}
+	for _, ec := range converters {
+		for _, element := range ec.Elements() {
+			importer.ElementConverters[element] = ec
+		}
+	}
+}
+
+// elementConverter returns the converter registered for the element and whether there is one
+func (importer *HTMLImporter) elementConverter(element string) (HTMLElementConverter, bool) {
+	importer.mu.RLock()
+	defer importer.mu.RUnlock()
+	ec, ok := importer.ElementConverters[element]
+	return ec, ok
+}
+
+// ImportHTML converts an html document or fragment into an editor.js document
+func (importer *HTMLImporter) ImportHTML(htmlData string) (*Document, error) {
+	blocks, err := importer.ConvertBlocks(parseHTMLNodes(htmlData))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Document{Blocks: blocks}, nil
+}
+
+// ConvertBlocks converts nodes into blocks, e.g. the children of an element that holds blocks.
+// Consecutive text and elements without a converter that aren't block level become paragraphs.
+func (importer *HTMLImporter) ConvertBlocks(nodes []*HTMLNode) ([]EditorJSBlock, error) {
+	blocks := []EditorJSBlock{}
+	inline := []*HTMLNode{}
+	flushInline := func() {
+		if text := importer.inlineHTML(inline); text != "" {
+			blocks = append(blocks, newImportBlock("paragraph", &paragraph{Text: text, Alignment: "left"}))
+		}
+		inline = []*HTMLNode{}
+	}
+
+	for _, node := range nodes {
+		if importer.isInline(node) {
+			inline = append(inline, node)
+			continue
+		}
+
+		flushInline()
+		converted, err := importer.convertElement(node)
+		if err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, converted...)
+	}
+	flushInline()
+
+	return blocks, nil
+}
+
+// ConvertInline returns the inline html of the children of node, e.g. the text of a block.
+// Whitespace is collapsed, block level children are separated by line breaks and the html is sanitized with the InlinePolicy.
+func (importer *HTMLImporter) ConvertInline(node *HTMLNode) string {
+	return importer.inlineHTML(node.Children)
+}
+
+// isInline reports whether the node is text or an element without a converter that isn't block level
+func (importer *HTMLImporter) isInline(node *HTMLNode) bool {
+	if node.IsText() {
+		return true
+	}
+	if _, ok := importer.elementConverter(node.Tag); ok {
+		return false
+	}
+	return !htmlBlockElements[node.Tag]
+}
+
+// convertElement converts a block level element with its converter or the UnknownElementPolicy
+func (importer *HTMLImporter) convertElement(node *HTMLNode) ([]EditorJSBlock, error) {
+	if ec, ok := importer.elementConverter(node.Tag); ok {
+		return ec.ConvertHTML(importer, node)
+	}
+
+	switch {
+	case node.Tag == "html" || node.Tag == "body":
+		return importer.ConvertBlocks(node.Children)
+	case htmlImportIgnoredElements[node.Tag] || sanitizeDroppedElements[node.Tag]:
+		return nil, nil
+	}
+
+	if importer.OnUnknownElement != nil {
+		importer.OnUnknownElement(node)
+	}
+
+	switch importer.UnknownElementPolicy {
+	case UnknownElementDrop:
+		return nil, nil
+	case UnknownElementRaw:
+		return []EditorJSBlock{newImportBlock("raw", &raw{HTML: node.OuterHTML()})}, nil
+	default:
+		return importer.ConvertBlocks(node.Children)
+	}
+}
+
+// inlineHTML returns the sanitized inline html of nodes
+func (importer *HTMLImporter) inlineHTML(nodes []*HTMLNode) string {
+	writer := &inlineHTMLWriter{}
+	writer.writeNodes(nodes)
+	return strings.TrimSpace(importer.InlinePolicy.Sanitize(writer.String()))
+}
+
+// htmlBlockElements are the block level elements, other elements are part of the text of a block
+var htmlBlockElements = map[string]bool{
+	"address": true, "article": true, "aside": true, "audio": true, "blockquote": true, "body": true, "canvas": true,
+	"caption": true, "center": true, "dd": true, "details": true, "dialog": true, "div": true, "dl": true, "dt": true,
+	"embed": true, "fieldset": true, "figcaption": true, "figure": true, "footer": true, "form": true, "h1": true,
+	"h2": true, "h3": true, "h4": true, "h5": true, "h6": true, "head": true, "header": true, "hr": true, "html": true,
+	"iframe": true, "legend": true, "li": true, "main": true, "menu": true, "nav": true, "noscript": true, "object": true,
+	"ol": true, "p": true, "pre": true, "section": true, "summary": true, "table": true, "tbody": true, "td": true,
+	"tfoot": true, "th": true, "thead": true, "tr": true, "ul": true, "video": true}
+
+// htmlImportIgnoredElements are never converted into blocks
+var htmlImportIgnoredElements = map[string]bool{"head": true, "meta": true, "link": true, "base": true}
+
+// htmlImportInlineNames are the names editor.js uses for inline elements
+var htmlImportInlineNames = map[string]string{"strong": "b", "em": "i", "del": "s", "strike": "s"}
+
+// inlineHTMLWriter writes the inline html of nodes, collapsing whitespace like a browser does.
+// Whitespace and line breaks, including the breaks between block level elements, are only written before the content that follows them.
+type inlineHTMLWriter struct {
+	strings.Builder
+	pendingSpace  bool
+	pendingBreaks int
+}
+
+func (w *inlineHTMLWriter) writeNodes(nodes []*HTMLNode) {
+	for _, node := range nodes {
+		switch {
+		case node.IsText():
+			w.writeText(node.Text)
+		case node.Tag == "br":
+			w.pendingBreaks++
+		case node.Tag == "input" || sanitizeDroppedElements[node.Tag] || htmlImportIgnoredElements[node.Tag]:
+		case htmlBlockElements[node.Tag]:
+			// Block level elements inside of text are separated by line breaks
+			w.breakLine()
+			w.writeNodes(node.Children)
+			w.breakLine()
+		default:
+			name := node.Tag
+			if inlineName, ok := htmlImportInlineNames[name]; ok {
+				name = inlineName
+			}
+			w.writePending()
+			w.WriteString(node.startTag(name))
+			if !htmlVoidElements[node.Tag] {
+				w.writeNodes(node.Children)
+				w.WriteString("</" + name + ">")
+			}
+		}
+	}
+}
+
+func (w *inlineHTMLWriter) writeText(text string) {
+	for i, word := range htmlWhitespaceRun.Split(text, -1) {
+		if i > 0 {
+			w.pendingSpace = true
+		}
+		if word != "" {
+			w.writePending()
+			w.WriteString(word)
+		}
+	}
+}
+
+// breakLine makes sure the following content starts on a new line
+func (w *inlineHTMLWriter) breakLine() {
+	if w.pendingBreaks == 0 {
+		w.pendingBreaks = 1
+	}
+}
+
+// writePending writes the pending line breaks or space, unless nothing has been written yet
+func (w *inlineHTMLWriter) writePending() {
+	if w.Len() > 0 {
+		if w.pendingBreaks > 0 {
+			w.WriteString(strings.Repeat("<br>", w.pendingBreaks))
+		} else if w.pendingSpace {
+			w.WriteByte(' ')
+		}
+	}
+	w.pendingSpace, w.pendingBreaks = false, 0
+}
+
+// htmlWhitespaceRun matches runs of html whitespace
+var htmlWhitespaceRun = regexp.MustCompile(`[ \t\n\r\f]+`)
+
+// HTMLNode is a node of the html parsed by the HTMLImporter, an element or text
+type HTMLNode struct {
+	// Tag is the lower case tag name of an element, it's empty for text
+	Tag string
+	// Text is the html of text, with its character references as they are
+	Text     string
+	Children []*HTMLNode
+
+	attributes []htmlAttribute
+}
+
+// IsText reports whether the node is text
+func (n *HTMLNode) IsText() bool {
+	return n.Tag == ""
+}
+
+// Attribute returns the value of the named attribute of an element and whether it exists
+func (n *HTMLNode) Attribute(name string) (string, bool) {
+	return htmlToken{Attributes: n.attributes}.attribute(name)
+}
+
+// HasClass reports whether the class attribute of an element contains the class
+func (n *HTMLNode) HasClass(class string) bool {
+	classes, _ := n.Attribute("class")
+	for _, c := range strings.Fields(classes) {
+		if c == class {
+			return true
+		}
+	}
+	return false
+}
+
+// Find returns the first descendant element of the node with the tag, or nil if there is none
+func (n *HTMLNode) Find(tag string) *HTMLNode {
+	for _, child := range n.Children {
+		if child.Tag == tag {
+			return child
+		}
+		if found := child.Find(tag); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// TextContent returns the decoded text of the node and its descendants
+func (n *HTMLNode) TextContent() string {
+	if n.IsText() {
+		return html.UnescapeString(n.Text)
+	}
+
+	text := &strings.Builder{}
+	for _, child := range n.Children {
+		text.WriteString(child.TextContent())
+	}
+	return text.String()
+}
+
+// InnerHTML returns the html of the children of the node
+func (n *HTMLNode) InnerHTML() string {
+	result := &strings.Builder{}
+	for _, child := range n.Children {
+		result.WriteString(child.OuterHTML())
+	}
+	return result.String()
+}
+
+// OuterHTML returns the html of the node
+func (n *HTMLNode) OuterHTML() string {
+	if n.IsText() {
+		return n.Text
+	}
+	if htmlVoidElements[n.Tag] {
+		return n.startTag(n.Tag)
+	}
+	return n.startTag(n.Tag) + n.InnerHTML() + "</" + n.Tag + ">"
+}
+
+// startTag returns the start tag of an element with its attributes, using name as the tag name
+func (n *HTMLNode) startTag(name string) string {
+	return inlineHTMLTag(htmlToken{Data: name, Attributes: n.attributes})
+}
+
+// htmlImpliedEndTags maps elements to the open elements their start tag closes, and the elements that stop the search for them.
+// It's the part of the html5 tree construction rules that matters for the html of editors, e.g. lists without closing </li> tags.
+var htmlImpliedEndTags = map[string]struct{ closes, boundaries []string }{
+	"li":    {[]string{"li"}, []string{"ul", "ol"}},
+	"dt":    {[]string{"dt", "dd"}, []string{"dl"}},
+	"dd":    {[]string{"dt", "dd"}, []string{"dl"}},
+	"tr":    {[]string{"tr"}, []string{"table", "thead", "tbody", "tfoot"}},
+	"td":    {[]string{"td", "th"}, []string{"tr", "table"}},
+	"th":    {[]string{"td", "th"}, []string{"tr", "table"}},
+	"thead": {[]string{"thead", "tbody", "tfoot"}, []string{"table"}},
+	"tbody": {[]string{"thead", "tbody", "tfoot"}, []string{"table"}},
+	"tfoot": {[]string{"thead", "tbody", "tfoot"}, []string{"table"}},
+}
+
+// htmlParagraphClosers are the elements whose start tag closes an open paragraph
+var htmlParagraphClosers = map[string]bool{
+	"address": true, "article": true, "aside": true, "blockquote": true, "details": true, "div": true, "dl": true,
+	"fieldset": true, "figcaption": true, "figure": true, "footer": true, "form": true, "h1": true, "h2": true,
+	"h3": true, "h4": true, "h5": true, "h6": true, "header": true, "hr": true, "main": true, "nav": true, "ol": true,
+	"p": true, "pre": true, "section": true, "table": true, "ul": true}
+
+// parseHTMLNodes parses an html document or fragment into nodes. Comments, doctypes and end tags without a start tag are dropped.
+func parseHTMLNodes(htmlData string) []*HTMLNode {
+	root := &HTMLNode{Tag: "#root"}
+	stack := []*HTMLNode{root}
+	for _, token := range tokenizeHTML(htmlData) {
+		switch token.Type {
+		case htmlTextToken:
+			parent := stack[len(stack)-1]
+			parent.Children = append(parent.Children, &HTMLNode{Text: token.Data})
+		case htmlStartTagToken:
+			stack = closeImpliedElements(stack, token.Data)
+			node := &HTMLNode{Tag: token.Data, attributes: token.Attributes}
+			parent := stack[len(stack)-1]
+			parent.Children = append(parent.Children, node)
+			if !token.SelfClosing && !htmlVoidElements[token.Data] {
+				stack = append(stack, node)
+			}
+		case htmlEndTagToken:
+			if i := openElementIndex(stack, []string{token.Data}, nil); i > 0 {
+				stack = stack[:i]
+			}
+		}
+	}
+
+	return root.Children
+}
+
+// closeImpliedElements closes the open elements that are closed by the start tag of the element
+func closeImpliedElements(stack []*HTMLNode, tag string) []*HTMLNode {
+	if htmlParagraphClosers[tag] {
+		if i := openElementIndex(stack, []string{"p"}, []string{"td", "th", "table", "li", "blockquote", "div"}); i > 0 {
+			stack = stack[:i]
+		}
+	}
+	if implied, ok := htmlImpliedEndTags[tag]; ok {
+		if i := openElementIndex(stack, implied.closes, implied.boundaries); i > 0 {
+			stack = stack[:i]
+		}
+	}
+	return stack
+}
+
+// openElementIndex returns the index in the stack of the innermost open element with one of the tags,
+// or -1 if there is none before an element with one of the boundary tags
+func openElementIndex(stack []*HTMLNode, tags, boundaries []string) int {
+	for i := len(stack) - 1; i > 0; i-- {
+		if containsString(tags, stack[i].Tag) {
+			return i
+		}
+		if containsString(boundaries, stack[i].Tag) {
+			return -1
+		}
+	}
+	return -1
+}
@@ -0,0 +1,198 @@
+package goeditorjs_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/davidscottmills/goeditorjs"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_HTMLImporter_ImportHTML(t *testing.T) {
+	testData := []struct {
+		html           string
+		expectedType   string
+		expectedResult string
+	}{
+		{html: `<h2>Heading <strong>two</strong></h2>`, expectedType: "header", expectedResult: `{"text":"Heading <b>two</b>","level":2}`},
+		{html: "<p style=\"text-align: center\">Some <em>text</em>\n  with   spaces</p>", expectedType: "paragraph",
+			expectedResult: `{"text":"Some <i>text</i> with spaces","alignment":"center"}`},
+		{html: `Loose <b>text</b> and <custom-element>custom</custom-element><br>`, expectedType: "paragraph",
+			expectedResult: `{"text":"Loose <b>text</b> and custom","alignment":"left"}`},
+		{html: `<p><a href="javascript:alert(1)" onclick="x">Link</a> <mark class="cdx-marker">marked</mark></p>`, expectedType: "paragraph",
+			expectedResult: `{"text":"<a>Link</a> <mark class=\"cdx-marker\">marked</mark>","alignment":"left"}`},
+		{html: `<ul><li>One<li>Two<ul><li>Nested</ul></ul>`,
+			expectedType: "list",
+			expectedResult: `{"style":"unordered","meta":{"start":0,"counterType":"","checked":false},"items":[` +
+				`{"content":"One","meta":{"start":0,"counterType":"","checked":false},"items":[]},` +
+				`{"content":"Two","meta":{"start":0,"counterType":"","checked":false},"items":[{"content":"Nested","meta":{"start":0,"counterType":"","checked":false},"items":[]}]}]}`},
+		{html: `<ol start="3" type="i"><li><p>One</p><p>paragraphs</p></li></ol>`,
+			expectedType: "list",
+			expectedResult: `{"style":"ordered","meta":{"start":3,"counterType":"lower-roman","checked":false},"items":[` +
+				`{"content":"One<br>paragraphs","meta":{"start":0,"counterType":"","checked":false},"items":[]}]}`},
+		{html: `<ul><li><label><input type="checkbox" checked> Done</label></li><li><input type="checkbox"> Todo</li></ul>`,
+			expectedType: "checklist", expectedResult: `{"items":[{"text":"Done","checked":true},{"text":"Todo","checked":false}]}`},
+		{html: `<ul><li><input type="checkbox" checked>Done<ul><li><input type="checkbox">Todo</li></ul></li></ul>`,
+			expectedType: "list",
+			expectedResult: `{"style":"checklist","meta":{"start":0,"counterType":"","checked":false},"items":[` +
+				`{"content":"Done","meta":{"start":0,"counterType":"","checked":true},"items":[{"content":"Todo","meta":{"start":0,"counterType":"","checked":false},"items":[]}]}]}`},
+		{html: `<img src="https://example.com/a.png" alt="A &amp; B" class="image-tool--withBorder">`, expectedType: "image",
+			expectedResult: `{"file":{"url":"https://example.com/a.png","name":"","size":0,"extension":""},"caption":"A &amp; B","withBorder":true,"withBackground":false,"stretched":false}`},
+		{html: `<figure><img src="a.png" alt="Alt"><figcaption>The <i>caption</i></figcaption></figure>`, expectedType: "image",
+			expectedResult: `{"file":{"url":"a.png","name":"","size":0,"extension":""},"caption":"The <i>caption</i>","withBorder":false,"withBackground":false,"stretched":false}`},
+		{html: `<blockquote><p>Quoted</p><p>text</p><footer>Author</footer></blockquote>`, expectedType: "quote",
+			expectedResult: `{"text":"Quoted<br>text","caption":"Author","alignment":"left"}`},
+		{html: `<figure style="text-align:center"><blockquote>Quoted</blockquote><figcaption><cite>Author</cite></figcaption></figure>`, expectedType: "quote",
+			expectedResult: `{"text":"Quoted","caption":"Author","alignment":"center"}`},
+		{html: `<table><tr><th>A<th>B<tr><td>1<td><b>2</b><tr><td>3</table>`, expectedType: "table",
+			expectedResult: `{"withHeadings":true,"content":[["A","B"],["1","<b>2</b>"],["3",""]]}`},
+		{html: `<table><tbody><tr><td>1</td></tr></tbody></table>`, expectedType: "table", expectedResult: `{"withHeadings":false,"content":[["1"]]}`},
+		{html: "<pre><code class=\"language-go\">\nfmt.Println(\"&lt;x&gt;\")</code></pre>", expectedType: "code",
+			expectedResult: `{"code":"fmt.Println(\"<x>\")","language":"go"}`},
+		{html: `<pre><div>line 1</div><div>line 2</div></pre>`, expectedType: "code", expectedResult: `{"code":"line 1\nline 2","language":""}`},
+		{html: `<hr>`, expectedType: "delimiter", expectedResult: `{}`},
+	}
+
+	importer := goeditorjs.NewDefaultHTMLImporter()
+	for _, td := range testData {
+		document, err := importer.ImportHTML(td.html)
+		require.NoError(t, err)
+		require.Len(t, document.Blocks, 1, td.html)
+		require.Equal(t, td.expectedType, document.Blocks[0].Type, td.html)
+		require.JSONEq(t, td.expectedResult, string(document.Blocks[0].Data), td.html)
+	}
+}
+
+func Test_HTMLImporter_ImportHTML_Document(t *testing.T) {
+	html := `<!DOCTYPE html><html><head><title>Title</title><style>p {}</style></head><body>
+	<h1>Title</h1>
+	<div class="content"><p>In a div</p>Loose text<p><img src="a.png">After the image</p></div>
+	<script>alert(1)</script>
+	</body></html>`
+
+	document, err := goeditorjs.NewDefaultHTMLImporter().ImportHTML(html)
+	require.NoError(t, err)
+
+	types := []string{}
+	for _, block := range document.Blocks {
+		types = append(types, block.Type)
+	}
+	require.Equal(t, []string{"header", "paragraph", "paragraph", "image", "paragraph"}, types)
+}
+
+func Test_HTMLImporter_UnknownElementPolicy(t *testing.T) {
+	html := `<section class="x"><p>Text</p></section><p>After</p>`
+	testData := []struct {
+		policy         goeditorjs.UnknownElementPolicy
+		expectedTypes  []string
+		expectedResult string
+	}{
+		{policy: goeditorjs.UnknownElementUnwrap, expectedTypes: []string{"paragraph", "paragraph"}, expectedResult: `{"text":"Text","alignment":"left"}`},
+		{policy: goeditorjs.UnknownElementDrop, expectedTypes: []string{"paragraph"}, expectedResult: `{"text":"After","alignment":"left"}`},
+		{policy: goeditorjs.UnknownElementRaw, expectedTypes: []string{"raw", "paragraph"}, expectedResult: `{"html":"<section class=\"x\"><p>Text</p></section>"}`},
+	}
+
+	for _, td := range testData {
+		importer := goeditorjs.NewDefaultHTMLImporter()
+		importer.UnknownElementPolicy = td.policy
+		unknown := []string{}
+		importer.OnUnknownElement = func(node *goeditorjs.HTMLNode) {
+			unknown = append(unknown, node.Tag)
+		}
+
+		document, err := importer.ImportHTML(html)
+		require.NoError(t, err)
+
+		types := []string{}
+		for _, block := range document.Blocks {
+			types = append(types, block.Type)
+		}
+		require.Equal(t, td.expectedTypes, types)
+		require.JSONEq(t, td.expectedResult, string(document.Blocks[0].Data))
+		require.Equal(t, []string{"section"}, unknown)
+	}
+}
+
+type testAsideConverter struct{}
+
+func (*testAsideConverter) Elements() []string {
+	return []string{"aside"}
+}
+
+func (*testAsideConverter) ConvertHTML(importer *goeditorjs.HTMLImporter, node *goeditorjs.HTMLNode) ([]goeditorjs.EditorJSBlock, error) {
+	if !node.HasClass("warning") {
+		return importer.ConvertBlocks(node.Children)
+	}
+	if node.Find("script") != nil {
+		return nil, errors.New("script in warning")
+	}
+
+	title := ""
+	if heading := node.Find("h3"); heading != nil {
+		title = heading.TextContent()
+	}
+	data, err := json.Marshal(map[string]string{"title": title, "message": importer.ConvertInline(node.Find("p"))})
+	return []goeditorjs.EditorJSBlock{{Type: "warning", Data: data}}, err
+}
+
+func Test_HTMLImporter_RegisterElementConverters(t *testing.T) {
+	importer := goeditorjs.NewDefaultHTMLImporter()
+	importer.RegisterElementConverters(&testAsideConverter{})
+
+	document, err := importer.ImportHTML(`<aside class="note warning"><h3>Title &amp; more</h3><p>The <b>message</b></p></aside><aside><h3>Heading</h3></aside>`)
+	require.NoError(t, err)
+	require.Len(t, document.Blocks, 2)
+	require.Equal(t, "warning", document.Blocks[0].Type)
+	require.JSONEq(t, `{"title":"Title & more","message":"The <b>message</b>"}`, string(document.Blocks[0].Data))
+	require.Equal(t, "header", document.Blocks[1].Type)
+
+	_, err = importer.ImportHTML(`<aside class="warning"><script>x</script></aside>`)
+	require.EqualError(t, err, "script in warning")
+
+	importer = goeditorjs.NewHTMLImporter()
+	document, err = importer.ImportHTML(`<h1>Heading</h1>`)
+	require.NoError(t, err)
+	require.Equal(t, "paragraph", document.Blocks[0].Type)
+}
+
+func Test_HTMLImporter_InlinePolicy(t *testing.T) {
+	html := `<p><span class="x">Span</span> <b onclick="x">bold</b></p>`
+
+	importer := goeditorjs.NewDefaultHTMLImporter()
+	document, err := importer.ImportHTML(html)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"text":"Span <b>bold</b>","alignment":"left"}`, string(document.Blocks[0].Data))
+
+	importer.InlinePolicy = nil
+	document, err = importer.ImportHTML(html)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"text":"<span class=\"x\">Span</span> <b onclick=\"x\">bold</b>","alignment":"left"}`, string(document.Blocks[0].Data))
+}
+
+func Test_HTMLImporter_Round_Trips(t *testing.T) {
+	ejs := `{"blocks":[
+		{"type":"header","data":{"text":"Title <b>bold</b>","level":2}},
+		{"type":"paragraph","data":{"text":"Some <i>text</i> with a <a href=\"https://example.com\">link</a>","alignment":"left"}},
+		{"type":"paragraph","data":{"text":"Centered","alignment":"center"}},
+		{"type":"list","data":{"style":"ordered","meta":{"start":2},"items":[{"content":"One","items":[{"content":"Nested","items":[]}]},{"content":"Two","items":[]}]}},
+		{"type":"checklist","data":{"items":[{"text":"Done","checked":true},{"text":"Todo","checked":false}]}},
+		{"type":"image","data":{"file":{"url":"https://example.com/a.png"},"caption":"Caption","stretched":true}},
+		{"type":"quote","data":{"text":"Quoted","caption":"Author","alignment":"left"}},
+		{"type":"table","data":{"withHeadings":true,"content":[["A","B"],["1","<b>2</b>"]]}},
+		{"type":"code","data":{"code":"fmt.Println(\"<x>\")","language":"go"}},
+		{"type":"delimiter","data":{}}
+	]}`
+
+	engine := goeditorjs.NewDefaultHTMLEngine()
+	html, err := engine.GenerateHTML(ejs)
+	require.NoError(t, err)
+
+	document, err := goeditorjs.NewDefaultHTMLImporter().ImportHTML(html)
+	require.NoError(t, err)
+	require.Len(t, document.Blocks, 10)
+
+	result, err := engine.GenerateHTMLFromDocument(document)
+	require.NoError(t, err)
+	require.Equal(t, html, result)
+}
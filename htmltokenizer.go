@@ -0,0 +1,222 @@
+package goeditorjs
+
+import (
+	"html"
+	"strings"
+)
+
+// htmlTokenType is the type of an htmlToken
+type htmlTokenType int
+
+const (
+	htmlTextToken htmlTokenType = iota
+	htmlStartTagToken
+	htmlEndTagToken
+	htmlCommentToken
+)
+
+// htmlAttribute is an attribute of a start tag. The value has its entities decoded.
+type htmlAttribute struct {
+	Name  string
+	Value string
+}
+
+// htmlToken is a token of an html fragment.
+// Data is the lower case tag name of tags, the raw text of text tokens and the content of comments.
+type htmlToken struct {
+	Type        htmlTokenType
+	Data        string
+	Attributes  []htmlAttribute
+	SelfClosing bool
+}
+
+// attribute returns the value of the named attribute of the token and whether it exists
+func (t htmlToken) attribute(name string) (string, bool) {
+	for _, attribute := range t.Attributes {
+		if attribute.Name == name {
+			return attribute.Value, true
+		}
+	}
+	return "", false
+}
+
+// htmlRawTextElements hold text up to their end tag, even if it looks like markup
+var htmlRawTextElements = map[string]bool{"script": true, "style": true, "textarea": true, "title": true}
+
+// htmlVoidElements never have content or an end tag
+var htmlVoidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true, "img": true, "input": true,
+	"link": true, "meta": true, "param": true, "source": true, "track": true, "wbr": true}
+
+// tokenizeHTML splits an html fragment into tokens.
+// It's a lenient tokenizer for the inline html produced by editor.js, not a full html5 parser:
+// a "<" that doesn't start a tag is text, and a tag that isn't closed before the end of the input is dropped.
+func tokenizeHTML(htmlData string) []htmlToken {
+	tokens := []htmlToken{}
+	text := &strings.Builder{}
+	flushText := func() {
+		if text.Len() > 0 {
+			tokens = append(tokens, htmlToken{Type: htmlTextToken, Data: text.String()})
+			text.Reset()
+		}
+	}
+
+	for i := 0; i < len(htmlData); {
+		if htmlData[i] != '<' || i+1 == len(htmlData) {
+			text.WriteByte(htmlData[i])
+			i++
+			continue
+		}
+
+		next := htmlData[i+1]
+		switch {
+		case strings.HasPrefix(htmlData[i:], "<!--"):
+			flushText()
+			end := strings.Index(htmlData[i+4:], "-->")
+			if end < 0 {
+				tokens = append(tokens, htmlToken{Type: htmlCommentToken, Data: htmlData[i+4:]})
+				return tokens
+			}
+			tokens = append(tokens, htmlToken{Type: htmlCommentToken, Data: htmlData[i+4 : i+4+end]})
+			i += 4 + end + 3
+		case next == '!' || next == '?':
+			// Doctypes, cdata sections and processing instructions are treated as comments
+			flushText()
+			end := strings.IndexByte(htmlData[i:], '>')
+			if end < 0 {
+				return tokens
+			}
+			tokens = append(tokens, htmlToken{Type: htmlCommentToken, Data: htmlData[i+2 : i+end]})
+			i += end + 1
+		case next == '/' && i+2 < len(htmlData) && isASCIILetter(htmlData[i+2]):
+			flushText()
+			end := strings.IndexByte(htmlData[i:], '>')
+			if end < 0 {
+				return tokens
+			}
+			name, _ := readTagName(htmlData[i+2 : i+end])
+			tokens = append(tokens, htmlToken{Type: htmlEndTagToken, Data: name})
+			i += end + 1
+		case isASCIILetter(next):
+			token, length, ok := readStartTag(htmlData[i:])
+			if !ok {
+				flushText()
+				return tokens
+			}
+			flushText()
+			tokens = append(tokens, token)
+			i += length
+
+			if htmlRawTextElements[token.Data] && !token.SelfClosing {
+				end := indexEndTag(htmlData[i:], token.Data)
+				if end < 0 {
+					end = len(htmlData) - i
+				}
+				if end > 0 {
+					tokens = append(tokens, htmlToken{Type: htmlTextToken, Data: htmlData[i : i+end]})
+				}
+				i += end
+			}
+		default:
+			text.WriteByte('<')
+			i++
+		}
+	}
+
+	flushText()
+	return tokens
+}
+
+// readStartTag reads the start tag at the beginning of s and returns it with its length in bytes
+func readStartTag(s string) (htmlToken, int, bool) {
+	name, i := readTagName(s[1:])
+	i++
+	token := htmlToken{Type: htmlStartTagToken, Data: name}
+
+	for i < len(s) {
+		switch {
+		case isHTMLSpace(s[i]):
+			i++
+		case s[i] == '>':
+			return token, i + 1, true
+		case s[i] == '/':
+			if i+1 < len(s) && s[i+1] == '>' {
+				token.SelfClosing = true
+				return token, i + 2, true
+			}
+			i++
+		default:
+			start := i
+			for i < len(s) && !isHTMLSpace(s[i]) && s[i] != '/' && s[i] != '>' && (s[i] != '=' || i == start) {
+				i++
+			}
+			attribute := htmlAttribute{Name: strings.ToLower(s[start:i])}
+
+			j := i
+			for j < len(s) && isHTMLSpace(s[j]) {
+				j++
+			}
+			if j < len(s) && s[j] == '=' {
+				i = j + 1
+				for i < len(s) && isHTMLSpace(s[i]) {
+					i++
+				}
+				if i < len(s) && (s[i] == '"' || s[i] == '\'') {
+					end := strings.IndexByte(s[i+1:], s[i])
+					if end < 0 {
+						return token, 0, false
+					}
+					attribute.Value = html.UnescapeString(s[i+1 : i+1+end])
+					i += end + 2
+				} else {
+					start := i
+					for i < len(s) && !isHTMLSpace(s[i]) && s[i] != '>' {
+						i++
+					}
+					attribute.Value = html.UnescapeString(s[start:i])
+				}
+			}
+
+			if _, ok := token.attribute(attribute.Name); !ok {
+				token.Attributes = append(token.Attributes, attribute)
+			}
+		}
+	}
+
+	return token, 0, false
+}
+
+// readTagName reads the lower case tag name at the beginning of s and returns it with its length in bytes
+func readTagName(s string) (string, int) {
+	i := 0
+	for i < len(s) && !isHTMLSpace(s[i]) && s[i] != '/' && s[i] != '>' {
+		i++
+	}
+	return strings.ToLower(s[:i]), i
+}
+
+// indexEndTag returns the index of the end tag of the named element in s, or -1 if there is none
+func indexEndTag(s, name string) int {
+	lower := strings.ToLower(s)
+	offset := 0
+	for {
+		i := strings.Index(lower[offset:], "</"+name)
+		if i < 0 {
+			return -1
+		}
+		i += offset
+		end := i + 2 + len(name)
+		if end == len(s) || isHTMLSpace(s[end]) || s[end] == '>' || s[end] == '/' {
+			return i
+		}
+		offset = end
+	}
+}
+
+func isASCIILetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isHTMLSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
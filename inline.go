@@ -0,0 +1,797 @@
+package goeditorjs
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// InlineMarkdownOptions are the options of the conversion of editor.js inline html to markdown
+type InlineMarkdownOptions struct {
+	// Mark is the delimiter put around marked text, e.g. "==". If empty, mark elements are handled as unsupported tags.
+	Mark string
+	// StripUnsupported removes tags that don't have a markdown equivalent, keeping their content.
+	// Otherwise they are kept as inline html.
+	StripUnsupported bool
+}
+
+// DefaultInlineMarkdownOptions are the default options of the conversion of inline html to markdown
+var DefaultInlineMarkdownOptions = &InlineMarkdownOptions{
+	Mark: "=="}
+
+// inlineMarkdownDelimiters are the markdown delimiters of the supported emphasis elements
+var inlineMarkdownDelimiters = map[string]string{
+	"b": "**", "strong": "**",
+	"i": "*", "em": "*",
+	"s": "~~", "del": "~~", "strike": "~~",
+}
+
+// inlineURLPolicy filters the urls of converted links
+var inlineURLPolicy = &SanitizePolicy{URLSchemes: []string{"http", "https", "mailto", "tel"}}
+
+// inlineMarkdownFrame is an element being converted, its content is collected until the element is closed
+type inlineMarkdownFrame struct {
+	tag       string
+	delimiter string
+	href      string
+	content   strings.Builder
+}
+
+// InlineHTMLToMarkdown converts editor.js inline html to markdown.
+// Bold, italic, strikethrough, links, inline code and marked text are converted, entities are decoded
+// and characters that have a meaning in markdown are escaped. If options is nil, DefaultInlineMarkdownOptions are used.
+func InlineHTMLToMarkdown(htmlData string, options *InlineMarkdownOptions) string {
+	if options == nil {
+		options = DefaultInlineMarkdownOptions
+	}
+
+	root := &inlineMarkdownFrame{}
+	stack := []*inlineMarkdownFrame{root}
+	atLineStart := true
+	dropped, droppedDepth := "", 0
+	for _, token := range tokenizeHTML(htmlData) {
+		current := stack[len(stack)-1]
+		if droppedDepth > 0 {
+			if token.Data == dropped && token.Type == htmlStartTagToken && !token.SelfClosing {
+				droppedDepth++
+			} else if token.Data == dropped && token.Type == htmlEndTagToken {
+				droppedDepth--
+			}
+			continue
+		}
+
+		if current.tag == "code" && !(token.Type == htmlEndTagToken && token.Data == "code") {
+			// Code is taken literally, markup inside of it is ignored
+			if token.Type == htmlTextToken {
+				current.content.WriteString(html.UnescapeString(token.Data))
+			}
+			continue
+		}
+
+		switch token.Type {
+		case htmlTextToken:
+			text := html.UnescapeString(token.Data)
+			current.content.WriteString(escapeMarkdownText(text, atLineStart && len(stack) == 1))
+			if text != "" {
+				atLineStart = false
+			}
+		case htmlCommentToken:
+			if !options.StripUnsupported {
+				current.content.WriteString("<!--" + token.Data + "-->")
+			}
+		case htmlStartTagToken:
+			frame := &inlineMarkdownFrame{tag: token.Data, delimiter: inlineMarkdownDelimiters[token.Data]}
+			switch {
+			case token.Data == "br":
+				current.content.WriteString("<br>")
+				atLineStart = true
+				continue
+			case token.Data == "a":
+				href, _ := token.attribute("href")
+				frame.href = inlineURLPolicy.SanitizeURL(href)
+			case token.Data == "code":
+			case token.Data == "mark" && options.Mark != "":
+				frame.delimiter = options.Mark
+			case frame.delimiter != "":
+			default:
+				if options.StripUnsupported {
+					if sanitizeDroppedElements[token.Data] && !token.SelfClosing && !htmlVoidElements[token.Data] {
+						dropped, droppedDepth = token.Data, 1
+					}
+				} else {
+					current.content.WriteString(inlineHTMLTag(token))
+				}
+				continue
+			}
+			if token.SelfClosing {
+				continue
+			}
+			stack = append(stack, frame)
+			atLineStart = false
+		case htmlEndTagToken:
+			i := len(stack) - 1
+			for i > 0 && stack[i].tag != token.Data {
+				i--
+			}
+			if i == 0 {
+				if !options.StripUnsupported && !isInlineMarkdownTag(token.Data, options) {
+					current.content.WriteString("</" + token.Data + ">")
+				}
+				continue
+			}
+			for len(stack) > i {
+				stack = closeInlineMarkdownFrame(stack)
+			}
+		}
+	}
+
+	for len(stack) > 1 {
+		stack = closeInlineMarkdownFrame(stack)
+	}
+
+	return root.content.String()
+}
+
+// isInlineMarkdownTag reports whether the tag is converted to markdown
+func isInlineMarkdownTag(tag string, options *InlineMarkdownOptions) bool {
+	_, ok := inlineMarkdownDelimiters[tag]
+	return ok || tag == "a" || tag == "code" || tag == "br" || (tag == "mark" && options.Mark != "")
+}
+
+// closeInlineMarkdownFrame writes the markdown of the innermost frame to its parent and removes it from the stack
+func closeInlineMarkdownFrame(stack []*inlineMarkdownFrame) []*inlineMarkdownFrame {
+	frame, parent := stack[len(stack)-1], stack[len(stack)-2]
+	content := frame.content.String()
+
+	switch {
+	case frame.tag == "code":
+		parent.content.WriteString(markdownCodeSpan(content))
+	case frame.tag == "a":
+		if frame.href == "" {
+			parent.content.WriteString(content)
+		} else {
+			parent.content.WriteString(fmt.Sprintf("[%s](%s)", content, markdownLinkDestination(frame.href)))
+		}
+	default:
+		// Delimiters next to whitespace don't open or close emphasis, so the whitespace is moved outside of them
+		trimmed := strings.TrimLeftFunc(content, unicode.IsSpace)
+		leading := content[:len(content)-len(trimmed)]
+		core := strings.TrimRightFunc(trimmed, unicode.IsSpace)
+		trailing := trimmed[len(core):]
+		if core == "" {
+			parent.content.WriteString(content)
+		} else {
+			parent.content.WriteString(leading + frame.delimiter + core + frame.delimiter + trailing)
+		}
+	}
+
+	return stack[:len(stack)-1]
+}
+
+// inlineHTMLTag serializes a start tag that is kept as inline html
+func inlineHTMLTag(token htmlToken) string {
+	result := "<" + token.Data
+	for _, attribute := range token.Attributes {
+		result += " " + attribute.Name + `="` + html.EscapeString(attribute.Value) + `"`
+	}
+	if token.SelfClosing {
+		result += "/"
+	}
+	return result + ">"
+}
+
+// markdownCodeSpan wraps code in a code span delimited by more backticks than any run of backticks inside of it
+func markdownCodeSpan(code string) string {
+	if code == "" {
+		return ""
+	}
+
+	fence := "`"
+	for _, run := range backtickRuns.FindAllString(code, -1) {
+		if len(run) >= len(fence) {
+			fence = strings.Repeat("`", len(run)+1)
+		}
+	}
+
+	if strings.HasPrefix(code, "`") || strings.HasSuffix(code, "`") {
+		code = " " + code + " "
+	}
+	return fence + code + fence
+}
+
+// markdownLinkDestination returns the url as a markdown link destination
+func markdownLinkDestination(url string) string {
+	if strings.ContainsAny(url, " ()<>") {
+		return "<" + strings.NewReplacer("<", "%3C", ">", "%3E").Replace(url) + ">"
+	}
+	return url
+}
+
+// markdownSpecialCharacters are escaped wherever they appear in text
+const markdownSpecialCharacters = "\\`*_[]<~"
+
+// escapeMarkdownText escapes the characters of text that have a meaning in markdown.
+// Characters that only have a meaning at the start of a line are escaped if atLineStart is set.
+func escapeMarkdownText(text string, atLineStart bool) string {
+	lineStart := -1
+	if atLineStart {
+		lineStart = markdownLineStartIndex(text)
+	}
+
+	result := &strings.Builder{}
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case strings.IndexByte(markdownSpecialCharacters, c) >= 0,
+			c == '&' && htmlEntity.MatchString(text[i:]),
+			c == '=' && i+1 < len(text) && text[i+1] == '=',
+			i == lineStart:
+			result.WriteByte('\\')
+		}
+		result.WriteByte(c)
+	}
+	return result.String()
+}
+
+// markdownLineStartIndex returns the index of the character that would make text start with a block marker,
+// like a heading, quote or list marker, or -1 if there is none
+func markdownLineStartIndex(text string) int {
+	i := 0
+	for i < len(text) && text[i] == ' ' {
+		i++
+	}
+	if i == len(text) {
+		return -1
+	}
+
+	followedBySpace := i+1 == len(text) || text[i+1] == ' '
+	switch {
+	case text[i] == '#' || text[i] == '>':
+		return i
+	case (text[i] == '-' || text[i] == '+') && followedBySpace:
+		return i
+	}
+
+	digits := i
+	for digits < len(text) && text[digits] >= '0' && text[digits] <= '9' {
+		digits++
+	}
+	if digits > i && digits < len(text) && (text[digits] == '.' || text[digits] == ')') &&
+		(digits+1 == len(text) || text[digits+1] == ' ') {
+		return digits
+	}
+	return -1
+}
+
+// inlineMarkdownOptionsContextKey is the context key of the InlineMarkdownOptions
+type inlineMarkdownOptionsContextKey struct{}
+
+// ContextWithInlineMarkdownOptions returns a copy of ctx that carries the InlineMarkdownOptions used by the built-in handlers
+func ContextWithInlineMarkdownOptions(ctx context.Context, options *InlineMarkdownOptions) context.Context {
+	return context.WithValue(ctx, inlineMarkdownOptionsContextKey{}, options)
+}
+
+// InlineMarkdownOptionsFromContext returns the InlineMarkdownOptions carried by ctx, or DefaultInlineMarkdownOptions if there are none
+func InlineMarkdownOptionsFromContext(ctx context.Context) *InlineMarkdownOptions {
+	if options, ok := ctx.Value(inlineMarkdownOptionsContextKey{}).(*InlineMarkdownOptions); ok && options != nil {
+		return options
+	}
+	return DefaultInlineMarkdownOptions
+}
+
+// InlineMarkdownToHTML converts inline markdown to editor.js inline html, the reverse of InlineHTMLToMarkdown.
+// Emphasis, strikethrough, marked text, inline code and links are converted, inline html is kept and the markup
+// characters of the text are escaped. Line breaks are spaces, unless they are hard line breaks.
+func InlineMarkdownToHTML(markdown string) string {
+	return markdownInlineToHTML(markdown, " ")
+}
+
+// markdownInline is a piece of converted inline markdown. Delimiter runs and link brackets are pieces of their own,
+// as the emphasis and links they belong to are only known once their closers are found.
+type markdownInline struct {
+	text string
+	// delimiter is the character of a delimiter run, runLength the number of its characters
+	// and length the number of its characters that aren't matched with another delimiter run yet
+	delimiter         byte
+	runLength, length int
+	canOpen, canClose bool
+	// opening and closing are the tags of the emphasis opened and closed by the delimiter run
+	opening, closing string
+	// previous and next are the neighbours of the delimiter run on the delimiter stack
+	previous, next *markdownInline
+	// bracket is set for the "[" and "![" opening links, bottom is the top of the delimiter stack when they were found
+	bracket, image bool
+	bottom         *markdownInline
+}
+
+// markdownOpenersKey identifies the closers that share the bottom of the delimiter stack to search for openers
+type markdownOpenersKey struct {
+	delimiter byte
+	canOpen   bool
+	mod3      int
+}
+
+// markdownInlineParser converts inline markdown in a single pass. Like the CommonMark reference parsers, it matches
+// emphasis with a delimiter stack and links with a bracket stack, so untrusted markdown is converted in linear time.
+type markdownInlineParser struct {
+	markdown   string
+	softBreak  string
+	inlines    []*markdownInline
+	delimiters *markdownInline
+	brackets   []*markdownInline
+	// inactiveBrackets is the number of brackets at the bottom of the bracket stack that can't open links,
+	// as links can't contain other links
+	inactiveBrackets int
+	// codeSpanMisses are the lengths of the backtick runs that aren't closed in the rest of the markdown
+	codeSpanMisses map[int]bool
+	// unclosedComment is set once an html comment is found that isn't closed in the rest of the markdown
+	unclosedComment bool
+}
+
+// markdownInlineSpecial are the characters that may start markup in inline markdown
+const markdownInlineSpecial = "\\`![]<&>*_~= \n"
+
+// markdownInlineToHTML converts inline markdown to html, writing softBreak for line breaks that aren't hard line breaks
+func markdownInlineToHTML(markdown, softBreak string) string {
+	p := &markdownInlineParser{markdown: markdown, softBreak: softBreak, codeSpanMisses: map[int]bool{}}
+	p.parse()
+	p.processEmphasis(nil)
+
+	result := &strings.Builder{}
+	for _, inline := range p.inlines {
+		result.WriteString(inline.closing)
+		if inline.delimiter != 0 {
+			result.WriteString(strings.Repeat(string(inline.delimiter), inline.length))
+		} else {
+			result.WriteString(inline.text)
+		}
+		result.WriteString(inline.opening)
+	}
+	return strings.TrimRight(result.String(), " ")
+}
+
+func (p *markdownInlineParser) parse() {
+	markdown := p.markdown
+	for i := 0; i < len(markdown); {
+		c := markdown[i]
+		switch {
+		case c == '\\' && i+1 < len(markdown) && markdown[i+1] == '\n':
+			p.text("<br>")
+			i = skipLineIndent(markdown, i+2)
+		case c == '\\' && i+1 < len(markdown) && isASCIIPunctuation(markdown[i+1]):
+			p.text(html.EscapeString(markdown[i+1 : i+2]))
+			i += 2
+		case c == '`':
+			i = p.codeSpan(i)
+		case c == '!' && i+1 < len(markdown) && markdown[i+1] == '[':
+			// Images can't be inline in editor.js, they are kept as links
+			p.openBracket("![", true)
+			i += 2
+		case c == '[':
+			p.openBracket("[", false)
+			i++
+		case c == ']':
+			i = p.closeBracket(i)
+		case c == '<':
+			i = p.angleBracket(i)
+		case c == '&':
+			if entity := htmlEntity.FindString(markdown[i:]); entity != "" {
+				p.text(entity)
+				i += len(entity)
+			} else {
+				p.text("&amp;")
+				i++
+			}
+		case c == '>':
+			p.text("&gt;")
+			i++
+		case c == '*' || c == '_' || c == '~' || c == '=':
+			i = p.delimiterRun(i)
+		case c == ' ' || c == '\n':
+			n := i
+			for n < len(markdown) && markdown[n] == ' ' {
+				n++
+			}
+			switch {
+			case n < len(markdown) && markdown[n] == '\n' && n-i >= 2:
+				p.text("<br>")
+				i = skipLineIndent(markdown, n+1)
+			case n < len(markdown) && markdown[n] == '\n':
+				p.text(p.softBreak)
+				i = skipLineIndent(markdown, n+1)
+			default:
+				p.text(markdown[i:n])
+				i = n
+			}
+		default:
+			n := i + 1
+			for n < len(markdown) && strings.IndexByte(markdownInlineSpecial, markdown[n]) < 0 {
+				n++
+			}
+			p.text(markdown[i:n])
+			i = n
+		}
+	}
+}
+
+// text adds converted text
+func (p *markdownInlineParser) text(text string) {
+	p.inlines = append(p.inlines, &markdownInline{text: text})
+}
+
+// codeSpan converts the code span starting with the backtick run at i, returning its end
+func (p *markdownInlineParser) codeSpan(i int) int {
+	n := runLength(p.markdown, i)
+	if !p.codeSpanMisses[n] {
+		if end := codeSpanEnd(p.markdown, i+n, n); end >= 0 {
+			p.text(`<code class="inline-code">` + html.EscapeString(codeSpanContent(p.markdown[i+n:end])) + "</code>")
+			return end + n
+		}
+		// Every later backtick run of this length is in the part of the markdown that was just searched
+		p.codeSpanMisses[n] = true
+	}
+	p.text(p.markdown[i : i+n])
+	return i + n
+}
+
+// angleBracket converts the autolink or inline html starting with the "<" at i, returning its end
+func (p *markdownInlineParser) angleBracket(i int) int {
+	markdown := p.markdown
+	if match := markdownAutolink.FindStringSubmatch(markdown[i:]); match != nil {
+		href := match[1]
+		if !strings.Contains(href, ":") {
+			href = "mailto:" + href
+		}
+		p.text(`<a href="` + html.EscapeString(href) + `">` + html.EscapeString(match[1]) + "</a>")
+		return i + len(match[0])
+	}
+
+	if strings.HasPrefix(markdown[i:], "<!--") {
+		if !p.unclosedComment {
+			if end := strings.Index(markdown[i+4:], "-->"); end >= 0 {
+				p.text(markdown[i : i+4+end+3])
+				return i + 4 + end + 3
+			}
+			p.unclosedComment = true
+		}
+	} else if tag := markdownInlineHTML.FindString(markdown[i:]); tag != "" {
+		p.text(tag)
+		return i + len(tag)
+	}
+
+	p.text("&lt;")
+	return i + 1
+}
+
+// delimiterRun adds the delimiter run at i, returning its end. Whether it can open or close emphasis
+// depends on the characters around it, as described by CommonMark.
+func (p *markdownInlineParser) delimiterRun(i int) int {
+	markdown := p.markdown
+	c := markdown[i]
+	n := runLength(markdown, i)
+	if (c == '~' || c == '=') && n != 2 {
+		p.text(markdown[i : i+n])
+		return i + n
+	}
+
+	before, after := ' ', ' '
+	if i > 0 {
+		before, _ = utf8.DecodeLastRuneInString(markdown[:i])
+	}
+	if i+n < len(markdown) {
+		after, _ = utf8.DecodeRuneInString(markdown[i+n:])
+	}
+	leftFlanking := !unicode.IsSpace(after) && (!isMarkdownPunctuation(after) || unicode.IsSpace(before) || isMarkdownPunctuation(before))
+	rightFlanking := !unicode.IsSpace(before) && (!isMarkdownPunctuation(before) || unicode.IsSpace(after) || isMarkdownPunctuation(after))
+
+	inline := &markdownInline{delimiter: c, runLength: n, length: n, canOpen: leftFlanking, canClose: rightFlanking}
+	if c == '_' {
+		// Underscores inside of words don't open or close emphasis
+		inline.canOpen = leftFlanking && (!rightFlanking || isMarkdownPunctuation(before))
+		inline.canClose = rightFlanking && (!leftFlanking || isMarkdownPunctuation(after))
+	}
+	p.inlines = append(p.inlines, inline)
+	if inline.canOpen || inline.canClose {
+		inline.previous = p.delimiters
+		if p.delimiters != nil {
+			p.delimiters.next = inline
+		}
+		p.delimiters = inline
+	}
+	return i + n
+}
+
+// removeDelimiter removes a delimiter run from the delimiter stack
+func (p *markdownInlineParser) removeDelimiter(inline *markdownInline) {
+	if inline.previous != nil {
+		inline.previous.next = inline.next
+	}
+	if inline.next != nil {
+		inline.next.previous = inline.previous
+	} else {
+		p.delimiters = inline.previous
+	}
+}
+
+// openBracket adds a bracket that may open a link
+func (p *markdownInlineParser) openBracket(text string, image bool) {
+	inline := &markdownInline{text: text, bracket: true, image: image, bottom: p.delimiters}
+	p.inlines = append(p.inlines, inline)
+	p.brackets = append(p.brackets, inline)
+}
+
+// closeBracket converts the "]" at i and the destination following it to a link if it closes a bracket, returning its end
+func (p *markdownInlineParser) closeBracket(i int) int {
+	if len(p.brackets) == 0 {
+		p.text("]")
+		return i + 1
+	}
+
+	opener := p.brackets[len(p.brackets)-1]
+	p.brackets = p.brackets[:len(p.brackets)-1]
+	active := opener.image || len(p.brackets) >= p.inactiveBrackets
+	if p.inactiveBrackets > len(p.brackets) {
+		p.inactiveBrackets = len(p.brackets)
+	}
+
+	destination, end, ok := "", 0, false
+	if active {
+		destination, end, ok = parseMarkdownLinkDestination(p.markdown, i+1)
+	}
+	if !ok {
+		p.text("]")
+		return i + 1
+	}
+
+	opener.text = `<a href="` + html.EscapeString(destination) + `">`
+	p.processEmphasis(opener.bottom)
+	p.text("</a>")
+	if !opener.image {
+		p.inactiveBrackets = len(p.brackets)
+	}
+	return end
+}
+
+// processEmphasis matches the delimiter runs above bottom on the delimiter stack to emphasis and removes them from the stack,
+// following the "process emphasis" procedure of CommonMark
+func (p *markdownInlineParser) processEmphasis(bottom *markdownInline) {
+	var first *markdownInline
+	for inline := p.delimiters; inline != nil && inline != bottom; inline = inline.previous {
+		first = inline
+	}
+
+	openersBottom := map[markdownOpenersKey]*markdownInline{}
+	for closer := first; closer != nil; {
+		if !closer.canClose {
+			closer = closer.next
+			continue
+		}
+
+		key := markdownOpenersKey{delimiter: closer.delimiter, canOpen: closer.canOpen, mod3: closer.runLength % 3}
+		lower, ok := openersBottom[key]
+		if !ok {
+			lower = bottom
+		}
+		opener := closer.previous
+		for opener != nil && opener != bottom && opener != lower && !opener.opens(closer) {
+			opener = opener.previous
+		}
+
+		if opener == nil || opener == bottom || opener == lower {
+			// There's no opener for this closer, nor for the later closers of the same kind below this one
+			openersBottom[key] = closer.previous
+			next := closer.next
+			if !closer.canOpen {
+				p.removeDelimiter(closer)
+			}
+			closer = next
+			continue
+		}
+
+		use, tag := markdownEmphasis(opener, closer)
+		opener.length -= use
+		closer.length -= use
+		opener.opening = "<" + tag + ">" + opener.opening
+		closer.closing += "</" + strings.Fields(tag)[0] + ">"
+
+		// The delimiter runs between the opener and the closer can't be matched anymore
+		opener.next = closer
+		closer.previous = opener
+		if opener.length == 0 {
+			p.removeDelimiter(opener)
+		}
+		if closer.length == 0 {
+			next := closer.next
+			p.removeDelimiter(closer)
+			closer = next
+		}
+	}
+
+	p.delimiters = bottom
+	if bottom != nil {
+		bottom.next = nil
+	}
+}
+
+// opens reports whether the delimiter run can open the emphasis closed by closer
+func (inline *markdownInline) opens(closer *markdownInline) bool {
+	if !inline.canOpen || inline.delimiter != closer.delimiter {
+		return false
+	}
+	// A run that can both open and close only matches a run of a length that doesn't add up to a multiple of three,
+	// so that e.g. "*a**b*" is a single emphasis
+	bothWays := inline.canClose || closer.canOpen
+	return !bothWays || (inline.runLength+closer.runLength)%3 != 0 || (inline.runLength%3 == 0 && closer.runLength%3 == 0)
+}
+
+// markdownEmphasis returns the number of delimiter characters used by the emphasis of opener and closer and its html tag
+func markdownEmphasis(opener, closer *markdownInline) (int, string) {
+	switch {
+	case closer.delimiter == '~':
+		return 2, "s"
+	case closer.delimiter == '=':
+		return 2, `mark class="cdx-marker"`
+	case opener.length >= 2 && closer.length >= 2:
+		return 2, "b"
+	}
+	return 1, "i"
+}
+
+// isMarkdownPunctuation reports whether r is punctuation for the flanking rules of delimiter runs
+func isMarkdownPunctuation(r rune) bool {
+	if r < utf8.RuneSelf {
+		return isASCIIPunctuation(byte(r))
+	}
+	return unicode.IsPunct(r) || unicode.IsSymbol(r)
+}
+
+var (
+	markdownAutolink   = regexp.MustCompile(`^<([A-Za-z][A-Za-z0-9+.-]{1,31}:[^\s<>]*|[A-Za-z0-9.!#$%&'*+/=?^_{|}~-]+@[A-Za-z0-9](?:[A-Za-z0-9-]*[A-Za-z0-9])?(?:\.[A-Za-z0-9](?:[A-Za-z0-9-]*[A-Za-z0-9])?)*)>`)
+	markdownInlineHTML = regexp.MustCompile(`^(?:<[A-Za-z][A-Za-z0-9-]*(?:\s+[A-Za-z_:][A-Za-z0-9_.:-]*(?:\s*=\s*(?:[^\s"'=<>` + "`" + `]+|'[^']*'|"[^"]*"))?)*\s*/?>|</[A-Za-z][A-Za-z0-9-]*\s*>)`)
+)
+
+// runLength returns the number of repetitions of the character at i
+func runLength(markdown string, i int) int {
+	n := 1
+	for i+n < len(markdown) && markdown[i+n] == markdown[i] {
+		n++
+	}
+	return n
+}
+
+// codeSpanEnd returns the index of the run of exactly n backticks closing a code span, or -1 if there is none
+func codeSpanEnd(markdown string, from, n int) int {
+	for j := from; j < len(markdown); {
+		if markdown[j] != '`' {
+			j++
+			continue
+		}
+		k := runLength(markdown, j)
+		if k == n {
+			return j
+		}
+		j += k
+	}
+	return -1
+}
+
+// codeSpanContent normalizes the content of a code span, line breaks become spaces and a single surrounding space is removed
+func codeSpanContent(code string) string {
+	code = strings.ReplaceAll(code, "\n", " ")
+	if len(code) > 2 && code[0] == ' ' && code[len(code)-1] == ' ' && strings.Trim(code, " ") != "" {
+		code = code[1 : len(code)-1]
+	}
+	return code
+}
+
+// maxLinkDestinationParentheses is the number of nested parentheses allowed in a link destination, as in CommonMark.
+// It bounds how far the destination of a link is looked for.
+const maxLinkDestinationParentheses = 32
+
+// parseMarkdownLinkDestination parses the parenthesized destination and optional title following the label of
+// an inline link at i, returning the unescaped destination and the end of the link
+func parseMarkdownLinkDestination(markdown string, i int) (string, int, bool) {
+	if i >= len(markdown) || markdown[i] != '(' {
+		return "", 0, false
+	}
+
+	j := skipMarkdownLinkSpace(markdown, i+1)
+	start := j
+	destination := ""
+	if j < len(markdown) && markdown[j] == '<' {
+		for j++; j < len(markdown) && markdown[j] != '>'; j++ {
+			switch markdown[j] {
+			case '\\':
+				j++
+			case '<', '\n':
+				return "", 0, false
+			}
+		}
+		if j >= len(markdown) {
+			return "", 0, false
+		}
+		destination = markdown[start+1 : j]
+		j++
+	} else {
+		depth := 0
+	destination:
+		for ; j < len(markdown); j++ {
+			switch c := markdown[j]; {
+			case c == '\\' && j+1 < len(markdown) && isASCIIPunctuation(markdown[j+1]):
+				j++
+			case c <= ' ':
+				break destination
+			case c == '(':
+				depth++
+				if depth > maxLinkDestinationParentheses {
+					return "", 0, false
+				}
+			case c == ')':
+				if depth == 0 {
+					break destination
+				}
+				depth--
+			}
+		}
+		if depth > 0 {
+			return "", 0, false
+		}
+		destination = markdown[start:j]
+	}
+
+	// The title is optional, it has to be separated from the destination
+	k := skipMarkdownLinkSpace(markdown, j)
+	if k > j && k < len(markdown) && strings.IndexByte(`"'(`, markdown[k]) >= 0 {
+		closing := markdown[k]
+		if closing == '(' {
+			closing = ')'
+		}
+		for k++; k < len(markdown) && markdown[k] != closing; k++ {
+			switch {
+			case markdown[k] == '\\':
+				k++
+			case closing == ')' && markdown[k] == '(':
+				return "", 0, false
+			}
+		}
+		if k >= len(markdown) {
+			return "", 0, false
+		}
+		k = skipMarkdownLinkSpace(markdown, k+1)
+	}
+	if k >= len(markdown) || markdown[k] != ')' {
+		return "", 0, false
+	}
+	return unescapeMarkdown(destination), k + 1, true
+}
+
+// skipMarkdownLinkSpace returns the index of the first character at or after i that isn't a space, a tab or the first line break
+func skipMarkdownLinkSpace(markdown string, i int) int {
+	lineBreak := false
+	for ; i < len(markdown); i++ {
+		switch {
+		case markdown[i] == ' ' || markdown[i] == '\t':
+		case markdown[i] == '\n' && !lineBreak:
+			lineBreak = true
+		default:
+			return i
+		}
+	}
+	return i
+}
+
+// skipLineIndent returns the index of the first character of the line starting at i that isn't a space
+func skipLineIndent(markdown string, i int) int {
+	for i < len(markdown) && markdown[i] == ' ' {
+		i++
+	}
+	return i
+}
+
+func isASCIIAlphanumeric(c byte) bool {
+	return isASCIILetter(c) || (c >= '0' && c <= '9')
+}
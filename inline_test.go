@@ -0,0 +1,156 @@
+package goeditorjs_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/davidscottmills/goeditorjs"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_InlineHTMLToMarkdown(t *testing.T) {
+	testData := []struct {
+		data           string
+		expectedResult string
+	}{
+		{data: `Plain text`, expectedResult: `Plain text`},
+		{data: `<b>Bold</b> and <strong>strong</strong>`, expectedResult: `**Bold** and **strong**`},
+		{data: `<i>Italic</i> and <em>emphasis</em>`, expectedResult: `*Italic* and *emphasis*`},
+		{data: `<s>Struck</s>`, expectedResult: `~~Struck~~`},
+		{data: `<b>Bold <i>and italic</i></b>`, expectedResult: `**Bold *and italic***`},
+		{data: `foo<i>bar</i>baz`, expectedResult: `foo*bar*baz`},
+		{data: `<b>Bold </b>text`, expectedResult: `**Bold** text`},
+		{data: `<b> </b>text`, expectedResult: ` text`},
+		{data: `<a href="https://example.com">Link</a>`, expectedResult: `[Link](https://example.com)`},
+		{data: `<a href="https://example.com/a b">Link</a>`, expectedResult: `[Link](<https://example.com/a b>)`},
+		{data: `<a href="javascript:alert(1)">Link</a>`, expectedResult: `Link`},
+		{data: `<a>Anchor</a>`, expectedResult: `Anchor`},
+		{data: `<code class="inline-code">fmt.Println("*")</code>`, expectedResult: "`fmt.Println(\"*\")`"},
+		{data: "<code>a ` b</code>", expectedResult: "``a ` b``"},
+		{data: "<code>`tick`</code>", expectedResult: "`` `tick` ``"},
+		{data: `<code>&lt;b&gt;</code>`, expectedResult: "`<b>`"},
+		{data: `<mark class="cdx-marker">Marked</mark>`, expectedResult: `==Marked==`},
+		{data: `Line<br>break`, expectedResult: `Line<br>break`},
+		{data: `Non&nbsp;breaking &amp; &lt;tag&gt;`, expectedResult: "Non breaking & \\<tag>"},
+		{data: `2 * 3 = 6, snake_case, [x], a\b`, expectedResult: `2 \* 3 = 6, snake\_case, \[x\], a\\b`},
+		{data: `# Not a heading`, expectedResult: `\# Not a heading`},
+		{data: `- Not a list`, expectedResult: `\- Not a list`},
+		{data: `1. Not a list`, expectedResult: `1\. Not a list`},
+		{data: `> Not a quote`, expectedResult: `\> Not a quote`},
+		{data: `Line<br>- Not a list`, expectedResult: `Line<br>\- Not a list`},
+		{data: `Text - with # signs`, expectedResult: `Text - with # signs`},
+		{data: `&amp;copy;`, expectedResult: `\&copy;`},
+		{data: `<u class="cdx-underline">Under</u>`, expectedResult: `<u class="cdx-underline">Under</u>`},
+		{data: `<b>Unclosed`, expectedResult: `**Unclosed**`},
+		{data: `Stray</b>`, expectedResult: `Stray`},
+	}
+
+	for _, td := range testData {
+		require.Equal(t, td.expectedResult, goeditorjs.InlineHTMLToMarkdown(td.data, nil), td.data)
+	}
+}
+
+func Test_InlineHTMLToMarkdown_Options(t *testing.T) {
+	data := `<mark class="cdx-marker">Marked</mark> <u class="cdx-underline">under</u><script>x</script>`
+
+	result := goeditorjs.InlineHTMLToMarkdown(data, &goeditorjs.InlineMarkdownOptions{Mark: "=="})
+	require.Equal(t, `==Marked== <u class="cdx-underline">under</u><script>x</script>`, result)
+
+	result = goeditorjs.InlineHTMLToMarkdown(data, &goeditorjs.InlineMarkdownOptions{})
+	require.Equal(t, `<mark class="cdx-marker">Marked</mark> <u class="cdx-underline">under</u><script>x</script>`, result)
+
+	result = goeditorjs.InlineHTMLToMarkdown(data, &goeditorjs.InlineMarkdownOptions{StripUnsupported: true})
+	require.Equal(t, `Marked under`, result)
+}
+
+func Test_InlineMarkdownOptionsFromContext(t *testing.T) {
+	require.Equal(t, goeditorjs.DefaultInlineMarkdownOptions, goeditorjs.InlineMarkdownOptionsFromContext(context.Background()))
+
+	options := &goeditorjs.InlineMarkdownOptions{StripUnsupported: true}
+	ctx := goeditorjs.ContextWithInlineMarkdownOptions(context.Background(), options)
+	require.Equal(t, options, goeditorjs.InlineMarkdownOptionsFromContext(ctx))
+}
+
+func Test_MarkdownEngine_InlineMarkdownOptions(t *testing.T) {
+	editorJSData := `{"blocks": [
+		{"type": "header","data": {"text": "<i>Title</i>","level": 2}},
+		{"type": "paragraph","data": {"text": "<b>Bold</b> <mark>marked</mark> <u>under</u>","alignment": "left"}},
+		{"type": "list","data": {"style": "unordered","items": ["<a href=\"https://example.com\">Item</a>"]}}
+	]}`
+
+	eng := goeditorjs.NewMarkdownEngine()
+	eng.RegisterBlockHandlers(&goeditorjs.HeaderHandler{}, &goeditorjs.ParagraphHandler{}, &goeditorjs.ListHandler{})
+
+	result, err := eng.GenerateMarkdown(editorJSData)
+	require.NoError(t, err)
+	require.Equal(t, "## *Title*\n\n**Bold** ==marked== <u>under</u>\n\n- [Item](https://example.com)", result)
+
+	eng.InlineMarkdownOptions = &goeditorjs.InlineMarkdownOptions{StripUnsupported: true}
+	result, err = eng.GenerateMarkdown(editorJSData)
+	require.NoError(t, err)
+	require.Equal(t, "## *Title*\n\n**Bold** marked under\n\n- [Item](https://example.com)", result)
+}
+
+func Test_InlineMarkdownToHTML(t *testing.T) {
+	testData := []struct {
+		data           string
+		expectedResult string
+	}{
+		{data: `Plain text`, expectedResult: `Plain text`},
+		{data: `**Bold** and __strong__`, expectedResult: `<b>Bold</b> and <b>strong</b>`},
+		{data: `*Italic* and _emphasis_`, expectedResult: `<i>Italic</i> and <i>emphasis</i>`},
+		{data: `***Both***`, expectedResult: `<i><b>Both</b></i>`},
+		{data: `**Bold _and italic_**`, expectedResult: `<b>Bold <i>and italic</i></b>`},
+		{data: `**Bold *and italic***`, expectedResult: `<b>Bold <i>and italic</i></b>`},
+		{data: `*a **b** c* foo*bar*baz`, expectedResult: `<i>a <b>b</b> c</i> foo<i>bar</i>baz`},
+		{data: `*a **b*`, expectedResult: `*a *<i>b</i>`},
+		{data: `~~Struck~~ and ==marked==`, expectedResult: `<s>Struck</s> and <mark class="cdx-marker">marked</mark>`},
+		{data: `snake_case_name, 2 * 3 * 4, ** not bold **`, expectedResult: `snake_case_name, 2 * 3 * 4, ** not bold **`},
+		{data: "`a *b* <c>`", expectedResult: `<code class="inline-code">a *b* &lt;c&gt;</code>`},
+		{data: "`` `tick` ``", expectedResult: "<code class=\"inline-code\">`tick`</code>"},
+		{data: "`unclosed", expectedResult: "`unclosed"},
+		{data: `[Link](https://example.com "Title")`, expectedResult: `<a href="https://example.com">Link</a>`},
+		{data: `[**Bold** link](<https://example.com/a b>)`, expectedResult: `<a href="https://example.com/a b"><b>Bold</b> link</a>`},
+		{data: `[Not a link] (x)`, expectedResult: `[Not a link] (x)`},
+		{data: `[a [b](c) d](e) [f](g (h)) [i](j k)`, expectedResult: `[a <a href="c">b</a> d](e) <a href="g">f</a> [i](j k)`},
+		{data: `[*a](b) c*`, expectedResult: `<a href="b">*a</a> c*`},
+		{data: `![Image](https://example.com/a.png)`, expectedResult: `<a href="https://example.com/a.png">Image</a>`},
+		{data: `<https://example.com> <me@example.com>`, expectedResult: `<a href="https://example.com">https://example.com</a> <a href="mailto:me@example.com">me@example.com</a>`},
+		{data: `<u class="cdx-underline">Under</u><br>`, expectedResult: `<u class="cdx-underline">Under</u><br>`},
+		{data: `a < b & c > d &amp; &copy;`, expectedResult: `a &lt; b &amp; c &gt; d &amp; &copy;`},
+		{data: `\*not\* \_emphasis\_ \\ \<b\>`, expectedResult: `*not* _emphasis_ \ &lt;b&gt;`},
+		{data: "Soft\nbreak", expectedResult: `Soft break`},
+		{data: "Hard  \nbreak\\\nagain", expectedResult: `Hard<br>break<br>again`},
+	}
+
+	for _, td := range testData {
+		require.Equal(t, td.expectedResult, goeditorjs.InlineMarkdownToHTML(td.data), td.data)
+	}
+}
+
+func Test_InlineMarkdownToHTML_Is_Linear(t *testing.T) {
+	testData := []string{"*a ", "[a](", "[", "`a``", "<!--", "a*", "**a*"}
+
+	for _, td := range testData {
+		start := time.Now()
+		goeditorjs.InlineMarkdownToHTML(strings.Repeat(td, 50000))
+		require.Less(t, int64(time.Since(start)), int64(time.Second), td)
+	}
+}
+
+func Test_InlineMarkdownToHTML_Round_Trips(t *testing.T) {
+	testData := []string{
+		`**Bold** and *italic* with ~~struck~~ and ==marked== text`,
+		"`code` and [a link](https://example.com)",
+		`2 \* 3 = 6, snake\_case, \[x\], a\\b, \<tag>`,
+		`**Bold *and italic***`,
+		`foo*bar*baz`,
+	}
+
+	for _, td := range testData {
+		html := goeditorjs.InlineMarkdownToHTML(td)
+		require.Equal(t, td, goeditorjs.InlineHTMLToMarkdown(html, nil), html)
+	}
+}
@@ -1,13 +1,36 @@
 package goeditorjs
 
 import (
-	"fmt"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
 	"strings"
+	"sync"
 )
 
 // MarkdownEngine is the engine that creates the HTML from EditorJS blocks
+// Handlers can be registered while the engine is generating.
 type MarkdownEngine struct {
 	BlockHandlers map[string]MarkdownBlockHandler
+	TuneHandlers  map[string]MarkdownTuneHandler
+	// Registry, if set, provides the handlers for block types that aren't in BlockHandlers
+	Registry *Registry
+	// UnknownBlockPolicy decides what happens to blocks without a registered handler. Defaults to UnknownBlockFail.
+	UnknownBlockPolicy UnknownBlockPolicy
+	// FallbackHandler generates markdown for blocks without a registered handler when using UnknownBlockFallback
+	FallbackHandler MarkdownBlockHandler
+	// OnUnknownBlock, if set, is called with every block that doesn't have a registered handler
+	OnUnknownBlock func(editorJSBlock EditorJSBlock)
+	// ContinueOnError generates every block that can be generated and returns BlockErrors for the blocks that can't.
+	// Otherwise generation stops at the first block that fails with a *BlockError.
+	ContinueOnError bool
+	// InlineMarkdownOptions are used by the built-in handlers to convert the inline html of blocks to markdown.
+	// If nil, DefaultInlineMarkdownOptions are used. They're passed to handlers in the context.
+	InlineMarkdownOptions *InlineMarkdownOptions
+
+	mu sync.RWMutex
 }
 
 // MarkdownBlockHandler is an interface for a plugable EditorJS HTML generator
@@ -16,37 +39,287 @@ type MarkdownBlockHandler interface {
 	GenerateMarkdown(editorJSBlock EditorJSBlock) (string, error)
 }
 
+// ContextMarkdownBlockHandler is an interface for a plugable EditorJS markdown generator that is given the context of the generation,
+// e.g. to read request scoped values. The engine calls GenerateMarkdownContext instead of GenerateMarkdown for handlers implementing it.
+type ContextMarkdownBlockHandler interface {
+	MarkdownBlockHandler
+	GenerateMarkdownContext(ctx context.Context, editorJSBlock EditorJSBlock) (string, error)
+}
+
+// MarkdownBlockWriter is an interface for a plugable EditorJS markdown generator that writes the markdown to an io.Writer.
+// MarkdownBlockHandlers registered with the engine that also implement MarkdownBlockWriter are written using WriteMarkdown.
+type MarkdownBlockWriter interface {
+	Type() string // Type returns the type the block writer supports as a string
+	WriteMarkdown(w io.Writer, editorJSBlock EditorJSBlock) error
+}
+
+// MarkdownTuneHandler is an interface for a plugable EditorJS block tune markdown generator.
+// It's given the markdown generated for a block that has the tune and returns the tuned markdown, or ErrSkipBlock to suppress the block.
+type MarkdownTuneHandler interface {
+	TuneHandler
+	TuneMarkdown(tuneData json.RawMessage, editorJSBlock EditorJSBlock, markdown string) (string, error)
+}
+
 // NewMarkdownEngine creates a new MarkdownEngine
 func NewMarkdownEngine() *MarkdownEngine {
 	bhs := make(map[string]MarkdownBlockHandler)
-	return &MarkdownEngine{BlockHandlers: bhs}
+	ths := make(map[string]MarkdownTuneHandler)
+	return &MarkdownEngine{BlockHandlers: bhs, TuneHandlers: ths}
+}
+
+// NewMarkdownEngineFromRegistry creates a new MarkdownEngine that uses the handlers of the registry
+func NewMarkdownEngineFromRegistry(registry *Registry) *MarkdownEngine {
+	markdownEngine := NewMarkdownEngine()
+	markdownEngine.Registry = registry
+	return markdownEngine
+}
+
+// NewDefaultMarkdownEngine creates a new MarkdownEngine with all the built-in block handlers and tune handlers registered
+func NewDefaultMarkdownEngine() *MarkdownEngine {
+	markdownEngine := NewMarkdownEngineFromRegistry(NewDefaultRegistry())
+	markdownEngine.RegisterTuneHandlers(&AlignmentTuneHandler{}, &AnchorTuneHandler{}, &HiddenTuneHandler{})
+	return markdownEngine
+}
+
+// NewMarkdownBlockWriter adapts a MarkdownBlockHandler to a MarkdownBlockWriter
+func NewMarkdownBlockWriter(handler MarkdownBlockHandler) MarkdownBlockWriter {
+	return &markdownBlockHandlerWriter{MarkdownBlockHandler: handler}
+}
+
+// markdownBlockHandlerWriter adapts a MarkdownBlockHandler to a MarkdownBlockWriter
+type markdownBlockHandlerWriter struct {
+	MarkdownBlockHandler
+}
+
+// WriteMarkdown writes the markdown generated by the handler to w
+func (h *markdownBlockHandlerWriter) WriteMarkdown(w io.Writer, editorJSBlock EditorJSBlock) error {
+	md, err := h.GenerateMarkdown(editorJSBlock)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, md)
+	return err
+}
+
+// GenerateMarkdownContext passes the context on to the handler if it implements ContextMarkdownBlockHandler
+func (h *markdownBlockHandlerWriter) GenerateMarkdownContext(ctx context.Context, editorJSBlock EditorJSBlock) (string, error) {
+	if contextHandler, ok := h.MarkdownBlockHandler.(ContextMarkdownBlockHandler); ok {
+		return contextHandler.GenerateMarkdownContext(ctx, editorJSBlock)
+	}
+	return h.GenerateMarkdown(editorJSBlock)
+}
+
+// markdownBlockWriterHandler adapts a MarkdownBlockWriter to a MarkdownBlockHandler
+type markdownBlockWriterHandler struct {
+	MarkdownBlockWriter
+}
+
+// GenerateMarkdown returns the markdown written by the writer
+func (h *markdownBlockWriterHandler) GenerateMarkdown(editorJSBlock EditorJSBlock) (string, error) {
+	md := &strings.Builder{}
+	err := h.WriteMarkdown(md, editorJSBlock)
+	return md.String(), err
 }
 
 // RegisterBlockHandlers registers or overrides a block handlers for blockType given by MarkdownBlockHandler.Type()
 func (markdownEngine *MarkdownEngine) RegisterBlockHandlers(handlers ...MarkdownBlockHandler) {
+	markdownEngine.mu.Lock()
+	defer markdownEngine.mu.Unlock()
+	if markdownEngine.BlockHandlers == nil {
+		markdownEngine.BlockHandlers = make(map[string]MarkdownBlockHandler)
+	}
 	for _, bh := range handlers {
 		markdownEngine.BlockHandlers[bh.Type()] = bh
 	}
 }
 
+// RegisterBlockWriters registers or overrides a block writers for blockType given by MarkdownBlockWriter.Type()
+func (markdownEngine *MarkdownEngine) RegisterBlockWriters(writers ...MarkdownBlockWriter) {
+	markdownEngine.mu.Lock()
+	defer markdownEngine.mu.Unlock()
+	if markdownEngine.BlockHandlers == nil {
+		markdownEngine.BlockHandlers = make(map[string]MarkdownBlockHandler)
+	}
+	for _, bw := range writers {
+		if bh, ok := bw.(MarkdownBlockHandler); ok {
+			markdownEngine.BlockHandlers[bw.Type()] = bh
+		} else {
+			markdownEngine.BlockHandlers[bw.Type()] = &markdownBlockWriterHandler{MarkdownBlockWriter: bw}
+		}
+	}
+}
+
+// RegisterTuneHandlers registers or overrides tune handlers for the tune given by MarkdownTuneHandler.Type()
+func (markdownEngine *MarkdownEngine) RegisterTuneHandlers(handlers ...MarkdownTuneHandler) {
+	markdownEngine.mu.Lock()
+	defer markdownEngine.mu.Unlock()
+	if markdownEngine.TuneHandlers == nil {
+		markdownEngine.TuneHandlers = make(map[string]MarkdownTuneHandler)
+	}
+	for _, th := range handlers {
+		markdownEngine.TuneHandlers[th.Type()] = th
+	}
+}
+
 // GenerateMarkdown generates markdown from the editorJS using configured set of markdown handlers
 func (markdownEngine *MarkdownEngine) GenerateMarkdown(editorJSData string) (string, error) {
-	results := []string{}
-	ejs, err := parseEditorJSON(editorJSData)
+	document, err := ParseDocument(editorJSData)
 	if err != nil {
 		return "", err
 	}
-	for _, block := range ejs.Blocks {
-		if generator, ok := markdownEngine.BlockHandlers[block.Type]; ok {
-			md, err := generator.GenerateMarkdown(block)
+	return markdownEngine.GenerateMarkdownFromDocument(document)
+}
+
+// GenerateMarkdownContext generates markdown from the editorJS using configured set of markdown handlers.
+// The context is passed to handlers implementing ContextMarkdownBlockHandler, and generation stops when the context is done.
+func (markdownEngine *MarkdownEngine) GenerateMarkdownContext(ctx context.Context, editorJSData string) (string, error) {
+	document, err := ParseDocument(editorJSData)
+	if err != nil {
+		return "", err
+	}
+	return markdownEngine.GenerateMarkdownFromDocumentContext(ctx, document)
+}
+
+// GenerateMarkdownFromDocument generates markdown from a parsed Document using configured set of markdown handlers
+func (markdownEngine *MarkdownEngine) GenerateMarkdownFromDocument(document *Document) (string, error) {
+	result := &strings.Builder{}
+	err := markdownEngine.RenderMarkdownFromDocument(result, document)
+	return result.String(), err
+}
+
+// GenerateMarkdownFromDocumentContext generates markdown from a parsed Document using configured set of markdown handlers.
+// The context is passed to handlers implementing ContextMarkdownBlockHandler, and generation stops when the context is done.
+func (markdownEngine *MarkdownEngine) GenerateMarkdownFromDocumentContext(ctx context.Context, document *Document) (string, error) {
+	result := &strings.Builder{}
+	err := markdownEngine.renderDocument(ctx, result, document)
+	return result.String(), err
+}
+
+// RenderMarkdown reads editorJS data from r and writes the markdown to w using configured set of markdown handlers
+func (markdownEngine *MarkdownEngine) RenderMarkdown(w io.Writer, r io.Reader) error {
+	document, err := decodeDocument(r)
+	if err != nil {
+		return err
+	}
+	return markdownEngine.RenderMarkdownFromDocument(w, document)
+}
+
+// RenderMarkdownContext reads editorJS data from r and writes the markdown to w using configured set of markdown handlers.
+// The context is passed to handlers implementing ContextMarkdownBlockHandler, and rendering stops when the context is done.
+func (markdownEngine *MarkdownEngine) RenderMarkdownContext(ctx context.Context, w io.Writer, r io.Reader) error {
+	document, err := decodeDocument(r)
+	if err != nil {
+		return err
+	}
+	return markdownEngine.RenderMarkdownFromDocumentContext(ctx, w, document)
+}
+
+// RenderMarkdownFromDocument writes the markdown for a parsed Document to w using configured set of markdown handlers
+func (markdownEngine *MarkdownEngine) RenderMarkdownFromDocument(w io.Writer, document *Document) error {
+	return markdownEngine.renderDocument(context.Background(), w, document)
+}
+
+// RenderMarkdownFromDocumentContext writes the markdown for a parsed Document to w using configured set of markdown handlers.
+// The context is passed to handlers implementing ContextMarkdownBlockHandler, and rendering stops when the context is done.
+func (markdownEngine *MarkdownEngine) RenderMarkdownFromDocumentContext(ctx context.Context, w io.Writer, document *Document) error {
+	return markdownEngine.renderDocument(ctx, w, document)
+}
+
+// renderDocument writes the markdown for a parsed Document to w
+func (markdownEngine *MarkdownEngine) renderDocument(ctx context.Context, w io.Writer, document *Document) error {
+	return writeBlocks(ctx, w, document.Blocks, "\n\n", markdownEngine.ContinueOnError, markdownEngine.writeBlock)
+}
+
+// writeBlock writes the markdown for a single block to buf
+func (markdownEngine *MarkdownEngine) writeBlock(ctx context.Context, buf *bytes.Buffer, block EditorJSBlock) (bool, error) {
+	generator, ok := markdownEngine.blockHandler(block.Type)
+	if !ok {
+		switch unknownBlock(markdownEngine.UnknownBlockPolicy, markdownEngine.FallbackHandler != nil, markdownEngine.OnUnknownBlock, block) {
+		case UnknownBlockSkip:
+			return false, nil
+		case UnknownBlockPlaceholder:
+			buf.WriteString(unknownBlockPlaceholder(block))
+			return true, nil
+		case UnknownBlockFallback:
+			generator = markdownEngine.FallbackHandler
+		default:
+			return false, errBlockHandlerNotFound(block)
+		}
+	}
+
+	if markdownEngine.InlineMarkdownOptions != nil {
+		ctx = ContextWithInlineMarkdownOptions(ctx, markdownEngine.InlineMarkdownOptions)
+	}
+
+	if contextGenerator, ok := generator.(ContextMarkdownBlockHandler); ok {
+		output, err := contextGenerator.GenerateMarkdownContext(ctx, block)
+		if err != nil {
+			return false, err
+		}
+		buf.WriteString(output)
+	} else if writer, ok := generator.(MarkdownBlockWriter); ok {
+		if err := writer.WriteMarkdown(buf, block); err != nil {
+			return false, err
+		}
+	} else {
+		md, err := generator.GenerateMarkdown(block)
+		if err != nil {
+			return false, err
+		}
+		buf.WriteString(md)
+	}
+
+	if len(block.Tunes) == 0 {
+		return true, nil
+	}
+
+	md, err := markdownEngine.applyTunes(block, buf.String())
+	buf.Reset()
+	if errors.Is(err, ErrSkipBlock) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	buf.WriteString(md)
+	return true, nil
+}
+
+// blockHandler returns the handler for the block type from BlockHandlers, or from the Registry if it has one the engine can use
+func (markdownEngine *MarkdownEngine) blockHandler(blockType string) (MarkdownBlockHandler, bool) {
+	markdownEngine.mu.RLock()
+	generator, ok := markdownEngine.BlockHandlers[blockType]
+	registry := markdownEngine.Registry
+	markdownEngine.mu.RUnlock()
+	if ok || registry == nil {
+		return generator, ok
+	}
+
+	if h, ok := registry.Lookup(blockType); ok {
+		generator, ok = h.(MarkdownBlockHandler)
+		return generator, ok
+	}
+	return nil, false
+}
+
+// tuneHandler returns the registered tune handler for the tune
+func (markdownEngine *MarkdownEngine) tuneHandler(name string) (MarkdownTuneHandler, bool) {
+	markdownEngine.mu.RLock()
+	defer markdownEngine.mu.RUnlock()
+	tuneHandler, ok := markdownEngine.TuneHandlers[name]
+	return tuneHandler, ok
+}
+
+// applyTunes applies the registered tune handlers for the tunes of the block, in order of tune name
+func (markdownEngine *MarkdownEngine) applyTunes(block EditorJSBlock, md string) (string, error) {
+	for _, name := range tuneNames(block) {
+		if tuneHandler, ok := markdownEngine.tuneHandler(name); ok {
+			var err error
+			md, err = tuneHandler.TuneMarkdown(block.Tunes[name], block, md)
 			if err != nil {
 				return "", err
 			}
-			results = append(results, md)
-		} else {
-			return "", fmt.Errorf("%w, Block Type: %s", ErrBlockHandlerNotFound, block.Type)
 		}
 	}
-
-	return strings.Join(results, "\n\n"), nil
+	return md, nil
 }
@@ -1,7 +1,12 @@
 package goeditorjs_test
 
 import (
+	"bytes"
+	"context"
 	"errors"
+	"fmt"
+	"io"
+	"strings"
 	"testing"
 
 	"github.com/davidscottmills/goeditorjs"
@@ -61,7 +66,11 @@ func Test_GenerateMarkdown_Returns_Err_From_Handler(t *testing.T) {
 	eng.BlockHandlers["header"] = bh
 	_, err := eng.GenerateMarkdown(editorJSData)
 	require.Error(t, err)
-	require.Equal(t, mockErr, err)
+	require.True(t, errors.Is(err, mockErr))
+	blockErr := &goeditorjs.BlockError{}
+	require.True(t, errors.As(err, &blockErr))
+	require.Equal(t, 0, blockErr.Index)
+	require.Equal(t, "header", blockErr.Type)
 	bh.AssertCalled(t, "GenerateMarkdown", mock.Anything)
 }
 
@@ -76,3 +85,229 @@ func Test_GenerateMarkdown_Result_Includes_Handler_Result(t *testing.T) {
 	require.Contains(t, result, handlerResult)
 	bh.AssertCalled(t, "GenerateMarkdown", mock.Anything)
 }
+
+func Test_GenerateMarkdownFromDocument(t *testing.T) {
+	document, err := goeditorjs.ParseDocument(`{"time": 1607709186831,"blocks": [{"id": "a","type": "header","data": {"text": "Heading 1","level": 1}},{"id": "b","type": "paragraph","data": {"text": "Text","alignment": "left"}}],"version": "2.22.2"}`)
+	require.NoError(t, err)
+	// Drop the header before rendering
+	document.Blocks = document.Blocks[1:]
+
+	eng := goeditorjs.NewMarkdownEngine()
+	eng.RegisterBlockHandlers(&goeditorjs.HeaderHandler{}, &goeditorjs.ParagraphHandler{})
+	result, err := eng.GenerateMarkdownFromDocument(document)
+	require.NoError(t, err)
+	require.Equal(t, "Text", result)
+}
+
+func Test_GenerateMarkdown_UnknownBlockPolicy(t *testing.T) {
+	editorJSData := `{"blocks": [{"type": "header","data": {"text": "Heading 1","level": 1}},{"type": "unknown","data": {}},{"type": "paragraph","data": {"text": "Text","alignment": "left"}}]}`
+	fallback := &mockMarkdownBlockHandler{typeName: "fallback"}
+	fallback.On("GenerateMarkdown", mock.Anything).Return("fallback", nil)
+
+	testData := []struct {
+		policy         goeditorjs.UnknownBlockPolicy
+		fallback       goeditorjs.MarkdownBlockHandler
+		expectedResult string
+		expectErr      bool
+	}{
+		{policy: goeditorjs.UnknownBlockFail, expectErr: true},
+		{policy: goeditorjs.UnknownBlockSkip, expectedResult: "# Heading 1\n\nText"},
+		{policy: goeditorjs.UnknownBlockPlaceholder, expectedResult: "# Heading 1\n\n<!-- goeditorjs: no handler for block type \"unknown\" -->\n\nText"},
+		{policy: goeditorjs.UnknownBlockFallback, fallback: fallback, expectedResult: "# Heading 1\n\nfallback\n\nText"},
+		{policy: goeditorjs.UnknownBlockFallback, expectErr: true},
+	}
+
+	for _, td := range testData {
+		unknownTypes := []string{}
+		eng := goeditorjs.NewMarkdownEngine()
+		eng.RegisterBlockHandlers(&goeditorjs.HeaderHandler{}, &goeditorjs.ParagraphHandler{})
+		eng.UnknownBlockPolicy = td.policy
+		eng.FallbackHandler = td.fallback
+		eng.OnUnknownBlock = func(editorJSBlock goeditorjs.EditorJSBlock) {
+			unknownTypes = append(unknownTypes, editorJSBlock.Type)
+		}
+
+		result, err := eng.GenerateMarkdown(editorJSData)
+		if td.expectErr {
+			require.True(t, errors.Is(err, goeditorjs.ErrBlockHandlerNotFound))
+		} else {
+			require.NoError(t, err)
+			require.Equal(t, td.expectedResult, result)
+		}
+		require.Equal(t, []string{"unknown"}, unknownTypes)
+	}
+}
+
+func Test_GenerateMarkdown_UnknownBlockPlaceholder_Escapes_Type(t *testing.T) {
+	editorJSData := `{"blocks": [{"type": "---><img src=x onerror=alert(1)>","data": {}},{"type": "paragraph","data": {"text": "Text","alignment": "left"}}]}`
+	eng := goeditorjs.NewMarkdownEngine()
+	eng.RegisterBlockHandlers(&goeditorjs.ParagraphHandler{})
+	eng.UnknownBlockPolicy = goeditorjs.UnknownBlockPlaceholder
+
+	result, err := eng.GenerateMarkdown(editorJSData)
+	require.NoError(t, err)
+	require.Equal(t, "<!-- goeditorjs: no handler for block type \"-__img_src_x_onerror_alert_1__\" -->\n\nText", result)
+}
+
+func Test_GenerateMarkdown_Returns_Partial_Result_On_Err(t *testing.T) {
+	editorJSData := `{"blocks": [{"id": "a","type": "header","data": {"text": "Heading 1","level": 1}},{"id": "b","type": "paragraph","data": []},{"id": "c","type": "unknown","data": {}},{"id": "d","type": "paragraph","data": {"text": "Text","alignment": "left"}}]}`
+	eng := goeditorjs.NewMarkdownEngine()
+	eng.RegisterBlockHandlers(&goeditorjs.HeaderHandler{}, &goeditorjs.ParagraphHandler{})
+
+	result, err := eng.GenerateMarkdown(editorJSData)
+	require.Equal(t, "# Heading 1", result)
+	blockErr := &goeditorjs.BlockError{}
+	require.True(t, errors.As(err, &blockErr))
+	require.Equal(t, 1, blockErr.Index)
+	require.Equal(t, "b", blockErr.ID)
+	require.Equal(t, "paragraph", blockErr.Type)
+}
+
+func Test_GenerateMarkdown_ContinueOnError(t *testing.T) {
+	editorJSData := `{"blocks": [{"id": "a","type": "header","data": {"text": "Heading 1","level": 1}},{"id": "b","type": "paragraph","data": []},{"id": "c","type": "unknown","data": {}},{"id": "d","type": "paragraph","data": {"text": "Text","alignment": "left"}}]}`
+	eng := goeditorjs.NewMarkdownEngine()
+	eng.RegisterBlockHandlers(&goeditorjs.HeaderHandler{}, &goeditorjs.ParagraphHandler{})
+	eng.ContinueOnError = true
+
+	result, err := eng.GenerateMarkdown(editorJSData)
+	require.Equal(t, "# Heading 1\n\nText", result)
+	blockErrs := goeditorjs.BlockErrors{}
+	require.True(t, errors.As(err, &blockErrs))
+	require.Len(t, blockErrs, 2)
+	require.Equal(t, "c", blockErrs[1].ID)
+	require.True(t, errors.Is(err, goeditorjs.ErrBlockHandlerNotFound))
+}
+
+type testMarkdownBlockWriter struct{}
+
+func (*testMarkdownBlockWriter) Type() string {
+	return "header"
+}
+
+func (*testMarkdownBlockWriter) WriteMarkdown(w io.Writer, editorJSBlock goeditorjs.EditorJSBlock) error {
+	_, err := fmt.Fprintf(w, "# %s", editorJSBlock.ID)
+	return err
+}
+
+func Test_RenderMarkdown(t *testing.T) {
+	editorJSData := `{"blocks": [{"id": "a","type": "header","data": {}},{"id": "b","type": "paragraph","data": {"text": "Text","alignment": "left"}}]}`
+	eng := goeditorjs.NewMarkdownEngine()
+	eng.RegisterBlockWriters(&testMarkdownBlockWriter{}, goeditorjs.NewMarkdownBlockWriter(&goeditorjs.ParagraphHandler{}))
+
+	w := &bytes.Buffer{}
+	err := eng.RenderMarkdown(w, strings.NewReader(editorJSData))
+	require.NoError(t, err)
+	require.Equal(t, "# a\n\nText", w.String())
+
+	result, err := eng.GenerateMarkdown(editorJSData)
+	require.NoError(t, err)
+	require.Equal(t, "# a\n\nText", result)
+}
+
+func Test_RenderMarkdown_Returns_Parse_Err(t *testing.T) {
+	eng := goeditorjs.NewMarkdownEngine()
+	err := eng.RenderMarkdown(&bytes.Buffer{}, strings.NewReader(``))
+	require.Error(t, err)
+}
+
+func Test_RenderMarkdown_Returns_Write_Err(t *testing.T) {
+	editorJSData := `{"blocks": [{"type": "paragraph","data": {"text": "Text","alignment": "left"}}]}`
+	eng := goeditorjs.NewMarkdownEngine()
+	eng.RegisterBlockHandlers(&goeditorjs.ParagraphHandler{})
+	err := eng.RenderMarkdown(&failingWriter{}, strings.NewReader(editorJSData))
+	require.EqualError(t, err, "write failed")
+}
+
+func Test_NewMarkdownBlockWriter(t *testing.T) {
+	w := goeditorjs.NewMarkdownBlockWriter(&goeditorjs.HeaderHandler{})
+	require.Equal(t, "header", w.Type())
+
+	buf := &bytes.Buffer{}
+	err := w.WriteMarkdown(buf, goeditorjs.EditorJSBlock{Type: "header", Data: []byte(`{"text": "Heading","level": 2}`)})
+	require.NoError(t, err)
+	require.Equal(t, "## Heading", buf.String())
+
+	err = w.WriteMarkdown(buf, goeditorjs.EditorJSBlock{Type: "header", Data: []byte{}})
+	require.Error(t, err)
+}
+
+type testContextMarkdownBlockHandler struct{}
+
+func (*testContextMarkdownBlockHandler) Type() string {
+	return "paragraph"
+}
+
+func (*testContextMarkdownBlockHandler) GenerateMarkdown(editorJSBlock goeditorjs.EditorJSBlock) (string, error) {
+	return "", errors.New("GenerateMarkdown should not be called")
+}
+
+func (*testContextMarkdownBlockHandler) GenerateMarkdownContext(ctx context.Context, editorJSBlock goeditorjs.EditorJSBlock) (string, error) {
+	user, _ := ctx.Value(testContextKey{}).(string)
+	return user, nil
+}
+
+func Test_GenerateMarkdownContext_Passes_Context_To_Handler(t *testing.T) {
+	editorJSData := `{"blocks": [{"type": "paragraph","data": {"text": "Text"}}]}`
+	eng := goeditorjs.NewMarkdownEngine()
+	eng.RegisterBlockHandlers(&testContextMarkdownBlockHandler{})
+
+	ctx := context.WithValue(context.Background(), testContextKey{}, "gopher")
+	result, err := eng.GenerateMarkdownContext(ctx, editorJSData)
+	require.NoError(t, err)
+	require.Equal(t, "gopher", result)
+
+	w := &bytes.Buffer{}
+	err = eng.RenderMarkdownContext(ctx, w, strings.NewReader(editorJSData))
+	require.NoError(t, err)
+	require.Equal(t, "gopher", w.String())
+
+	document, err := goeditorjs.ParseDocument(editorJSData)
+	require.NoError(t, err)
+	result, err = eng.GenerateMarkdownFromDocumentContext(ctx, document)
+	require.NoError(t, err)
+	require.Equal(t, "gopher", result)
+
+	w.Reset()
+	err = eng.RenderMarkdownFromDocumentContext(ctx, w, document)
+	require.NoError(t, err)
+	require.Equal(t, "gopher", w.String())
+}
+
+func Test_GenerateMarkdownFromDocumentContext_Stops_When_Cancelled(t *testing.T) {
+	document, err := goeditorjs.ParseDocument(`{"blocks": [{"type": "paragraph","data": {"text": "Text"}}]}`)
+	require.NoError(t, err)
+	eng := goeditorjs.NewMarkdownEngine()
+	eng.RegisterBlockHandlers(&goeditorjs.ParagraphHandler{})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	result, err := eng.GenerateMarkdownFromDocumentContext(ctx, document)
+	require.Equal(t, context.Canceled, err)
+	require.Equal(t, "", result)
+
+	w := &bytes.Buffer{}
+	err = eng.RenderMarkdownFromDocumentContext(ctx, w, document)
+	require.Equal(t, context.Canceled, err)
+	require.Equal(t, "", w.String())
+}
+
+func Test_GenerateMarkdownContext_Stops_When_Cancelled(t *testing.T) {
+	editorJSData := `{"blocks": [{"type": "paragraph","data": {"text": "One"}},{"type": "paragraph","data": {"text": "Two"}}]}`
+	ctx, cancel := context.WithCancel(context.Background())
+	handler := &mockMarkdownBlockHandler{typeName: "paragraph"}
+	handler.On("GenerateMarkdown", mock.Anything).Run(func(mock.Arguments) { cancel() }).Return("One", nil).Once()
+	eng := goeditorjs.NewMarkdownEngine()
+	eng.ContinueOnError = true
+	eng.RegisterBlockHandlers(handler)
+
+	result, err := eng.GenerateMarkdownContext(ctx, editorJSData)
+	require.Equal(t, context.Canceled, err)
+	require.Equal(t, "One", result)
+	handler.AssertExpectations(t)
+}
+
+func Test_GenerateMarkdownContext_Returns_Parse_Err(t *testing.T) {
+	eng := goeditorjs.NewMarkdownEngine()
+	_, err := eng.GenerateMarkdownContext(context.Background(), ``)
+	require.Error(t, err)
+}
@@ -0,0 +1,647 @@
+package goeditorjs
+
+import (
+	"encoding/json"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// MarkdownBlockParser parses custom markdown constructs into editor.js blocks
+type MarkdownBlockParser interface {
+	// ParseMarkdownBlock is called with the remaining lines of the markdown at the start of every top level block.
+	// It returns the blocks of the construct and the number of lines it consumed, or 0 lines if the construct doesn't start at the first line.
+	ParseMarkdownBlock(lines []string) ([]EditorJSBlock, int, error)
+}
+
+// MarkdownBlockParserFunc is a function that implements MarkdownBlockParser
+type MarkdownBlockParserFunc func(lines []string) ([]EditorJSBlock, int, error)
+
+// ParseMarkdownBlock calls f(lines)
+func (f MarkdownBlockParserFunc) ParseMarkdownBlock(lines []string) ([]EditorJSBlock, int, error) {
+	return f(lines)
+}
+
+// MarkdownImporter converts CommonMark and GitHub flavored markdown into editor.js documents.
+// The blocks use the data formats of the built-in handlers: headers, paragraphs, lists, checklists, code, images,
+// tables, quotes, warnings (GitHub alerts), delimiters and raw html.
+type MarkdownImporter struct {
+	// BlockParsers are tried in order at the start of every top level block, before the built-in constructs
+	BlockParsers []MarkdownBlockParser
+	// CodeBlockType is the block type of code blocks, "code" or "codeBox". Defaults to "code".
+	CodeBlockType string
+	// HTMLImporter, if set, converts block level html into blocks, e.g. the html the markdown handlers use for alignment.
+	// Otherwise html is kept as raw blocks.
+	HTMLImporter *HTMLImporter
+}
+
+// NewMarkdownImporter creates a new MarkdownImporter
+func NewMarkdownImporter() *MarkdownImporter {
+	return &MarkdownImporter{CodeBlockType: "code"}
+}
+
+// RegisterBlockParsers adds block parsers for custom markdown constructs
+func (importer *MarkdownImporter) RegisterBlockParsers(parsers ...MarkdownBlockParser) {
+	importer.BlockParsers = append(importer.BlockParsers, parsers...)
+}
+
+// ImportMarkdown converts markdown into an editor.js document
+func (importer *MarkdownImporter) ImportMarkdown(markdown string) (*Document, error) {
+	markdown = strings.NewReplacer("\r\n", "\n", "\r", "\n").Replace(markdown)
+	lines := strings.Split(markdown, "\n")
+	for i, line := range lines {
+		lines[i] = expandLeadingTabs(line)
+	}
+
+	blocks := []EditorJSBlock{}
+	for i := 0; i < len(lines); {
+		if isBlankLine(lines[i]) {
+			i++
+			continue
+		}
+
+		parsed, consumed, err := importer.parseCustomBlock(lines[i:])
+		if err != nil {
+			return nil, err
+		}
+		if consumed == 0 {
+			parsed, consumed = importer.parseBlock(lines[i:])
+		}
+		blocks = append(blocks, parsed...)
+		i += consumed
+	}
+
+	return &Document{Blocks: blocks}, nil
+}
+
+// parseCustomBlock tries the BlockParsers at the start of lines
+func (importer *MarkdownImporter) parseCustomBlock(lines []string) ([]EditorJSBlock, int, error) {
+	for _, parser := range importer.BlockParsers {
+		blocks, consumed, err := parser.ParseMarkdownBlock(lines)
+		if err != nil {
+			return nil, 0, err
+		}
+		if consumed > 0 {
+			if consumed > len(lines) {
+				consumed = len(lines)
+			}
+			return blocks, consumed, nil
+		}
+	}
+	return nil, 0, nil
+}
+
+// parseBlock parses the built-in construct at the start of lines, which doesn't start with a blank line.
+// It returns the blocks of the construct and the number of lines it consumed, which is at least one.
+func (importer *MarkdownImporter) parseBlock(lines []string) ([]EditorJSBlock, int) {
+	line := lines[0]
+	if leadingSpaces(line) >= 4 {
+		return importer.parseIndentedCode(lines)
+	}
+
+	trimmed := strings.TrimLeft(line, " ")
+	switch {
+	case markdownFence.MatchString(line):
+		return importer.parseFencedCode(lines)
+	case markdownATXHeading.MatchString(line):
+		return []EditorJSBlock{parseATXHeading(line)}, 1
+	case markdownThematicBreak.MatchString(line):
+		return []EditorJSBlock{{Type: "delimiter", Data: json.RawMessage(`{}`)}}, 1
+	case strings.HasPrefix(trimmed, ">"):
+		return parseBlockquote(lines)
+	case len(lines) > 1 && isTableStart(lines[0], lines[1]):
+		return parseTable(lines)
+	case markdownHTMLBlock.MatchString(line) || markdownHTMLTagLine.MatchString(line):
+		return importer.parseHTMLBlock(lines)
+	}
+
+	if marker, ok := parseListMarker(line); ok {
+		return parseList(lines, marker)
+	}
+
+	return parseParagraph(lines)
+}
+
+// parseIndentedCode parses a code block indented by four spaces
+func (importer *MarkdownImporter) parseIndentedCode(lines []string) ([]EditorJSBlock, int) {
+	end := 0
+	code := []string{}
+	for i, line := range lines {
+		if !isBlankLine(line) && leadingSpaces(line) < 4 {
+			break
+		}
+		code = append(code, removeIndent(line, 4))
+		if !isBlankLine(line) {
+			end = i + 1
+		}
+	}
+
+	return []EditorJSBlock{newCodeBlock(importer.CodeBlockType, strings.Join(code[:end], "\n"), "")}, end
+}
+
+// parseFencedCode parses a code block fenced by backticks or tildes
+func (importer *MarkdownImporter) parseFencedCode(lines []string) ([]EditorJSBlock, int) {
+	match := markdownFence.FindStringSubmatch(lines[0])
+	indent, fence := len(match[1]), match[2]
+	language := ""
+	if fields := strings.Fields(match[3]); len(fields) > 0 {
+		language = unescapeMarkdown(fields[0])
+	}
+
+	code := []string{}
+	i := 1
+	for ; i < len(lines); i++ {
+		closing := strings.TrimSpace(lines[i])
+		if leadingSpaces(lines[i]) < 4 && len(closing) >= len(fence) && strings.Trim(closing, fence[:1]) == "" {
+			i++
+			break
+		}
+		code = append(code, removeIndent(lines[i], indent))
+	}
+
+	return []EditorJSBlock{newCodeBlock(importer.CodeBlockType, strings.Join(code, "\n"), language)}, i
+}
+
+// newCodeBlock creates a "code" block, or a "codeBox" block if that's the blockType
+func newCodeBlock(blockType, code, language string) EditorJSBlock {
+	if blockType == "codeBox" {
+		// Code boxes hold html
+		return newImportBlock("codeBox", &codeBox{Code: html.EscapeString(code), Language: language})
+	}
+	return newImportBlock("code", &codeBox{Code: code, Language: language})
+}
+
+// parseATXHeading parses a "#" heading
+func parseATXHeading(line string) EditorJSBlock {
+	match := markdownATXHeading.FindStringSubmatch(line)
+	text := markdownClosingHashes.ReplaceAllString(match[2], "")
+	if strings.Trim(text, "#") == "" {
+		text = ""
+	}
+
+	return newImportBlock("header", &header{Text: markdownInlineToHTML(strings.TrimSpace(text), " "), Level: len(match[1])})
+}
+
+// parseBlockquote parses a block quote, which is a warning if it's a GitHub alert
+func parseBlockquote(lines []string) ([]EditorJSBlock, int) {
+	content := []string{}
+	i := 0
+	for ; i < len(lines); i++ {
+		trimmed := strings.TrimLeft(lines[i], " ")
+		if leadingSpaces(lines[i]) >= 4 || !strings.HasPrefix(trimmed, ">") {
+			break
+		}
+		trimmed = trimmed[1:]
+		if strings.HasPrefix(trimmed, " ") {
+			trimmed = trimmed[1:]
+		}
+		content = append(content, trimmed)
+	}
+
+	content = trimBlankLines(content)
+	if len(content) > 0 && markdownAlert.MatchString(content[0]) {
+		return []EditorJSBlock{parseAlert(content[1:])}, i
+	}
+
+	caption := ""
+	if n := len(content); n > 2 && isBlankLine(content[n-2]) && strings.HasPrefix(content[n-1], "— ") {
+		caption = markdownInlineToHTML(strings.TrimPrefix(content[n-1], "— "), " ")
+		content = trimBlankLines(content[:n-2])
+	}
+
+	text := markdownInlineToHTML(strings.Join(content, "\n"), "<br>")
+	return []EditorJSBlock{newImportBlock("quote", &quote{Text: text, Caption: caption, Alignment: "left"})}, i
+}
+
+// parseAlert parses the content of a GitHub alert following the "[!KIND]" line.
+// A bold line separated from the message by a blank line is the title of the warning.
+func parseAlert(content []string) EditorJSBlock {
+	content = trimBlankLines(content)
+	title := ""
+	if len(content) > 0 && (len(content) == 1 || isBlankLine(content[1])) {
+		if match := markdownAlertTitle.FindStringSubmatch(content[0]); match != nil {
+			title = markdownInlineToHTML(match[1], " ")
+			content = trimBlankLines(content[1:])
+		}
+	}
+
+	message := markdownInlineToHTML(strings.Join(content, "\n"), "<br>")
+	return newImportBlock("warning", &warning{Title: title, Message: message})
+}
+
+// isTableStart reports whether line is the header row of a GFM table with delimiter as its delimiter row
+func isTableStart(line, delimiter string) bool {
+	if !strings.Contains(line, "|") || !markdownTableDelimiter.MatchString(delimiter) {
+		return false
+	}
+	return len(splitTableRow(line)) == len(splitTableRow(delimiter))
+}
+
+// parseTable parses a GFM table. A header row without any text is dropped, the table then has no headings.
+func parseTable(lines []string) ([]EditorJSBlock, int) {
+	heading := splitTableRow(lines[0])
+	columns := len(heading)
+
+	rows := [][]string{heading}
+	i := 2
+	for ; i < len(lines); i++ {
+		if isBlankLine(lines[i]) || interruptsParagraph(lines[i]) {
+			break
+		}
+		row := splitTableRow(lines[i])
+		for len(row) < columns {
+			row = append(row, "")
+		}
+		rows = append(rows, row[:columns])
+	}
+
+	withHeadings := false
+	for _, cell := range heading {
+		withHeadings = withHeadings || cell != ""
+	}
+	if !withHeadings {
+		rows = rows[1:]
+	}
+
+	for _, row := range rows {
+		for j, cell := range row {
+			row[j] = markdownInlineToHTML(cell, " ")
+		}
+	}
+
+	return []EditorJSBlock{newImportBlock("table", &table{WithHeadings: withHeadings, Content: rows})}, i
+}
+
+// splitTableRow splits a table row into its trimmed cells, unescaping escaped pipes
+func splitTableRow(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, `\|`) {
+		line = line[:len(line)-1]
+	}
+
+	cells := []string{}
+	cell := &strings.Builder{}
+	for i := 0; i < len(line); i++ {
+		switch {
+		case line[i] == '\\' && i+1 < len(line) && line[i+1] == '|':
+			cell.WriteByte('|')
+			i++
+		case line[i] == '|':
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+		default:
+			cell.WriteByte(line[i])
+		}
+	}
+	return append(cells, strings.TrimSpace(cell.String()))
+}
+
+// parseHTMLBlock parses html up to the next blank line, converting it with the HTMLImporter.
+// It's kept as a raw block if there's no HTMLImporter or the html can't be converted into any blocks.
+func (importer *MarkdownImporter) parseHTMLBlock(lines []string) ([]EditorJSBlock, int) {
+	i := 0
+	for i < len(lines) && !isBlankLine(lines[i]) {
+		i++
+	}
+
+	htmlData := strings.Join(lines[:i], "\n")
+	if importer.HTMLImporter != nil {
+		document, err := importer.HTMLImporter.ImportHTML(htmlData)
+		if err == nil && len(document.Blocks) > 0 {
+			return document.Blocks, i
+		}
+	}
+	return []EditorJSBlock{newImportBlock("raw", &raw{HTML: htmlData})}, i
+}
+
+// parseParagraph parses a paragraph, a setext heading or an image on a line of its own
+func parseParagraph(lines []string) ([]EditorJSBlock, int) {
+	i := 1
+	level := 0
+	for ; i < len(lines); i++ {
+		if markdownSetextUnderline.MatchString(lines[i]) {
+			level = 1
+			if strings.Contains(lines[i], "-") {
+				level = 2
+			}
+			break
+		}
+		if isBlankLine(lines[i]) || interruptsParagraph(lines[i]) {
+			break
+		}
+	}
+
+	content := make([]string, i)
+	for j, line := range lines[:i] {
+		content[j] = strings.TrimLeft(line, " ")
+	}
+	text := strings.TrimRight(strings.Join(content, "\n"), " ")
+
+	if level > 0 {
+		return []EditorJSBlock{newImportBlock("header", &header{Text: markdownInlineToHTML(text, " "), Level: level})}, i + 1
+	}
+
+	if match := markdownImage.FindStringSubmatch(text); match != nil {
+		caption := match[3]
+		if caption == "" {
+			caption = match[1]
+		}
+		image := &image{File: file{URL: unescapeMarkdown(match[2])}, Caption: markdownInlineToHTML(caption, " ")}
+		return []EditorJSBlock{newImportBlock("image", image)}, i
+	}
+
+	return []EditorJSBlock{newImportBlock("paragraph", &paragraph{Text: markdownInlineToHTML(text, " "), Alignment: "left"})}, i
+}
+
+// interruptsParagraph reports whether line starts a block that ends a paragraph without a blank line in between
+func interruptsParagraph(line string) bool {
+	if leadingSpaces(line) >= 4 {
+		return false
+	}
+	if markdownFence.MatchString(line) || markdownATXHeading.MatchString(line) || markdownThematicBreak.MatchString(line) ||
+		markdownHTMLBlock.MatchString(line) || strings.HasPrefix(strings.TrimLeft(line, " "), ">") {
+		return true
+	}
+
+	marker, ok := parseListMarker(line)
+	return ok && marker.width < len(line) && (!marker.ordered || marker.start == 1)
+}
+
+// markdownListMarker is the marker of a list item
+type markdownListMarker struct {
+	indent  int // indent is the number of spaces before the marker
+	width   int // width is the offset of the content of the item
+	ordered bool
+	start   int
+}
+
+// parseListMarker parses the list marker at the start of line
+func parseListMarker(line string) (markdownListMarker, bool) {
+	match := markdownListMarkerPattern.FindStringSubmatch(line)
+	if match == nil || markdownThematicBreak.MatchString(line) {
+		return markdownListMarker{}, false
+	}
+
+	marker := markdownListMarker{indent: len(match[1])}
+	marker.width = len(match[1]) + len(match[2]) + len(match[4])
+	if len(match[4]) > 4 {
+		// The content is indented code, which isn't supported in list items
+		marker.width = len(match[1]) + len(match[2]) + 1
+	}
+	if match[3] != "" {
+		marker.ordered = true
+		marker.start, _ = strconv.Atoi(match[3])
+	}
+	return marker, true
+}
+
+// markdownListItem is a parsed list item. Its content is markdown.
+type markdownListItem struct {
+	content string
+	task    bool
+	checked bool
+	items   []*markdownListItem
+}
+
+// parseList parses a list starting with the item of marker.
+// It's a checklist if every item has a task box, a nested checklist is a list with the checklist style.
+func parseList(lines []string, marker markdownListMarker) ([]EditorJSBlock, int) {
+	width := marker.width
+	i := 1
+	for i < len(lines) {
+		line := lines[i]
+		if isBlankLine(line) {
+			next := i
+			for next < len(lines) && isBlankLine(lines[next]) {
+				next++
+			}
+			if next == len(lines) {
+				break
+			}
+			sibling, ok := parseListMarker(lines[next])
+			if leadingSpaces(lines[next]) < width && !(ok && sibling.ordered == marker.ordered) {
+				break
+			}
+			i = next
+			continue
+		}
+
+		if leadingSpaces(line) >= width {
+			i++
+			continue
+		}
+		if sibling, ok := parseListMarker(line); ok {
+			if sibling.ordered != marker.ordered {
+				break
+			}
+			width = sibling.width
+			i++
+			continue
+		}
+		if interruptsParagraph(line) {
+			break
+		}
+		// Lazy continuation of the item's text
+		i++
+	}
+
+	items := parseListItems(lines[:i])
+	if len(items) > 0 && allTasks(items) {
+		if !hasNestedItems(items) {
+			checklist := &checklist{Items: []checklistItem{}}
+			for _, item := range items {
+				checklist.Items = append(checklist.Items, checklistItem{Text: markdownInlineToHTML(item.content, " "), Checked: item.checked})
+			}
+			return []EditorJSBlock{newImportBlock("checklist", checklist)}, i
+		}
+		return []EditorJSBlock{newImportBlock("list", &list{Style: "checklist", Items: editorJSListItems(items, true)})}, i
+	}
+
+	list := &list{Style: "unordered", Items: editorJSListItems(items, false)}
+	if marker.ordered {
+		list.Style = "ordered"
+		list.Meta.Start = marker.start
+	}
+	return []EditorJSBlock{newImportBlock("list", list)}, i
+}
+
+// parseListItems splits the lines of a list into its items, parsing the nested lists of every item
+func parseListItems(lines []string) []*markdownListItem {
+	items := []*markdownListItem{}
+	var current *markdownListItem
+	width := 0
+	body := []string{}
+	for _, line := range lines {
+		if marker, ok := parseListMarker(line); ok && (current == nil || marker.indent < width) {
+			if current != nil {
+				current.parseBody(body)
+			}
+			current = &markdownListItem{}
+			items = append(items, current)
+			width = marker.width
+			body = []string{""}
+			if marker.width < len(line) {
+				body[0] = line[marker.width:]
+			}
+			continue
+		}
+		if current != nil {
+			body = append(body, removeIndent(line, width))
+		}
+	}
+	if current != nil {
+		current.parseBody(body)
+	}
+
+	return items
+}
+
+// parseBody parses the lines of a list item without the list marker and indentation.
+// The lines up to the first nested list item are the content of the item, the rest are its nested items.
+func (item *markdownListItem) parseBody(body []string) {
+	content := []string{}
+	i := 0
+	for ; i < len(body); i++ {
+		if _, ok := parseListMarker(body[i]); ok && leadingSpaces(body[i]) < 4 && i > 0 {
+			break
+		}
+		if !isBlankLine(body[i]) {
+			content = append(content, strings.TrimLeft(body[i], " "))
+		}
+	}
+
+	item.content = strings.Join(content, "\n")
+	if match := markdownTaskBox.FindStringSubmatch(item.content); match != nil {
+		item.task = true
+		item.checked = match[1] != " "
+		item.content = item.content[len(match[0]):]
+	}
+	item.items = parseListItems(body[i:])
+}
+
+// allTasks reports whether every item and nested item has a task box
+func allTasks(items []*markdownListItem) bool {
+	for _, item := range items {
+		if !item.task || !allTasks(item.items) {
+			return false
+		}
+	}
+	return true
+}
+
+// hasNestedItems reports whether any of the items has nested items
+func hasNestedItems(items []*markdownListItem) bool {
+	for _, item := range items {
+		if len(item.items) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// editorJSListItems converts parsed list items into the items of a list block
+func editorJSListItems(items []*markdownListItem, checklist bool) []listItem {
+	results := []listItem{}
+	for _, item := range items {
+		content := item.content
+		if item.task && !checklist {
+			// The task box is kept as text in lists that aren't checklists
+			content = checkboxMarkdown(item.checked) + content
+		}
+		result := listItem{Content: markdownInlineToHTML(content, " "), Items: editorJSListItems(item.items, checklist)}
+		result.Meta.Checked = checklist && item.checked
+		results = append(results, result)
+	}
+	return results
+}
+
+// newImportBlock creates a block with data marshalled from one of the block data types, which can't fail
+func newImportBlock(blockType string, data interface{}) EditorJSBlock {
+	raw, _ := json.Marshal(data)
+	return EditorJSBlock{Type: blockType, Data: raw}
+}
+
+var (
+	markdownFence             = regexp.MustCompile("^( {0,3})(`{3,}|~{3,})(.*)$")
+	markdownATXHeading        = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ \t]+(.*))?$`)
+	markdownClosingHashes     = regexp.MustCompile(`(?:^|[ \t]+)#+[ \t]*$`)
+	markdownThematicBreak     = regexp.MustCompile(`^ {0,3}(?:(?:\*[ \t]*){3,}|(?:-[ \t]*){3,}|(?:_[ \t]*){3,})$`)
+	markdownSetextUnderline   = regexp.MustCompile(`^ {0,3}(?:=+|-+)[ \t]*$`)
+	markdownTableDelimiter    = regexp.MustCompile(`^ {0,3}\|?[ \t]*:?-+:?[ \t]*(?:\|[ \t]*:?-+:?[ \t]*)*\|?[ \t]*$`)
+	markdownListMarkerPattern = regexp.MustCompile(`^( {0,3})([-+*]|(\d{1,9})[.)])( +|$)`)
+	markdownTaskBox           = regexp.MustCompile(`^\[([ xX])\](?: |$)`)
+	markdownAlert             = regexp.MustCompile(`^\[!([A-Za-z]+)\][ \t]*$`)
+	markdownAlertTitle        = regexp.MustCompile(`^\*\*(.+)\*\*[ \t]*$`)
+	markdownImage             = regexp.MustCompile(`^!\[((?:[^\]\\]|\\.)*)\]\(\s*(\S+?)(?:\s+"((?:[^"\\]|\\.)*)")?\s*\)$`)
+	// markdownHTMLTagLine matches a line that only holds a start or end tag, which starts an html block that can't interrupt a paragraph
+	markdownHTMLTagLine = regexp.MustCompile(`^ {0,3}(?:<[A-Za-z][A-Za-z0-9-]*(?:\s+[A-Za-z_:][A-Za-z0-9_.:-]*(?:\s*=\s*(?:[^\s"'=<>` + "`" + `]+|'[^']*'|"[^"]*"))?)*\s*/?>|</[A-Za-z][A-Za-z0-9-]*\s*>)\s*$`)
+	// markdownHTMLBlock matches the start of an html comment or of a block level element
+	markdownHTMLBlock = regexp.MustCompile(`(?i)^ {0,3}(?:<!--|</?(?:address|article|aside|blockquote|details|dialog|div|dl|fieldset|figcaption|figure|footer|form|h[1-6]|header|hr|iframe|li|main|nav|ol|p|pre|section|summary|table|tbody|td|tfoot|th|thead|tr|ul|video)(?:[ \t/>]|$))`)
+)
+
+// isBlankLine reports whether line only holds whitespace
+func isBlankLine(line string) bool {
+	return strings.TrimSpace(line) == ""
+}
+
+// leadingSpaces returns the number of spaces at the start of line
+func leadingSpaces(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+// removeIndent removes up to n spaces from the start of line
+func removeIndent(line string, n int) string {
+	spaces := leadingSpaces(line)
+	if spaces > n {
+		spaces = n
+	}
+	return line[spaces:]
+}
+
+// expandLeadingTabs replaces the tabs of the indentation of line with spaces, using tab stops of 4
+func expandLeadingTabs(line string) string {
+	column := 0
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case ' ':
+			column++
+		case '\t':
+			column += 4 - column%4
+		default:
+			if i == column {
+				return line
+			}
+			return strings.Repeat(" ", column) + line[i:]
+		}
+	}
+	return line
+}
+
+// trimBlankLines removes the blank lines at the start and end of lines
+func trimBlankLines(lines []string) []string {
+	for len(lines) > 0 && isBlankLine(lines[0]) {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && isBlankLine(lines[len(lines)-1]) {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// unescapeMarkdown removes the backslashes of escaped punctuation
+func unescapeMarkdown(text string) string {
+	result := &strings.Builder{}
+	for i := 0; i < len(text); i++ {
+		if text[i] == '\\' && i+1 < len(text) && isASCIIPunctuation(text[i+1]) {
+			i++
+		}
+		result.WriteByte(text[i])
+	}
+	return result.String()
+}
+
+func isASCIIPunctuation(c byte) bool {
+	return strings.IndexByte("!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", c) >= 0
+}
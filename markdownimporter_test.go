@@ -0,0 +1,171 @@
+package goeditorjs_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/davidscottmills/goeditorjs"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_MarkdownImporter_ImportMarkdown(t *testing.T) {
+	testData := []struct {
+		markdown       string
+		expectedType   string
+		expectedResult string
+	}{
+		{markdown: "## Heading *two* ##", expectedType: "header", expectedResult: `{"text":"Heading <i>two</i>","level":2}`},
+		{markdown: "Setext\n===", expectedType: "header", expectedResult: `{"text":"Setext","level":1}`},
+		{markdown: "Some **bold**\ntext", expectedType: "paragraph", expectedResult: `{"text":"Some <b>bold</b> text","alignment":"left"}`},
+		{markdown: "---", expectedType: "delimiter", expectedResult: `{}`},
+		{markdown: "- One\n- Two\n  - Nested\n\n- Three",
+			expectedType: "list",
+			expectedResult: `{"style":"unordered","meta":{"start":0,"counterType":"","checked":false},"items":[` +
+				`{"content":"One","meta":{"start":0,"counterType":"","checked":false},"items":[]},` +
+				`{"content":"Two","meta":{"start":0,"counterType":"","checked":false},"items":[{"content":"Nested","meta":{"start":0,"counterType":"","checked":false},"items":[]}]},` +
+				`{"content":"Three","meta":{"start":0,"counterType":"","checked":false},"items":[]}]}`},
+		{markdown: "3. One\n   lazy\n4. Two",
+			expectedType: "list",
+			expectedResult: `{"style":"ordered","meta":{"start":3,"counterType":"","checked":false},"items":[` +
+				`{"content":"One lazy","meta":{"start":0,"counterType":"","checked":false},"items":[]},` +
+				`{"content":"Two","meta":{"start":0,"counterType":"","checked":false},"items":[]}]}`},
+		{markdown: "- [x] Done\n- [ ] Todo", expectedType: "checklist", expectedResult: `{"items":[{"text":"Done","checked":true},{"text":"Todo","checked":false}]}`},
+		{markdown: "- [x] Done\n  - [ ] Todo",
+			expectedType: "list",
+			expectedResult: `{"style":"checklist","meta":{"start":0,"counterType":"","checked":false},"items":[` +
+				`{"content":"Done","meta":{"start":0,"counterType":"","checked":true},"items":[{"content":"Todo","meta":{"start":0,"counterType":"","checked":false},"items":[]}]}]}`},
+		{markdown: "```go\nfmt.Println(\"<x>\")\n\n```", expectedType: "code", expectedResult: `{"code":"fmt.Println(\"<x>\")\n","language":"go"}`},
+		{markdown: "    indented\n\n    code", expectedType: "code", expectedResult: `{"code":"indented\n\ncode","language":""}`},
+		{markdown: `![alt](https://example.com/a.png "The *caption*")`, expectedType: "image",
+			expectedResult: `{"file":{"url":"https://example.com/a.png","name":"","size":0,"extension":""},"caption":"The <i>caption</i>","withBorder":false,"withBackground":false,"stretched":false}`},
+		{markdown: "| A | **B** |\n| :-- | --: |\n| 1 \\| 2 | 3 |\n| 4 |", expectedType: "table",
+			expectedResult: `{"withHeadings":true,"content":[["A","<b>B</b>"],["1 | 2","3"],["4",""]]}`},
+		{markdown: "|  |  |\n| --- | --- |\n| 1 | 2 |", expectedType: "table", expectedResult: `{"withHeadings":false,"content":[["1","2"]]}`},
+		{markdown: "> Quoted\n> _text_\n>\n> — Author", expectedType: "quote", expectedResult: `{"text":"Quoted<br><i>text</i>","caption":"Author","alignment":"left"}`},
+		{markdown: "> [!WARNING]\n> **Title**\n>\n> The message", expectedType: "warning", expectedResult: `{"title":"Title","message":"The message"}`},
+		{markdown: "> [!NOTE]\n> The message", expectedType: "warning", expectedResult: `{"title":"","message":"The message"}`},
+		{markdown: "<div class=\"x\">\n*raw*\n</div>", expectedType: "raw", expectedResult: `{"html":"<div class=\"x\">\n*raw*\n</div>"}`},
+	}
+
+	importer := goeditorjs.NewMarkdownImporter()
+	for _, td := range testData {
+		document, err := importer.ImportMarkdown(td.markdown)
+		require.NoError(t, err)
+		require.Len(t, document.Blocks, 1, td.markdown)
+		require.Equal(t, td.expectedType, document.Blocks[0].Type, td.markdown)
+		require.JSONEq(t, td.expectedResult, string(document.Blocks[0].Data), td.markdown)
+	}
+}
+
+func Test_MarkdownImporter_ImportMarkdown_Splits_Blocks(t *testing.T) {
+	markdown := "# Title\nParagraph\n- Item\n\n> Quote\n```\ncode\n```\n***\n\n\tTabbed code"
+
+	document, err := goeditorjs.NewMarkdownImporter().ImportMarkdown(markdown)
+	require.NoError(t, err)
+
+	types := []string{}
+	for _, block := range document.Blocks {
+		types = append(types, block.Type)
+	}
+	require.Equal(t, []string{"header", "paragraph", "list", "quote", "code", "delimiter", "code"}, types)
+}
+
+func Test_MarkdownImporter_CodeBlockType(t *testing.T) {
+	importer := goeditorjs.NewMarkdownImporter()
+	importer.CodeBlockType = "codeBox"
+
+	document, err := importer.ImportMarkdown("```html\n<b>x</b>\n```")
+	require.NoError(t, err)
+	require.Equal(t, "codeBox", document.Blocks[0].Type)
+	require.JSONEq(t, `{"code":"&lt;b&gt;x&lt;/b&gt;","language":"html"}`, string(document.Blocks[0].Data))
+}
+
+func Test_MarkdownImporter_BlockParsers(t *testing.T) {
+	importer := goeditorjs.NewMarkdownImporter()
+	importer.RegisterBlockParsers(goeditorjs.MarkdownBlockParserFunc(func(lines []string) ([]goeditorjs.EditorJSBlock, int, error) {
+		if !strings.HasPrefix(lines[0], ":::") {
+			return nil, 0, nil
+		}
+		for i := 1; i < len(lines); i++ {
+			if lines[i] == ":::" {
+				text := goeditorjs.InlineMarkdownToHTML(strings.Join(lines[1:i], "\n"))
+				data, err := json.Marshal(map[string]string{"text": text})
+				return []goeditorjs.EditorJSBlock{{Type: "callout", Data: data}}, i + 1, err
+			}
+		}
+		return nil, 0, nil
+	}))
+
+	document, err := importer.ImportMarkdown("Before\n\n:::\n**Custom**\n:::\n\n:::\nUnclosed")
+	require.NoError(t, err)
+	require.Len(t, document.Blocks, 3)
+	require.Equal(t, "paragraph", document.Blocks[0].Type)
+	require.Equal(t, "callout", document.Blocks[1].Type)
+	require.JSONEq(t, `{"text":"<b>Custom</b>"}`, string(document.Blocks[1].Data))
+	require.Equal(t, "paragraph", document.Blocks[2].Type)
+
+	parseErr := errors.New("parse error")
+	importer.BlockParsers = []goeditorjs.MarkdownBlockParser{goeditorjs.MarkdownBlockParserFunc(func(lines []string) ([]goeditorjs.EditorJSBlock, int, error) {
+		return nil, 0, parseErr
+	})}
+	_, err = importer.ImportMarkdown("Text")
+	require.Equal(t, parseErr, err)
+}
+
+func Test_MarkdownImporter_Round_Trips(t *testing.T) {
+	markdown := strings.Join([]string{
+		"# Title with *emphasis*",
+		"Some **bold**, ~~struck~~, ==marked== and `code` text with [a link](https://example.com).",
+		"- One\n- Two\n  - Nested",
+		"3. Three\n3. Four",
+		"- [x] Done\n- [ ] Todo",
+		"```go\nfmt.Println(\"<x>\")\n```",
+		`![alt text](https://example.com/a.png "Caption")`,
+		"| A | B |\n| --- | --- |\n| 1 \\| 2 | **3** |",
+		"> Quoted\\\n> text\n>\n> — Author",
+		"> [!WARNING]\n> **Title**\n>\n> The message",
+		"***",
+		"<div class=\"x\">raw</div>",
+	}, "\n\n")
+
+	document, err := goeditorjs.NewMarkdownImporter().ImportMarkdown(markdown)
+	require.NoError(t, err)
+
+	result, err := goeditorjs.NewDefaultMarkdownEngine().GenerateMarkdownFromDocument(document)
+	require.NoError(t, err)
+	require.Equal(t, markdown, result)
+}
+
+func Test_MarkdownImporter_HTMLImporter(t *testing.T) {
+	importer := goeditorjs.NewMarkdownImporter()
+	document, err := importer.ImportMarkdown("<p>Kept</p>")
+	require.NoError(t, err)
+	require.Equal(t, "raw", document.Blocks[0].Type)
+	require.JSONEq(t, `{"html":"<p>Kept</p>"}`, string(document.Blocks[0].Data))
+
+	htmlImporter := goeditorjs.NewDefaultHTMLImporter()
+	htmlImporter.UnknownElementPolicy = goeditorjs.UnknownElementRaw
+	importer.HTMLImporter = htmlImporter
+	markdown := strings.Join([]string{
+		"Text\n<x-tag>",
+		"<x-tag>",
+		"<section>\n<p>Converted</p>\n</section>",
+		"<!-- comment -->",
+		"<p style=\"text-align:center\">Centered <b>text</b></p>",
+		"<img src=\"a.png\" alt=\"Alt\" class=\"image-tool--stretched\"/>",
+	}, "\n\n")
+	document, err = importer.ImportMarkdown(markdown)
+	require.NoError(t, err)
+	require.Len(t, document.Blocks, 6)
+	require.JSONEq(t, `{"text":"Text <x-tag>","alignment":"left"}`, string(document.Blocks[0].Data))
+	require.JSONEq(t, `{"html":"<x-tag>"}`, string(document.Blocks[1].Data))
+	require.JSONEq(t, `{"html":"<section>\n<p>Converted</p>\n</section>"}`, string(document.Blocks[2].Data))
+	require.JSONEq(t, `{"html":"<!-- comment -->"}`, string(document.Blocks[3].Data))
+	require.Equal(t, "paragraph", document.Blocks[4].Type)
+	require.JSONEq(t, `{"text":"Centered <b>text</b>","alignment":"center"}`, string(document.Blocks[4].Data))
+	require.Equal(t, "image", document.Blocks[5].Type)
+	require.JSONEq(t, `{"file":{"url":"a.png","name":"","size":0,"extension":""},"caption":"Alt","withBorder":false,"withBackground":false,"stretched":true}`,
+		string(document.Blocks[5].Data))
+}
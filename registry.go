@@ -0,0 +1,111 @@
+package goeditorjs
+
+import (
+	"sort"
+	"sync"
+)
+
+// BlockHandler is the interface shared by HTMLBlockHandler, MarkdownBlockHandler, TextBlockHandler, AsciiDocBlockHandler and RSTBlockHandler
+type BlockHandler interface {
+	Type() string // Type returns the type the block handler supports as a string
+}
+
+// Registry is a set of block handlers that engines can be built from.
+// Engines built from a registry see handlers registered or unregistered later on. It's safe for concurrent use.
+type Registry struct {
+	mu       sync.RWMutex
+	handlers map[string]BlockHandler
+	aliases  map[string]string
+}
+
+// NewRegistry creates a new, empty Registry
+func NewRegistry() *Registry {
+	return &Registry{handlers: make(map[string]BlockHandler), aliases: make(map[string]string)}
+}
+
+// NewDefaultRegistry creates a new Registry with all the built-in block handlers registered, using their default options
+func NewDefaultRegistry() *Registry {
+	registry := NewRegistry()
+	registry.Register(
+		&HeaderHandler{},
+		&ParagraphHandler{},
+		&ListHandler{},
+		&CodeBoxHandler{},
+		&RawHTMLHandler{},
+		&ImageHandler{},
+		&TableHandler{},
+		&ChecklistHandler{},
+		&QuoteHandler{},
+		&WarningHandler{},
+		&EmbedHandler{},
+		&LinkToolHandler{},
+		&AttachesHandler{},
+		&DelimiterHandler{},
+		&CodeHandler{},
+	)
+	return registry
+}
+
+// Register registers or replaces block handlers for the blockType given by BlockHandler.Type().
+// A handler registered for a block type that was an alias replaces the alias.
+func (r *Registry) Register(handlers ...BlockHandler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, h := range handlers {
+		r.handlers[h.Type()] = h
+		delete(r.aliases, h.Type())
+	}
+}
+
+// Unregister removes the block handlers or aliases for the block types
+func (r *Registry) Unregister(blockTypes ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, blockType := range blockTypes {
+		delete(r.handlers, blockType)
+		delete(r.aliases, blockType)
+	}
+}
+
+// Alias makes blocks of type alias use the handler registered for blockType, e.g. to handle "codeBox" blocks with the "code" handler.
+// An alias for a block type that has a handler of its own replaces the handler.
+func (r *Registry) Alias(alias, blockType string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.aliases[alias] = blockType
+	delete(r.handlers, alias)
+}
+
+// Lookup returns the block handler for the block type, following aliases, and whether there is one
+func (r *Registry) Lookup(blockType string) (BlockHandler, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if target, ok := r.aliases[blockType]; ok {
+		blockType = target
+	}
+	h, ok := r.handlers[blockType]
+	return h, ok
+}
+
+// Types returns the sorted block types that have a registered handler, without aliases
+func (r *Registry) Types() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	types := make([]string, 0, len(r.handlers))
+	for blockType := range r.handlers {
+		types = append(types, blockType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// Aliases returns a copy of the aliases, mapping each alias to the block type it uses the handler of
+func (r *Registry) Aliases() map[string]string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	aliases := make(map[string]string, len(r.aliases))
+	for alias, blockType := range r.aliases {
+		aliases[alias] = blockType
+	}
+	return aliases
+}
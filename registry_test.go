@@ -0,0 +1,147 @@
+package goeditorjs_test
+
+import (
+	"io/ioutil"
+	"sync"
+	"testing"
+
+	"github.com/davidscottmills/goeditorjs"
+	"github.com/stretchr/testify/require"
+)
+
+type testTypeOnlyHandler struct{}
+
+func (*testTypeOnlyHandler) Type() string {
+	return "typeOnly"
+}
+
+func Test_Registry_Register_Lookup_Unregister(t *testing.T) {
+	registry := goeditorjs.NewRegistry()
+	_, ok := registry.Lookup("header")
+	require.False(t, ok)
+
+	header := &goeditorjs.HeaderHandler{}
+	registry.Register(header, &goeditorjs.ParagraphHandler{})
+	h, ok := registry.Lookup("header")
+	require.True(t, ok)
+	require.Equal(t, header, h)
+	require.Equal(t, []string{"header", "paragraph"}, registry.Types())
+
+	replacement := &goeditorjs.HeaderHandler{}
+	registry.Register(replacement)
+	h, _ = registry.Lookup("header")
+	require.True(t, h == replacement)
+
+	registry.Unregister("header")
+	_, ok = registry.Lookup("header")
+	require.False(t, ok)
+	require.Equal(t, []string{"paragraph"}, registry.Types())
+}
+
+func Test_Registry_Alias(t *testing.T) {
+	registry := goeditorjs.NewDefaultRegistry()
+	registry.Alias("codeBox", "code")
+	require.Equal(t, map[string]string{"codeBox": "code"}, registry.Aliases())
+	require.NotContains(t, registry.Types(), "codeBox")
+
+	h, ok := registry.Lookup("codeBox")
+	require.True(t, ok)
+	require.Equal(t, "code", h.Type())
+
+	registry.Register(&goeditorjs.CodeBoxHandler{})
+	require.Empty(t, registry.Aliases())
+	h, _ = registry.Lookup("codeBox")
+	require.Equal(t, "codeBox", h.Type())
+
+	registry.Alias("codeBox", "code")
+	registry.Unregister("codeBox")
+	require.Empty(t, registry.Aliases())
+	_, ok = registry.Lookup("codeBox")
+	require.False(t, ok)
+}
+
+func Test_NewDefaultRegistry(t *testing.T) {
+	registry := goeditorjs.NewDefaultRegistry()
+	require.Equal(t, []string{"attaches", "checklist", "code", "codeBox", "delimiter", "embed", "header", "image",
+		"linkTool", "list", "paragraph", "quote", "raw", "table", "warning"}, registry.Types())
+}
+
+func Test_NewDefaultEngines(t *testing.T) {
+	content, err := ioutil.ReadFile("examples/test.json")
+	require.NoError(t, err)
+
+	html, err := goeditorjs.NewDefaultHTMLEngine().GenerateHTML(string(content))
+	require.NoError(t, err)
+	require.Contains(t, html, "<h1>")
+
+	md, err := goeditorjs.NewDefaultMarkdownEngine().GenerateMarkdown(string(content))
+	require.NoError(t, err)
+	require.Contains(t, md, "# ")
+
+	text, err := goeditorjs.NewDefaultTextEngine().GenerateText(string(content))
+	require.NoError(t, err)
+	require.NotEmpty(t, text)
+}
+
+func Test_Engines_From_Registry(t *testing.T) {
+	editorJSData := `{"blocks": [{"type": "paragraph","data": {"text": "Text","alignment": "left"}},{"type": "typeOnly","data": {}}]}`
+	registry := goeditorjs.NewRegistry()
+	registry.Register(&goeditorjs.ParagraphHandler{}, &testTypeOnlyHandler{})
+
+	htmlEngine := goeditorjs.NewHTMLEngineFromRegistry(registry)
+	htmlEngine.UnknownBlockPolicy = goeditorjs.UnknownBlockSkip
+	markdownEngine := goeditorjs.NewMarkdownEngineFromRegistry(registry)
+	markdownEngine.UnknownBlockPolicy = goeditorjs.UnknownBlockSkip
+
+	html, err := htmlEngine.GenerateHTML(editorJSData)
+	require.NoError(t, err)
+	require.Equal(t, "<p>Text</p>", html)
+
+	md, err := markdownEngine.GenerateMarkdown(editorJSData)
+	require.NoError(t, err)
+	require.Equal(t, "Text", md)
+
+	// Handlers registered with the engine take precedence over the registry
+	handler := &mockHTMLBlockHandler{typeName: "paragraph"}
+	handler.On("GenerateHTML", goeditorjs.EditorJSBlock{Type: "paragraph", Data: []byte(`{"text": "Text","alignment": "left"}`)}).Return("<p>Mock</p>", nil)
+	htmlEngine.RegisterBlockHandlers(handler)
+	html, err = htmlEngine.GenerateHTML(editorJSData)
+	require.NoError(t, err)
+	require.Equal(t, "<p>Mock</p>", html)
+
+	// Engines see changes to the registry
+	registry.Alias("paragraph", "header")
+	registry.Register(&goeditorjs.HeaderHandler{})
+	md, err = markdownEngine.GenerateMarkdown(`{"blocks": [{"type": "paragraph","data": {"text": "Title","level": 2}}]}`)
+	require.NoError(t, err)
+	require.Equal(t, "## Title", md)
+}
+
+func Test_Engine_Concurrent_Use(t *testing.T) {
+	editorJSData := `{"blocks": [{"type": "paragraph","data": {"text": "Text","alignment": "left"}}]}`
+	registry := goeditorjs.NewDefaultRegistry()
+	htmlEngine := goeditorjs.NewHTMLEngineFromRegistry(registry)
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				switch i % 4 {
+				case 0:
+					registry.Register(&goeditorjs.ParagraphHandler{})
+				case 1:
+					htmlEngine.RegisterBlockHandlers(&goeditorjs.ParagraphHandler{})
+				case 2:
+					htmlEngine.RegisterTuneHandlers(&goeditorjs.HiddenTuneHandler{})
+				default:
+					html, err := htmlEngine.GenerateHTML(editorJSData)
+					require.NoError(t, err)
+					require.Equal(t, "<p>Text</p>", html)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,341 @@
+package roundtrip
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/davidscottmills/goeditorjs"
+)
+
+// Normalizer normalizes the data of a block decoded from json, e.g. to convert a legacy data format.
+// Values are decoded as map[string]interface{}, []interface{}, string, float64, bool or nil.
+type Normalizer func(data interface{}) interface{}
+
+// DefaultNormalizers are the normalizers for the data of the built-in block types
+var DefaultNormalizers = map[string]Normalizer{
+	"list":      normalizeList,
+	"paragraph": normalizeParagraph,
+	"codeBox":   normalizeCodeBox,
+}
+
+// trailingBreaks matches the line breaks at the end of inline html
+var trailingBreaks = regexp.MustCompile(`(?i)(?:<br\s*/?>)+$`)
+
+// normalizeParagraph removes the line breaks at the end of the text, which editor.js leaves behind
+// but browsers don't render and importers don't keep
+func normalizeParagraph(data interface{}) interface{} {
+	paragraph, ok := data.(map[string]interface{})
+	if !ok {
+		return data
+	}
+
+	if text, ok := paragraph["text"].(string); ok {
+		paragraph["text"] = trailingBreaks.ReplaceAllString(text, "")
+	}
+	return paragraph
+}
+
+// normalizeCodeBox reduces the code to its text and removes the theme.
+// The highlighting markup of the code and the theme aren't part of the output of the handlers, so they can't survive a round trip.
+func normalizeCodeBox(data interface{}) interface{} {
+	codeBox, ok := data.(map[string]interface{})
+	if !ok {
+		return data
+	}
+
+	delete(codeBox, "theme")
+	blockData, err := json.Marshal(map[string]interface{}{"code": codeBox["code"]})
+	if err != nil {
+		return codeBox
+	}
+	if text, err := (&goeditorjs.CodeBoxHandler{}).GenerateText(goeditorjs.EditorJSBlock{Type: "codeBox", Data: blockData}); err == nil {
+		codeBox["code"] = text
+	}
+	return codeBox
+}
+
+// normalizeList converts the string items of the legacy list tool into item objects
+// and defaults the start of ordered lists to 1
+func normalizeList(data interface{}) interface{} {
+	list, ok := data.(map[string]interface{})
+	if !ok {
+		return data
+	}
+
+	list["items"] = normalizeListItems(list["items"])
+	if list["style"] == "ordered" {
+		meta, ok := list["meta"].(map[string]interface{})
+		if !ok {
+			meta = map[string]interface{}{}
+			list["meta"] = meta
+		}
+		if isZeroValue(meta["start"]) {
+			meta["start"] = float64(1)
+		}
+	}
+	return list
+}
+
+func normalizeListItems(items interface{}) interface{} {
+	values, ok := items.([]interface{})
+	if !ok {
+		return items
+	}
+
+	for i, value := range values {
+		switch item := value.(type) {
+		case string:
+			values[i] = map[string]interface{}{"content": item}
+		case map[string]interface{}:
+			item["items"] = normalizeListItems(item["items"])
+		}
+	}
+	return values
+}
+
+// BlockDiff is the semantic difference between a block of a document and the corresponding block of the imported document
+type BlockDiff struct {
+	// Index is the index of the block in the document, or -1 if the block was added by the round trip
+	Index int
+	// RoundTripIndex is the index of the block in the imported document, or -1 if the block was lost by the round trip
+	RoundTripIndex int
+	// Type is the type of the block in the document, or of the added block
+	Type string
+	// Differences describe the differences of the block, e.g. `items[0].content: "One" != "Two"`
+	Differences []string
+}
+
+// String describes the differences of the block
+func (d BlockDiff) String() string {
+	switch {
+	case d.RoundTripIndex < 0:
+		return fmt.Sprintf("block %d (%s): lost by the round trip", d.Index, d.Type)
+	case d.Index < 0:
+		return fmt.Sprintf("block %d (%s) of the round trip: added by the round trip", d.RoundTripIndex, d.Type)
+	}
+	return fmt.Sprintf("block %d (%s): %s", d.Index, d.Type, strings.Join(d.Differences, ", "))
+}
+
+// diffBlocks compares the blocks of a document with the blocks of the imported document.
+// Blocks are matched up by their types, so a lost or added block doesn't make the blocks after it differ.
+func diffBlocks(blocks, imported []goeditorjs.EditorJSBlock, normalizers map[string]Normalizer) ([]BlockDiff, error) {
+	diffs := []BlockDiff{}
+	i, j := 0, 0
+	for _, match := range matchBlockTypes(blocks, imported) {
+		// The blocks between matches are compared pairwise, the remaining ones were lost or added
+		for ; i < match[0] && j < match[1]; i, j = i+1, j+1 {
+			diff, err := diffBlock(i, blocks[i], j, imported[j], normalizers)
+			if err != nil {
+				return nil, err
+			}
+			if len(diff.Differences) > 0 {
+				diffs = append(diffs, diff)
+			}
+		}
+		for ; i < match[0]; i++ {
+			diffs = append(diffs, BlockDiff{Index: i, RoundTripIndex: -1, Type: blocks[i].Type})
+		}
+		for ; j < match[1]; j++ {
+			diffs = append(diffs, BlockDiff{Index: -1, RoundTripIndex: j, Type: imported[j].Type})
+		}
+
+		if match[0] == len(blocks) {
+			break
+		}
+		diff, err := diffBlock(i, blocks[i], j, imported[j], normalizers)
+		if err != nil {
+			return nil, err
+		}
+		if len(diff.Differences) > 0 {
+			diffs = append(diffs, diff)
+		}
+		i, j = i+1, j+1
+	}
+
+	return diffs, nil
+}
+
+// matchBlockTypes returns the index pairs of the longest common subsequence of the block types,
+// followed by the pair of lengths as a sentinel
+func matchBlockTypes(blocks, imported []goeditorjs.EditorJSBlock) [][2]int {
+	lengths := make([][]int, len(blocks)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(imported)+1)
+	}
+	for i := len(blocks) - 1; i >= 0; i-- {
+		for j := len(imported) - 1; j >= 0; j-- {
+			switch {
+			case blocks[i].Type == imported[j].Type:
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			case lengths[i+1][j] >= lengths[i][j+1]:
+				lengths[i][j] = lengths[i+1][j]
+			default:
+				lengths[i][j] = lengths[i][j+1]
+			}
+		}
+	}
+
+	matches := [][2]int{}
+	for i, j := 0, 0; i < len(blocks) && j < len(imported); {
+		switch {
+		case blocks[i].Type == imported[j].Type:
+			matches = append(matches, [2]int{i, j})
+			i, j = i+1, j+1
+		case lengths[i+1][j] >= lengths[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	return append(matches, [2]int{len(blocks), len(imported)})
+}
+
+// diffBlock compares the type and the normalized data of two blocks
+func diffBlock(i int, block goeditorjs.EditorJSBlock, j int, imported goeditorjs.EditorJSBlock, normalizers map[string]Normalizer) (BlockDiff, error) {
+	diff := BlockDiff{Index: i, RoundTripIndex: j, Type: block.Type, Differences: []string{}}
+	if block.Type != imported.Type {
+		diff.Differences = append(diff.Differences, fmt.Sprintf("type: %q != %q", block.Type, imported.Type))
+	}
+
+	data, err := decodeBlockData(block, normalizers)
+	if err != nil {
+		return diff, fmt.Errorf("block %d: %w", i, err)
+	}
+	importedData, err := decodeBlockData(imported, normalizers)
+	if err != nil {
+		return diff, fmt.Errorf("block %d of the round trip: %w", j, err)
+	}
+
+	diff.Differences = append(diff.Differences, diffValues("", data, importedData)...)
+	return diff, nil
+}
+
+// decodeBlockData decodes the data of the block and normalizes it with the normalizer of its type
+func decodeBlockData(block goeditorjs.EditorJSBlock, normalizers map[string]Normalizer) (interface{}, error) {
+	var data interface{}
+	if len(block.Data) > 0 {
+		if err := json.Unmarshal(block.Data, &data); err != nil {
+			return nil, err
+		}
+	}
+	if normalizer, ok := normalizers[block.Type]; ok {
+		data = normalizer(data)
+	}
+	return data, nil
+}
+
+// diffValues describes the differences between two decoded json values.
+// Missing values are equal to zero values, like "", false, 0, null and empty arrays and objects.
+func diffValues(path string, a, b interface{}) []string {
+	if isZeroValue(a) && isZeroValue(b) {
+		return nil
+	}
+	a, b = emptyLike(a, b), emptyLike(b, a)
+
+	switch a := a.(type) {
+	case map[string]interface{}:
+		if b, ok := b.(map[string]interface{}); ok {
+			keys := map[string]bool{}
+			for key := range a {
+				keys[key] = true
+			}
+			for key := range b {
+				keys[key] = true
+			}
+			sorted := []string{}
+			for key := range keys {
+				sorted = append(sorted, key)
+			}
+			sort.Strings(sorted)
+
+			diffs := []string{}
+			for _, key := range sorted {
+				diffs = append(diffs, diffValues(joinPath(path, key), a[key], b[key])...)
+			}
+			return diffs
+		}
+	case []interface{}:
+		if b, ok := b.([]interface{}); ok {
+			diffs := []string{}
+			for i := 0; i < len(a) || i < len(b); i++ {
+				var av, bv interface{}
+				if i < len(a) {
+					av = a[i]
+				}
+				if i < len(b) {
+					bv = b[i]
+				}
+				diffs = append(diffs, diffValues(fmt.Sprintf("%s[%d]", path, i), av, bv)...)
+			}
+			return diffs
+		}
+	}
+
+	if reflect.DeepEqual(a, b) {
+		return nil
+	}
+	if path == "" {
+		path = "data"
+	}
+	return []string{fmt.Sprintf("%s: %s != %s", path, formatValue(a), formatValue(b))}
+}
+
+// emptyLike returns an empty object or array for a missing value if the other value is an object or array, so their contents are compared
+func emptyLike(value, other interface{}) interface{} {
+	if value != nil {
+		return value
+	}
+	switch other.(type) {
+	case map[string]interface{}:
+		return map[string]interface{}{}
+	case []interface{}:
+		return []interface{}{}
+	}
+	return value
+}
+
+// isZeroValue reports whether a decoded json value is a zero value
+func isZeroValue(value interface{}) bool {
+	switch value := value.(type) {
+	case nil:
+		return true
+	case string:
+		return value == ""
+	case float64:
+		return value == 0
+	case bool:
+		return !value
+	case []interface{}:
+		return len(value) == 0
+	case map[string]interface{}:
+		for _, v := range value {
+			if !isZeroValue(v) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// formatValue formats a decoded json value as json, without escaping html
+func formatValue(value interface{}) string {
+	formatted := &strings.Builder{}
+	encoder := json.NewEncoder(formatted)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return fmt.Sprint(value)
+	}
+	return strings.TrimSuffix(formatted.String(), "\n")
+}
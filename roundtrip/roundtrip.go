@@ -0,0 +1,225 @@
+// Package roundtrip checks that editor.js documents survive being generated by an engine and imported again.
+// It can be used in the tests of custom handlers and element converters, with a corpus of editor.js fixtures.
+package roundtrip
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/davidscottmills/goeditorjs"
+)
+
+// GenerateFunc generates the output of a document, e.g. HTMLEngine.GenerateHTMLFromDocument
+type GenerateFunc func(document *goeditorjs.Document) (string, error)
+
+// ImportFunc imports generated output into a document, e.g. HTMLImporter.ImportHTML
+type ImportFunc func(output string) (*goeditorjs.Document, error)
+
+// Checker generates documents, imports the output and compares the result with the original documents
+type Checker struct {
+	Generate GenerateFunc
+	Import   ImportFunc
+	// Normalizers normalize the decoded data of blocks before they are compared, keyed by block type
+	Normalizers map[string]Normalizer
+}
+
+// NewHTMLChecker creates a Checker for the round trip through html, using a copy of DefaultNormalizers
+func NewHTMLChecker(engine *goeditorjs.HTMLEngine, importer *goeditorjs.HTMLImporter) *Checker {
+	return &Checker{Generate: engine.GenerateHTMLFromDocument, Import: importer.ImportHTML, Normalizers: defaultNormalizers()}
+}
+
+// NewMarkdownChecker creates a Checker for the round trip through markdown, using a copy of DefaultNormalizers
+func NewMarkdownChecker(engine *goeditorjs.MarkdownEngine, importer *goeditorjs.MarkdownImporter) *Checker {
+	return &Checker{Generate: engine.GenerateMarkdownFromDocument, Import: importer.ImportMarkdown, Normalizers: defaultNormalizers()}
+}
+
+// defaultNormalizers returns a copy of DefaultNormalizers, so the normalizers of a checker can be changed on their own
+func defaultNormalizers() map[string]Normalizer {
+	normalizers := make(map[string]Normalizer, len(DefaultNormalizers))
+	for blockType, normalizer := range DefaultNormalizers {
+		normalizers[blockType] = normalizer
+	}
+	return normalizers
+}
+
+// NewHTMLImporter creates an HTMLImporter for the html of the default HTMLEngine.
+// Inline html isn't sanitized, as the engine doesn't sanitize it either, and pre elements become codeBox blocks.
+func NewHTMLImporter() *goeditorjs.HTMLImporter {
+	importer := goeditorjs.NewDefaultHTMLImporter()
+	importer.InlinePolicy = nil
+	importer.RegisterElementConverters(&goeditorjs.CodeElementConverter{BlockType: "codeBox"})
+	return importer
+}
+
+// NewMarkdownImporter creates a MarkdownImporter for the markdown of the default MarkdownEngine.
+// Code blocks become codeBox blocks, and block level html, like the html of aligned paragraphs, is converted
+// with NewHTMLImporter, keeping elements it has no converter for as raw blocks.
+func NewMarkdownImporter() *goeditorjs.MarkdownImporter {
+	htmlImporter := NewHTMLImporter()
+	htmlImporter.UnknownElementPolicy = goeditorjs.UnknownElementRaw
+	importer := goeditorjs.NewMarkdownImporter()
+	importer.CodeBlockType = "codeBox"
+	importer.HTMLImporter = htmlImporter
+	return importer
+}
+
+// Fixture is a named editor.js document of a corpus
+type Fixture struct {
+	Name     string
+	Document *goeditorjs.Document
+}
+
+// LoadFixtures loads the editor.js json files matching the glob patterns, e.g. "testdata/*.json".
+// The fixtures are named after their files.
+func LoadFixtures(patterns ...string) ([]Fixture, error) {
+	paths := []string{}
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, matches...)
+	}
+	sort.Strings(paths)
+
+	fixtures := []Fixture{}
+	for _, path := range paths {
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		document, err := goeditorjs.ParseDocument(string(content))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		fixtures = append(fixtures, Fixture{Name: path, Document: document})
+	}
+
+	return fixtures, nil
+}
+
+// Report is the result of the round trip of a document
+type Report struct {
+	Name string
+	// Output is the output generated from the document
+	Output string
+	// RoundTripOutput is the output generated from the imported document
+	RoundTripOutput string
+	// Idempotent is set if another round trip of the imported document doesn't change the output any further
+	Idempotent bool
+	// Diffs are the semantic differences between the blocks of the document and the imported document
+	Diffs []BlockDiff
+}
+
+// Stable reports whether the output generated from the imported document is the output generated from the document
+func (r *Report) Stable() bool {
+	return r.Output == r.RoundTripOutput
+}
+
+// Equivalent reports whether the imported document has the same blocks as the document
+func (r *Report) Equivalent() bool {
+	return len(r.Diffs) == 0
+}
+
+// OK reports whether the round trip is stable, equivalent and idempotent
+func (r *Report) OK() bool {
+	return r.Stable() && r.Equivalent() && r.Idempotent
+}
+
+// String describes the problems of the round trip, one per line
+func (r *Report) String() string {
+	lines := []string{}
+	if !r.Stable() {
+		lines = append(lines, fmt.Sprintf("%s: output changed by the round trip:\n%s\n---\n%s", r.Name, r.Output, r.RoundTripOutput))
+	}
+	if !r.Idempotent {
+		lines = append(lines, fmt.Sprintf("%s: output keeps changing with more round trips", r.Name))
+	}
+	for _, diff := range r.Diffs {
+		lines = append(lines, r.Name+": "+diff.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Check makes a round trip with the document and reports the differences.
+// An error is returned if the document can't be generated or imported.
+func (c *Checker) Check(name string, document *goeditorjs.Document) (*Report, error) {
+	report := &Report{Name: name}
+	output, imported, err := c.roundTrip(document)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	report.Output = output
+
+	roundTripOutput, reimported, err := c.roundTrip(imported)
+	if err != nil {
+		return nil, fmt.Errorf("%s: round trip: %w", name, err)
+	}
+	report.RoundTripOutput = roundTripOutput
+
+	finalOutput, err := c.Generate(reimported)
+	if err != nil {
+		return nil, fmt.Errorf("%s: second round trip: %w", name, err)
+	}
+	report.Idempotent = finalOutput == roundTripOutput
+
+	report.Diffs, err = diffBlocks(document.Blocks, imported.Blocks, c.Normalizers)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return report, nil
+}
+
+// CheckFixtures checks every fixture
+func (c *Checker) CheckFixtures(fixtures []Fixture) ([]*Report, error) {
+	reports := []*Report{}
+	for _, fixture := range fixtures {
+		report, err := c.Check(fixture.Name, fixture.Document)
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
+
+// roundTrip generates the output of the document and imports it
+func (c *Checker) roundTrip(document *goeditorjs.Document) (string, *goeditorjs.Document, error) {
+	output, err := c.Generate(document)
+	if err != nil {
+		return "", nil, err
+	}
+	imported, err := c.Import(output)
+	return output, imported, err
+}
+
+// TestingT is the part of *testing.T used by Assert
+type TestingT interface {
+	Errorf(format string, args ...interface{})
+}
+
+// Assert checks every fixture and reports the fixtures whose round trip isn't OK as errors of t.
+// It returns whether every round trip is OK.
+func (c *Checker) Assert(t TestingT, fixtures ...Fixture) bool {
+	if helper, ok := t.(interface{ Helper() }); ok {
+		helper.Helper()
+	}
+
+	ok := true
+	for _, fixture := range fixtures {
+		report, err := c.Check(fixture.Name, fixture.Document)
+		if err != nil {
+			t.Errorf("%v", err)
+			ok = false
+			continue
+		}
+		if !report.OK() {
+			t.Errorf("%s", report)
+			ok = false
+		}
+	}
+	return ok
+}
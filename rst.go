@@ -0,0 +1,526 @@
+package goeditorjs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// RSTEngine is the engine that creates reStructuredText from EditorJS blocks, e.g. for Sphinx
+// Handlers can be registered while the engine is generating.
+type RSTEngine struct {
+	BlockHandlers map[string]RSTBlockHandler
+	TuneHandlers  map[string]RSTTuneHandler
+	// Registry, if set, provides the handlers for block types that aren't in BlockHandlers
+	Registry *Registry
+	// UnknownBlockPolicy decides what happens to blocks without a registered handler. Defaults to UnknownBlockFail.
+	UnknownBlockPolicy UnknownBlockPolicy
+	// FallbackHandler generates reStructuredText for blocks without a registered handler when using UnknownBlockFallback
+	FallbackHandler RSTBlockHandler
+	// OnUnknownBlock, if set, is called with every block that doesn't have a registered handler
+	OnUnknownBlock func(editorJSBlock EditorJSBlock)
+	// ContinueOnError generates every block that can be generated and returns BlockErrors for the blocks that can't.
+	// Otherwise generation stops at the first block that fails with a *BlockError.
+	ContinueOnError bool
+
+	mu sync.RWMutex
+}
+
+// RSTBlockHandler is an interface for a plugable EditorJS reStructuredText generator
+type RSTBlockHandler interface {
+	Type() string // Type returns the type the block handler supports as a string
+	GenerateRST(editorJSBlock EditorJSBlock) (string, error)
+}
+
+// ContextRSTBlockHandler is an interface for a plugable EditorJS reStructuredText generator that is given the context of the generation.
+// The engine calls GenerateRSTContext instead of GenerateRST for handlers implementing it.
+type ContextRSTBlockHandler interface {
+	RSTBlockHandler
+	GenerateRSTContext(ctx context.Context, editorJSBlock EditorJSBlock) (string, error)
+}
+
+// RSTTuneHandler is an interface for a plugable EditorJS block tune reStructuredText generator.
+// It's given the reStructuredText generated for a block that has the tune and returns the tuned reStructuredText, or ErrSkipBlock to suppress the block.
+type RSTTuneHandler interface {
+	TuneHandler
+	TuneRST(tuneData json.RawMessage, editorJSBlock EditorJSBlock, rst string) (string, error)
+}
+
+// NewRSTEngine creates a new RSTEngine
+func NewRSTEngine() *RSTEngine {
+	bhs := make(map[string]RSTBlockHandler)
+	ths := make(map[string]RSTTuneHandler)
+	return &RSTEngine{BlockHandlers: bhs, TuneHandlers: ths}
+}
+
+// NewRSTEngineFromRegistry creates a new RSTEngine that uses the handlers of the registry
+func NewRSTEngineFromRegistry(registry *Registry) *RSTEngine {
+	rstEngine := NewRSTEngine()
+	rstEngine.Registry = registry
+	return rstEngine
+}
+
+// NewDefaultRSTEngine creates a new RSTEngine with all the built-in block handlers and tune handlers registered
+func NewDefaultRSTEngine() *RSTEngine {
+	rstEngine := NewRSTEngineFromRegistry(NewDefaultRegistry())
+	rstEngine.RegisterTuneHandlers(&HiddenTuneHandler{})
+	return rstEngine
+}
+
+// RegisterBlockHandlers registers or overrides a block handlers for blockType given by RSTBlockHandler.Type()
+func (rstEngine *RSTEngine) RegisterBlockHandlers(handlers ...RSTBlockHandler) {
+	rstEngine.mu.Lock()
+	defer rstEngine.mu.Unlock()
+	if rstEngine.BlockHandlers == nil {
+		rstEngine.BlockHandlers = make(map[string]RSTBlockHandler)
+	}
+	for _, bh := range handlers {
+		rstEngine.BlockHandlers[bh.Type()] = bh
+	}
+}
+
+// RegisterTuneHandlers registers or overrides tune handlers for the tune given by RSTTuneHandler.Type()
+func (rstEngine *RSTEngine) RegisterTuneHandlers(handlers ...RSTTuneHandler) {
+	rstEngine.mu.Lock()
+	defer rstEngine.mu.Unlock()
+	if rstEngine.TuneHandlers == nil {
+		rstEngine.TuneHandlers = make(map[string]RSTTuneHandler)
+	}
+	for _, th := range handlers {
+		rstEngine.TuneHandlers[th.Type()] = th
+	}
+}
+
+// GenerateRST generates reStructuredText from the editorJS using configured set of reStructuredText handlers
+func (rstEngine *RSTEngine) GenerateRST(editorJSData string) (string, error) {
+	document, err := ParseDocument(editorJSData)
+	if err != nil {
+		return "", err
+	}
+	return rstEngine.GenerateRSTFromDocument(document)
+}
+
+// GenerateRSTContext generates reStructuredText from the editorJS using configured set of reStructuredText handlers.
+// The context is passed to handlers implementing ContextRSTBlockHandler, and generation stops when the context is done.
+func (rstEngine *RSTEngine) GenerateRSTContext(ctx context.Context, editorJSData string) (string, error) {
+	document, err := ParseDocument(editorJSData)
+	if err != nil {
+		return "", err
+	}
+	return rstEngine.GenerateRSTFromDocumentContext(ctx, document)
+}
+
+// GenerateRSTFromDocument generates reStructuredText from a parsed Document using configured set of reStructuredText handlers
+func (rstEngine *RSTEngine) GenerateRSTFromDocument(document *Document) (string, error) {
+	result := &strings.Builder{}
+	err := rstEngine.RenderRSTFromDocument(result, document)
+	return result.String(), err
+}
+
+// GenerateRSTFromDocumentContext generates reStructuredText from a parsed Document using configured set of reStructuredText handlers.
+// The context is passed to handlers implementing ContextRSTBlockHandler, and generation stops when the context is done.
+func (rstEngine *RSTEngine) GenerateRSTFromDocumentContext(ctx context.Context, document *Document) (string, error) {
+	result := &strings.Builder{}
+	err := rstEngine.renderDocument(ctx, result, document)
+	return result.String(), err
+}
+
+// RenderRST reads editorJS data from r and writes the reStructuredText to w using configured set of reStructuredText handlers
+func (rstEngine *RSTEngine) RenderRST(w io.Writer, r io.Reader) error {
+	document, err := decodeDocument(r)
+	if err != nil {
+		return err
+	}
+	return rstEngine.RenderRSTFromDocument(w, document)
+}
+
+// RenderRSTContext reads editorJS data from r and writes the reStructuredText to w using configured set of reStructuredText handlers.
+// The context is passed to handlers implementing ContextRSTBlockHandler, and rendering stops when the context is done.
+func (rstEngine *RSTEngine) RenderRSTContext(ctx context.Context, w io.Writer, r io.Reader) error {
+	document, err := decodeDocument(r)
+	if err != nil {
+		return err
+	}
+	return rstEngine.RenderRSTFromDocumentContext(ctx, w, document)
+}
+
+// RenderRSTFromDocument writes the reStructuredText for a parsed Document to w using configured set of reStructuredText handlers
+func (rstEngine *RSTEngine) RenderRSTFromDocument(w io.Writer, document *Document) error {
+	return rstEngine.renderDocument(context.Background(), w, document)
+}
+
+// RenderRSTFromDocumentContext writes the reStructuredText for a parsed Document to w using configured set of reStructuredText handlers.
+// The context is passed to handlers implementing ContextRSTBlockHandler, and rendering stops when the context is done.
+func (rstEngine *RSTEngine) RenderRSTFromDocumentContext(ctx context.Context, w io.Writer, document *Document) error {
+	return rstEngine.renderDocument(ctx, w, document)
+}
+
+// renderDocument writes the reStructuredText for a parsed Document to w
+func (rstEngine *RSTEngine) renderDocument(ctx context.Context, w io.Writer, document *Document) error {
+	return writeBlocks(ctx, w, document.Blocks, "\n\n", rstEngine.ContinueOnError, rstEngine.writeBlock)
+}
+
+// writeBlock writes the reStructuredText for a single block to buf
+func (rstEngine *RSTEngine) writeBlock(ctx context.Context, buf *bytes.Buffer, block EditorJSBlock) (bool, error) {
+	generator, ok := rstEngine.blockHandler(block.Type)
+	if !ok {
+		switch unknownBlock(rstEngine.UnknownBlockPolicy, rstEngine.FallbackHandler != nil, rstEngine.OnUnknownBlock, block) {
+		case UnknownBlockSkip:
+			return false, nil
+		case UnknownBlockPlaceholder:
+			buf.WriteString(rstUnknownBlockPlaceholder(block))
+			return true, nil
+		case UnknownBlockFallback:
+			generator = rstEngine.FallbackHandler
+		default:
+			return false, errBlockHandlerNotFound(block)
+		}
+	}
+
+	var rst string
+	var err error
+	if contextGenerator, ok := generator.(ContextRSTBlockHandler); ok {
+		rst, err = contextGenerator.GenerateRSTContext(ctx, block)
+	} else {
+		rst, err = generator.GenerateRST(block)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if len(block.Tunes) > 0 {
+		rst, err = rstEngine.applyTunes(block, rst)
+		if errors.Is(err, ErrSkipBlock) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+	}
+
+	if rst == "" {
+		return false, nil
+	}
+
+	buf.WriteString(rst)
+	return true, nil
+}
+
+// blockHandler returns the handler for the block type from BlockHandlers, or from the Registry if it has one the engine can use
+func (rstEngine *RSTEngine) blockHandler(blockType string) (RSTBlockHandler, bool) {
+	rstEngine.mu.RLock()
+	generator, ok := rstEngine.BlockHandlers[blockType]
+	registry := rstEngine.Registry
+	rstEngine.mu.RUnlock()
+	if ok || registry == nil {
+		return generator, ok
+	}
+
+	if h, ok := registry.Lookup(blockType); ok {
+		generator, ok = h.(RSTBlockHandler)
+		return generator, ok
+	}
+	return nil, false
+}
+
+// tuneHandler returns the registered tune handler for the tune
+func (rstEngine *RSTEngine) tuneHandler(name string) (RSTTuneHandler, bool) {
+	rstEngine.mu.RLock()
+	defer rstEngine.mu.RUnlock()
+	tuneHandler, ok := rstEngine.TuneHandlers[name]
+	return tuneHandler, ok
+}
+
+// applyTunes applies the registered tune handlers for the tunes of the block, in order of tune name
+func (rstEngine *RSTEngine) applyTunes(block EditorJSBlock, rst string) (string, error) {
+	for _, name := range tuneNames(block) {
+		if tuneHandler, ok := rstEngine.tuneHandler(name); ok {
+			var err error
+			rst, err = tuneHandler.TuneRST(block.Tunes[name], block, rst)
+			if err != nil {
+				return "", err
+			}
+		}
+	}
+	return rst, nil
+}
+
+// rstUnknownBlockPlaceholder returns the reStructuredText comment used by UnknownBlockPlaceholder
+func rstUnknownBlockPlaceholder(editorJSBlock EditorJSBlock) string {
+	// The comment ends at the end of the line
+	blockType := strings.NewReplacer("\r", " ", "\n", " ").Replace(editorJSBlock.Type)
+	return fmt.Sprintf(`.. goeditorjs: no handler for block type "%s"`, blockType)
+}
+
+// rstDirective generates a directive with its options and indented content
+func rstDirective(name, argument string, options [][2]string, content string) string {
+	lines := []string{strings.TrimRight(fmt.Sprintf(".. %s:: %s", name, argument), " ")}
+	for _, option := range options {
+		lines = append(lines, strings.TrimRight(fmt.Sprintf("   :%s: %s", option[0], option[1]), " "))
+	}
+	if content != "" {
+		lines = append(lines, "", rstIndent(content, "   "))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// rstIndent indents every line of text that isn't blank
+func rstIndent(text, indent string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			lines[i] = ""
+		} else {
+			lines[i] = indent + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// rstURL returns the url as the argument of a directive, which mustn't contain whitespace
+func rstURL(url string) string {
+	return strings.NewReplacer(" ", "%20", "\t", "%09", "\n", "%0A").Replace(url)
+}
+
+// rstParagraph formats converted inline text as a paragraph, or as a line block if it has line breaks
+func rstParagraph(text string) string {
+	if !strings.Contains(text, "\n") {
+		return text
+	}
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight("| "+line, " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// rstText formats the inline html of a block as a single line of reStructuredText
+func rstText(htmlData string) string {
+	return strings.ReplaceAll(InlineHTMLToRST(htmlData), "\n", " ")
+}
+
+// rstWidth returns the number of columns text takes up, which section title adornments have to cover.
+// East Asian wide characters take up two columns and combining marks none.
+func rstWidth(text string) int {
+	width := 0
+	for _, r := range text {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+		case unicode.In(r, unicode.Han, unicode.Hangul, unicode.Hiragana, unicode.Katakana),
+			r >= 0xff01 && r <= 0xff60, r >= 0xffe0 && r <= 0xffe6:
+			width += 2
+		default:
+			width++
+		}
+	}
+	return width
+}
+
+// rstMarkupStart and rstMarkupEnd mark the start and end of inline markup while converting inline html.
+// Markup has to be separated from adjacent words, which is done with escaped spaces once the text around it is known.
+const (
+	rstMarkupStart = '\x00'
+	rstMarkupEnd   = '\x01'
+)
+
+// inlineRSTMarks are the reStructuredText start and end strings of the supported inline elements
+var inlineRSTMarks = map[string]string{
+	"b": "**", "strong": "**",
+	"i": "*", "em": "*",
+	"code": "``",
+}
+
+// inlineRSTFrame is an element being converted, its content is collected until the element is closed
+type inlineRSTFrame struct {
+	tag     string
+	href    string
+	content strings.Builder
+}
+
+// InlineHTMLToRST converts editor.js inline html to reStructuredText.
+// Bold, italic, links and inline code are converted, line breaks become new lines and other tags are removed, keeping their content.
+// reStructuredText doesn't support nested inline markup, so only the outermost element is converted.
+// Entities are decoded and characters that have a meaning in reStructuredText are escaped.
+func InlineHTMLToRST(htmlData string) string {
+	root := &inlineRSTFrame{}
+	stack := []*inlineRSTFrame{root}
+	atLineStart := true
+	dropped, droppedDepth := "", 0
+	for _, token := range tokenizeHTML(htmlData) {
+		current := stack[len(stack)-1]
+		if droppedDepth > 0 {
+			if token.Data == dropped && token.Type == htmlStartTagToken && !token.SelfClosing {
+				droppedDepth++
+			} else if token.Data == dropped && token.Type == htmlEndTagToken {
+				droppedDepth--
+			}
+			continue
+		}
+
+		switch token.Type {
+		case htmlTextToken:
+			text := html.UnescapeString(strings.NewReplacer(string(rstMarkupStart), "", string(rstMarkupEnd), "").Replace(token.Data))
+			text = htmlWhitespace.ReplaceAllString(text, " ")
+			if atLineStart {
+				text = strings.TrimLeft(text, " ")
+			}
+			if current.tag == "code" {
+				// Code is taken literally, it's escaped when the element is closed if it can't be an inline literal
+				current.content.WriteString(text)
+			} else {
+				current.content.WriteString(escapeRSTText(text, atLineStart && len(stack) == 1))
+			}
+			if text != "" {
+				atLineStart = false
+			}
+		case htmlStartTagToken:
+			switch {
+			case token.Data == "br":
+				current.content.WriteString("\n")
+				atLineStart = true
+			case len(stack) == 1 && (token.Data == "a" || inlineRSTMarks[token.Data] != ""):
+				if token.SelfClosing {
+					continue
+				}
+				frame := &inlineRSTFrame{tag: token.Data}
+				if token.Data == "a" {
+					href, _ := token.attribute("href")
+					frame.href = inlineURLPolicy.SanitizeURL(href)
+				}
+				stack = append(stack, frame)
+			case sanitizeDroppedElements[token.Data] && !token.SelfClosing && !htmlVoidElements[token.Data]:
+				dropped, droppedDepth = token.Data, 1
+			}
+		case htmlEndTagToken:
+			if len(stack) > 1 && stack[1].tag == token.Data {
+				stack = closeInlineRSTFrame(stack)
+			}
+		}
+	}
+
+	if len(stack) > 1 {
+		stack = closeInlineRSTFrame(stack)
+	}
+
+	rst := separateRSTMarkup(strings.TrimRight(stack[0].content.String(), " \n"))
+	if strings.HasSuffix(rst, "::") {
+		// A paragraph ending with "::" would make the next block a literal block
+		rst = strings.TrimSuffix(rst, ":") + "\\:"
+	}
+	return rst
+}
+
+// closeInlineRSTFrame writes the reStructuredText of the innermost frame to its parent and removes it from the stack
+func closeInlineRSTFrame(stack []*inlineRSTFrame) []*inlineRSTFrame {
+	frame, parent := stack[len(stack)-1], stack[len(stack)-2]
+	content := frame.content.String()
+
+	// Inline markup can't start or end with whitespace, so it's moved outside of it
+	trimmed := strings.TrimLeftFunc(content, unicode.IsSpace)
+	leading := content[:len(content)-len(trimmed)]
+	core := strings.TrimRightFunc(trimmed, unicode.IsSpace)
+	trailing := trimmed[len(core):]
+
+	markup := ""
+	switch {
+	case core == "":
+	case frame.tag == "code":
+		if strings.Contains(core, "``") || strings.HasSuffix(core, "`") || strings.Contains(core, "\n") {
+			// The code can't be an inline literal, so it's kept as text
+			content = escapeRSTText(content, false)
+		} else {
+			markup = "``" + core + "``"
+		}
+	case frame.tag == "a":
+		if frame.href != "" {
+			markup = fmt.Sprintf("`%s <%s>`__", strings.ReplaceAll(core, "<", "\\<"), rstLinkURL(frame.href))
+		}
+	default:
+		markup = inlineRSTMarks[frame.tag] + core + inlineRSTMarks[frame.tag]
+	}
+
+	if markup == "" || strings.Contains(core, "\n") {
+		parent.content.WriteString(content)
+	} else {
+		parent.content.WriteString(leading + string(rstMarkupStart) + markup + string(rstMarkupEnd) + trailing)
+	}
+
+	return stack[:len(stack)-1]
+}
+
+// rstLinkURL returns the url as the target of a hyperlink reference
+func rstLinkURL(url string) string {
+	return strings.NewReplacer(" ", "%20", "<", "%3C", ">", "%3E", "`", "%60").Replace(url)
+}
+
+// separateRSTMarkup replaces the markup markers with escaped spaces where the markup is next to a word,
+// since inline markup has to start after and end before whitespace or punctuation
+func separateRSTMarkup(rst string) string {
+	result := &strings.Builder{}
+	var previous rune
+	for i, r := range rst {
+		switch r {
+		case rstMarkupStart:
+			if previous != 0 && !isRSTMarkupBoundary(previous) {
+				result.WriteString("\\ ")
+			}
+		case rstMarkupEnd:
+			next, _ := utf8.DecodeRuneInString(rst[i+1:])
+			if next == rstMarkupStart || (next != utf8.RuneError && !isRSTMarkupBoundary(next)) {
+				result.WriteString("\\ ")
+			}
+		default:
+			result.WriteRune(r)
+			previous = r
+		}
+	}
+	return result.String()
+}
+
+// isRSTMarkupBoundary reports whether inline markup can start after or end before the character
+func isRSTMarkupBoundary(r rune) bool {
+	return unicode.IsSpace(r) || unicode.IsPunct(r)
+}
+
+// rstEnumeratedLine matches the start of a line that would be an enumerated list item, like "1. ", "a) " or "iv. "
+var rstEnumeratedLine = regexp.MustCompile(`^(?:\d+|[a-zA-Z]|[ivxlcdmIVXLCDM]+)[.)](?: |$)`)
+
+// escapeRSTText escapes the characters of text that have a meaning in reStructuredText.
+// Text that would start a block, like a list item, is escaped if atLineStart is set.
+func escapeRSTText(text string, atLineStart bool) string {
+	result := &strings.Builder{}
+	if atLineStart && text != "" {
+		if loc := rstEnumeratedLine.FindStringIndex(text); loc != nil {
+			end := strings.IndexAny(text, ".)")
+			result.WriteString(text[:end] + "\\")
+			text = text[end:]
+		} else if c := text[0]; c < utf8.RuneSelf && (unicode.IsPunct(rune(c)) || unicode.IsSymbol(rune(c))) {
+			if !strings.ContainsRune("\\*`_|", rune(c)) {
+				result.WriteByte('\\')
+			}
+		}
+	}
+
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case strings.IndexByte("\\*`|", c) >= 0:
+			result.WriteByte('\\')
+		case c == '_' && (i+1 == len(text) || !isASCIIAlphanumeric(text[i+1])):
+			// Underscores only make references at the end of words
+			result.WriteByte('\\')
+		}
+		result.WriteByte(c)
+	}
+	return result.String()
+}
@@ -0,0 +1,309 @@
+package goeditorjs_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/davidscottmills/goeditorjs"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_NewRSTEngine(t *testing.T) {
+	eng := goeditorjs.NewRSTEngine()
+	require.NotNil(t, eng.BlockHandlers)
+	require.NotNil(t, eng.TuneHandlers)
+}
+
+func Test_GenerateRST_Returns_Parse_Err(t *testing.T) {
+	eng := goeditorjs.NewRSTEngine()
+	_, err := eng.GenerateRST(``)
+	require.Error(t, err)
+}
+
+func Test_GenerateRST_NoHandler_Should_Err(t *testing.T) {
+	eng := goeditorjs.NewRSTEngine()
+	_, err := eng.GenerateRST(`{"blocks": [{"type": "header","data": {"text": "Heading","level": 1}}]}`)
+	require.True(t, errors.Is(err, goeditorjs.ErrBlockHandlerNotFound))
+}
+
+func Test_GenerateRST(t *testing.T) {
+	editorJSData := `{"blocks": [
+		{"type": "header","data": {"text": "The <b>Title</b>","level": 1}},
+		{"type": "header","data": {"text": "<b> </b>","level": 2}},
+		{"type": "paragraph","data": {"text": "Text","alignment": "left"}},
+		{"type": "list","data": {"style": "unordered","items": ["Apple","Pear"]}}
+	]}`
+
+	result, err := goeditorjs.NewDefaultRSTEngine().GenerateRST(editorJSData)
+	require.NoError(t, err)
+	require.Equal(t, "The **Title**\n=============\n\nText\n\n- Apple\n- Pear", result)
+}
+
+func Test_HeaderHandler_GenerateRST_Returns_Parse_Err(t *testing.T) {
+	h := &goeditorjs.HeaderHandler{}
+	_, err := h.GenerateRST(goeditorjs.EditorJSBlock{Type: "header", Data: []byte{}})
+	require.Error(t, err)
+}
+
+func Test_HeaderHandler_GenerateRST(t *testing.T) {
+	h := &goeditorjs.HeaderHandler{}
+	testData := []struct {
+		data           string
+		expectedResult string
+	}{
+		{data: `{"text": "The <b>Title</b>","level": 1}`, expectedResult: "The **Title**\n============="},
+		{data: `{"text": "日本語","level": 2}`, expectedResult: "日本語\n------"},
+		{data: `{"text": "Deep","level": 7}`, expectedResult: "Deep\n''''"},
+		{data: `{"text": "<b> </b>","level": 2}`, expectedResult: ""},
+	}
+
+	for _, td := range testData {
+		result, err := h.GenerateRST(goeditorjs.EditorJSBlock{Type: "header", Data: []byte(td.data)})
+		require.NoError(t, err)
+		require.Equal(t, td.expectedResult, result, td.data)
+	}
+}
+
+func Test_ParagraphHandler_GenerateRST_Returns_Parse_Err(t *testing.T) {
+	h := &goeditorjs.ParagraphHandler{}
+	_, err := h.GenerateRST(goeditorjs.EditorJSBlock{Type: "paragraph", Data: []byte{}})
+	require.Error(t, err)
+}
+
+func Test_ParagraphHandler_GenerateRST(t *testing.T) {
+	h := &goeditorjs.ParagraphHandler{}
+	testData := []struct {
+		data           string
+		expectedResult string
+	}{
+		{data: `{"text": "Fish &amp; chips&nbsp;<i>today</i><br>- Second   line","alignment": "center"}`,
+			expectedResult: "| Fish & chips\u00a0*today*\n| \\- Second line"},
+	}
+
+	for _, td := range testData {
+		result, err := h.GenerateRST(goeditorjs.EditorJSBlock{Type: "paragraph", Data: []byte(td.data)})
+		require.NoError(t, err)
+		require.Equal(t, td.expectedResult, result, td.data)
+	}
+}
+
+func Test_ListHandler_GenerateRST_Returns_Parse_Err(t *testing.T) {
+	h := &goeditorjs.ListHandler{}
+	_, err := h.GenerateRST(goeditorjs.EditorJSBlock{Type: "list", Data: []byte{}})
+	require.Error(t, err)
+}
+
+func Test_ListHandler_GenerateRST(t *testing.T) {
+	h := &goeditorjs.ListHandler{}
+	testData := []struct {
+		data           string
+		expectedResult string
+	}{
+		{data: `{"style": "unordered","items": ["Apple","Pear"]}`, expectedResult: "- Apple\n- Pear"},
+		{data: `{"style": "ordered","meta": {"start": 3,"counterType": "lower-roman"},"items": [{"content": "One","items": [{"content": "Nested","items": []}]},{"content": "Two","items": []}]}`,
+			expectedResult: "iii. One\n\n     i. Nested\n\niv. Two"},
+		{data: `{"style": "ordered","meta": {"counterType": "upper-alpha"},"items": [{"content": "One<br>two lines","items": []}]}`,
+			expectedResult: "A. | One\n   | two lines"},
+		{data: `{"style": "checklist","items": [{"content": "Done","meta": {"checked": true},"items": []}]}`, expectedResult: "- [x] Done"},
+	}
+
+	for _, td := range testData {
+		result, err := h.GenerateRST(goeditorjs.EditorJSBlock{Type: "list", Data: []byte(td.data)})
+		require.NoError(t, err)
+		require.Equal(t, td.expectedResult, result, td.data)
+	}
+}
+
+func Test_CodeBoxHandler_GenerateRST_Returns_Parse_Err(t *testing.T) {
+	h := &goeditorjs.CodeBoxHandler{}
+	_, err := h.GenerateRST(goeditorjs.EditorJSBlock{Type: "codeBox", Data: []byte{}})
+	require.Error(t, err)
+}
+
+func Test_CodeBoxHandler_GenerateRST(t *testing.T) {
+	h := &goeditorjs.CodeBoxHandler{}
+	testData := []struct {
+		data           string
+		expectedResult string
+	}{
+		{data: `{"language": "go","code": "<span>x</span> := 1<div><br></div><div>y &lt; x</div>"}`, expectedResult: ".. code-block:: go\n\n   x := 1\n\n   y < x"},
+		{data: `{"language": "c++\n   :x: y","code": "x"}`, expectedResult: ".. code-block:: c++xy\n\n   x"},
+		{data: `{"language": "go","code": "<div><br></div>"}`, expectedResult: ".. goeditorjs: empty code block"},
+	}
+
+	for _, td := range testData {
+		result, err := h.GenerateRST(goeditorjs.EditorJSBlock{Type: "codeBox", Data: []byte(td.data)})
+		require.NoError(t, err)
+		require.Equal(t, td.expectedResult, result, td.data)
+	}
+}
+
+func Test_RawHTMLHandler_GenerateRST_Returns_Parse_Err(t *testing.T) {
+	h := &goeditorjs.RawHTMLHandler{}
+	_, err := h.GenerateRST(goeditorjs.EditorJSBlock{Type: "raw", Data: []byte{}})
+	require.Error(t, err)
+}
+
+func Test_RawHTMLHandler_GenerateRST(t *testing.T) {
+	h := &goeditorjs.RawHTMLHandler{}
+	testData := []struct {
+		data           string
+		expectedResult string
+	}{
+		{data: `{"html": "<div>\n<p>Raw</p>\n</div>"}`, expectedResult: ".. raw:: html\n\n   <div>\n   <p>Raw</p>\n   </div>"},
+	}
+
+	for _, td := range testData {
+		result, err := h.GenerateRST(goeditorjs.EditorJSBlock{Type: "raw", Data: []byte(td.data)})
+		require.NoError(t, err)
+		require.Equal(t, td.expectedResult, result, td.data)
+	}
+}
+
+func Test_ImageHandler_GenerateRST_Returns_Parse_Err(t *testing.T) {
+	h := &goeditorjs.ImageHandler{}
+	_, err := h.GenerateRST(goeditorjs.EditorJSBlock{Type: "image", Data: []byte{}})
+	require.Error(t, err)
+}
+
+func Test_ImageHandler_GenerateRST(t *testing.T) {
+	h := &goeditorjs.ImageHandler{}
+	testData := []struct {
+		data           string
+		expectedResult string
+	}{
+		{data: `{"file": {"url": "https://example.com/a b.png"},"caption": "The <i>caption</i>","withBorder": true,"stretched": true}`,
+			expectedResult: ".. figure:: https://example.com/a%20b.png\n   :alt: The caption\n   :class: image-tool--stretched image-tool--withBorder\n\n   The *caption*"},
+		{data: `{"file": {"url": "https://example.com/a.png"},"caption": "","withBackground": true}`,
+			expectedResult: ".. image:: https://example.com/a.png\n   :class: image-tool--withBackground"},
+	}
+
+	for _, td := range testData {
+		result, err := h.GenerateRST(goeditorjs.EditorJSBlock{Type: "image", Data: []byte(td.data)})
+		require.NoError(t, err)
+		require.Equal(t, td.expectedResult, result, td.data)
+	}
+}
+
+func Test_ImageHandler_GenerateRST_Options(t *testing.T) {
+	handler := &goeditorjs.ImageHandler{Options: &goeditorjs.ImageHandlerOptions{StretchClass: "wide", BorderClass: "bordered", BackgroundClass: "filled"}}
+	block := goeditorjs.EditorJSBlock{Type: "image", Data: []byte(`{"file": {"url": "a.png"},"caption": "Caption","withBackground": true,"withBorder": true}`)}
+
+	result, err := handler.GenerateRST(block)
+	require.NoError(t, err)
+	require.Equal(t, ".. figure:: a.png\n   :alt: Caption\n   :class: bordered filled\n\n   Caption", result)
+
+	_, err = handler.GenerateRST(goeditorjs.EditorJSBlock{Type: "image", Data: []byte(`[]`)})
+	require.Error(t, err)
+}
+
+func Test_InlineHTMLToRST(t *testing.T) {
+	testData := []struct {
+		html           string
+		expectedResult string
+	}{
+		{html: "Plain text", expectedResult: "Plain text"},
+		{html: "<b>Bold</b> <strong>strong</strong> <i>italic</i> <em>em</em>", expectedResult: "**Bold** **strong** *italic* *em*"},
+		{html: "<b> spaced </b>word", expectedResult: "**spaced** word"},
+		{html: "in<b>side</b>word <b>a</b><i>b</i> (<i>c</i>)", expectedResult: "in\\ **side**\\ word **a**\\ *b* (*c*)"},
+		{html: "<b>Bold <i>and italic</i></b>", expectedResult: "**Bold and italic**"},
+		{html: "<code class=\"inline-code\">a*b</code> <code>``x</code>", expectedResult: "``a*b`` \\`\\`x"},
+		{html: `<a href="https://example.com/a b">A &lt;link&gt;</a> <a href="javascript:alert(1)">Bad</a>`,
+			expectedResult: "`A \\<link> <https://example.com/a%20b>`__ Bad"},
+		{html: "*not* `literal` |sub| name_ snake_case \\", expectedResult: "\\*not\\* \\`literal\\` \\|sub\\| name\\_ snake_case \\\\"},
+		{html: "1. Not a list<br>.. not a comment<br>iv) roman", expectedResult: "1\\. Not a list\n\\.. not a comment\niv\\) roman"},
+		{html: "Ends with::", expectedResult: "Ends with:\\:"},
+		{html: "Text<script>alert(1)</script><span>span</span><br><br>", expectedResult: "Textspan"},
+	}
+
+	for _, td := range testData {
+		require.Equal(t, td.expectedResult, goeditorjs.InlineHTMLToRST(td.html), td.html)
+	}
+}
+
+func Test_GenerateRST_UnknownBlockPolicy(t *testing.T) {
+	editorJSData := `{"blocks": [{"type": "unknown","data": {}},{"type": "paragraph","data": {"text": "Text","alignment": "left"}}]}`
+	eng := goeditorjs.NewDefaultRSTEngine()
+
+	eng.UnknownBlockPolicy = goeditorjs.UnknownBlockSkip
+	result, err := eng.GenerateRST(editorJSData)
+	require.NoError(t, err)
+	require.Equal(t, "Text", result)
+
+	eng.UnknownBlockPolicy = goeditorjs.UnknownBlockPlaceholder
+	result, err = eng.GenerateRST(editorJSData)
+	require.NoError(t, err)
+	require.Equal(t, ".. goeditorjs: no handler for block type \"unknown\"\n\nText", result)
+}
+
+func Test_NewDefaultRSTEngine(t *testing.T) {
+	editorJSData := `{"blocks": [
+		{"type": "paragraph","data": {"text": "Hidden","alignment": "left"},"tunes": {"hidden": true}},
+		{"type": "header","data": {"text": "Shown","level": 3}},
+		{"type": "table","data": {"content": [["A"]]}}
+	]}`
+	eng := goeditorjs.NewDefaultRSTEngine()
+
+	_, err := eng.GenerateRST(editorJSData)
+	require.True(t, errors.Is(err, goeditorjs.ErrBlockHandlerNotFound))
+
+	eng.UnknownBlockPolicy = goeditorjs.UnknownBlockSkip
+	result, err := eng.GenerateRST(editorJSData)
+	require.NoError(t, err)
+	require.Equal(t, "Shown\n~~~~~", result)
+}
+
+func Test_GenerateRST_ContinueOnError(t *testing.T) {
+	editorJSData := `{"blocks": [{"type": "paragraph","data": []},{"type": "paragraph","data": {"text": "Text","alignment": "left"}}]}`
+	eng := goeditorjs.NewDefaultRSTEngine()
+	eng.ContinueOnError = true
+
+	result, err := eng.GenerateRST(editorJSData)
+	var blockErrs goeditorjs.BlockErrors
+	require.True(t, errors.As(err, &blockErrs))
+	require.Len(t, blockErrs, 1)
+	require.Equal(t, 0, blockErrs[0].Index)
+	require.Equal(t, "Text", result)
+}
+
+func Test_RenderRST(t *testing.T) {
+	editorJSData := `{"blocks": [{"type": "header","data": {"text": "Title","level": 1}},{"type": "paragraph","data": {"text": "Text","alignment": "left"}}]}`
+	w := &bytes.Buffer{}
+	err := goeditorjs.NewDefaultRSTEngine().RenderRST(w, strings.NewReader(editorJSData))
+	require.NoError(t, err)
+	require.Equal(t, "Title\n=====\n\nText", w.String())
+
+	err = goeditorjs.NewDefaultRSTEngine().RenderRST(w, strings.NewReader(``))
+	require.Error(t, err)
+}
+
+func Test_GenerateRSTContext_Stops_When_Cancelled(t *testing.T) {
+	editorJSData := `{"blocks": [{"type": "paragraph","data": {"text": "Text","alignment": "left"}}]}`
+	document, err := goeditorjs.ParseDocument(editorJSData)
+	require.NoError(t, err)
+	eng := goeditorjs.NewDefaultRSTEngine()
+
+	result, err := eng.GenerateRSTFromDocumentContext(context.Background(), document)
+	require.NoError(t, err)
+	require.Equal(t, "Text", result)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	result, err = eng.GenerateRSTContext(ctx, editorJSData)
+	require.Equal(t, context.Canceled, err)
+	require.Equal(t, "", result)
+
+	result, err = eng.GenerateRSTFromDocumentContext(ctx, document)
+	require.Equal(t, context.Canceled, err)
+	require.Equal(t, "", result)
+
+	w := &bytes.Buffer{}
+	require.Equal(t, context.Canceled, eng.RenderRSTContext(ctx, w, strings.NewReader(editorJSData)))
+	require.Equal(t, context.Canceled, eng.RenderRSTFromDocumentContext(ctx, w, document))
+	require.Equal(t, "", w.String())
+	require.Error(t, eng.RenderRSTContext(ctx, w, strings.NewReader(``)))
+}
@@ -0,0 +1,227 @@
+package goeditorjs
+
+import (
+	"context"
+	"html"
+	"regexp"
+	"strings"
+)
+
+// SanitizePolicy is an allowlist of the html elements and attributes allowed in the inline content of blocks.
+// The methods of a nil *SanitizePolicy return their input unchanged, so a nil policy doesn't sanitize anything.
+type SanitizePolicy struct {
+	// Elements maps the names of the allowed elements to the names of their allowed attributes.
+	// Other elements are removed, keeping their content, except for elements like script whose content is removed too.
+	Elements map[string][]string
+	// URLSchemes are the schemes allowed in href and src attributes. Relative urls are always allowed.
+	URLSchemes []string
+}
+
+// NewSanitizePolicy creates a SanitizePolicy that allows the markup of the editor.js inline tools
+func NewSanitizePolicy() *SanitizePolicy {
+	return &SanitizePolicy{
+		Elements: map[string][]string{
+			"b":    nil,
+			"i":    nil,
+			"a":    {"href", "target", "rel"},
+			"mark": {"class"},
+			"code": {"class"},
+			"u":    {"class"},
+			"s":    nil,
+			"br":   nil,
+		},
+		URLSchemes: []string{"http", "https", "mailto", "tel"},
+	}
+}
+
+// AllowElement allows the element with the given attributes, in addition to any attributes already allowed for it.
+// It returns the policy so calls can be chained.
+func (p *SanitizePolicy) AllowElement(name string, attributes ...string) *SanitizePolicy {
+	if p.Elements == nil {
+		p.Elements = map[string][]string{}
+	}
+	name = strings.ToLower(name)
+	allowed := p.Elements[name]
+	for _, attribute := range attributes {
+		attribute = strings.ToLower(attribute)
+		if !containsString(allowed, attribute) {
+			allowed = append(allowed, attribute)
+		}
+	}
+	p.Elements[name] = allowed
+	return p
+}
+
+// sanitizeDroppedElements are removed together with their content
+var sanitizeDroppedElements = map[string]bool{
+	"script": true, "style": true, "iframe": true, "object": true, "embed": true, "noscript": true,
+	"template": true, "textarea": true, "title": true, "select": true, "svg": true, "math": true}
+
+// sanitizeURLAttributes are the attributes that hold urls
+var sanitizeURLAttributes = map[string]bool{"href": true, "src": true, "cite": true, "action": true, "formaction": true, "poster": true}
+
+// Sanitize removes the elements and attributes not allowed by the policy from an html fragment.
+// Urls with disallowed schemes are removed, attribute values are escaped and unclosed elements are closed.
+func (p *SanitizePolicy) Sanitize(htmlData string) string {
+	if p == nil {
+		return htmlData
+	}
+
+	result := &strings.Builder{}
+	open := []string{}
+	dropped, droppedDepth := "", 0
+	for _, token := range tokenizeHTML(htmlData) {
+		if droppedDepth > 0 {
+			if token.Data == dropped && token.Type == htmlStartTagToken && !token.SelfClosing {
+				droppedDepth++
+			} else if token.Data == dropped && token.Type == htmlEndTagToken {
+				droppedDepth--
+			}
+			continue
+		}
+
+		switch token.Type {
+		case htmlTextToken:
+			result.WriteString(escapeHTMLText(token.Data))
+		case htmlStartTagToken:
+			if sanitizeDroppedElements[token.Data] {
+				if !token.SelfClosing && !htmlVoidElements[token.Data] {
+					dropped, droppedDepth = token.Data, 1
+				}
+				continue
+			}
+
+			allowed, ok := p.Elements[token.Data]
+			if !ok {
+				continue
+			}
+
+			result.WriteString("<" + token.Data)
+			for _, attribute := range token.Attributes {
+				if !containsString(allowed, attribute.Name) {
+					continue
+				}
+				value := attribute.Value
+				if sanitizeURLAttributes[attribute.Name] {
+					if value = p.SanitizeURL(value); value == "" {
+						continue
+					}
+				}
+				result.WriteString(" " + attribute.Name + `="` + html.EscapeString(value) + `"`)
+			}
+			if token.SelfClosing {
+				result.WriteString("/")
+			}
+			result.WriteString(">")
+
+			if !token.SelfClosing && !htmlVoidElements[token.Data] {
+				open = append(open, token.Data)
+			}
+		case htmlEndTagToken:
+			i := len(open) - 1
+			for i >= 0 && open[i] != token.Data {
+				i--
+			}
+			if i < 0 {
+				continue
+			}
+			// Close the elements left open inside of the element as well, so the output stays well formed
+			for j := len(open) - 1; j >= i; j-- {
+				result.WriteString("</" + open[j] + ">")
+			}
+			open = open[:i]
+		}
+	}
+
+	for i := len(open) - 1; i >= 0; i-- {
+		result.WriteString("</" + open[i] + ">")
+	}
+
+	return result.String()
+}
+
+// SanitizeURL returns the url if its scheme is allowed by the policy or it's relative, otherwise it returns "".
+// Whitespace browsers ignore in urls, like tabs and new lines, is removed.
+func (p *SanitizePolicy) SanitizeURL(rawURL string) string {
+	if p == nil {
+		return rawURL
+	}
+
+	cleaned := strings.Map(func(r rune) rune {
+		if r == '\t' || r == '\n' || r == '\r' {
+			return -1
+		}
+		return r
+	}, strings.TrimFunc(rawURL, func(r rune) bool { return r <= ' ' }))
+
+	end := strings.IndexAny(cleaned, ":/?#")
+	if end < 0 || cleaned[end] != ':' {
+		return cleaned
+	}
+
+	scheme := strings.ToLower(cleaned[:end])
+	for _, allowed := range p.URLSchemes {
+		if strings.ToLower(allowed) == scheme {
+			return cleaned
+		}
+	}
+	return ""
+}
+
+// SanitizeAttribute returns the text of an html fragment, without any markup, escaped for use as an attribute value
+func (p *SanitizePolicy) SanitizeAttribute(value string) string {
+	if p == nil {
+		return value
+	}
+	return html.EscapeString(htmlText(value))
+}
+
+// htmlText returns the text of an html fragment with its entities decoded
+func htmlText(htmlData string) string {
+	text := &strings.Builder{}
+	for _, token := range tokenizeHTML(htmlData) {
+		if token.Type == htmlTextToken {
+			text.WriteString(html.UnescapeString(token.Data))
+		}
+	}
+	return text.String()
+}
+
+// htmlEntity matches a character reference at the start of a string
+var htmlEntity = regexp.MustCompile(`^&(#[0-9]+|#[xX][0-9a-fA-F]+|[a-zA-Z][a-zA-Z0-9]*);`)
+
+// escapeHTMLText escapes the markup characters of html text, leaving existing character references as they are
+func escapeHTMLText(text string) string {
+	result := &strings.Builder{}
+	for i := 0; i < len(text); i++ {
+		switch text[i] {
+		case '<':
+			result.WriteString("&lt;")
+		case '>':
+			result.WriteString("&gt;")
+		case '&':
+			if htmlEntity.MatchString(text[i:]) {
+				result.WriteByte('&')
+			} else {
+				result.WriteString("&amp;")
+			}
+		default:
+			result.WriteByte(text[i])
+		}
+	}
+	return result.String()
+}
+
+// sanitizePolicyContextKey is the context key of the SanitizePolicy
+type sanitizePolicyContextKey struct{}
+
+// ContextWithSanitizePolicy returns a copy of ctx that carries the SanitizePolicy used by the built-in handlers
+func ContextWithSanitizePolicy(ctx context.Context, policy *SanitizePolicy) context.Context {
+	return context.WithValue(ctx, sanitizePolicyContextKey{}, policy)
+}
+
+// SanitizePolicyFromContext returns the SanitizePolicy carried by ctx, or nil if there is none
+func SanitizePolicyFromContext(ctx context.Context) *SanitizePolicy {
+	policy, _ := ctx.Value(sanitizePolicyContextKey{}).(*SanitizePolicy)
+	return policy
+}
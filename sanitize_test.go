@@ -0,0 +1,178 @@
+package goeditorjs_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/davidscottmills/goeditorjs"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_SanitizePolicy_Sanitize(t *testing.T) {
+	testData := []struct {
+		data           string
+		expectedResult string
+	}{
+		{data: `Plain text`, expectedResult: `Plain text`},
+		{data: `<b>Bold</b> <i>italic</i> <s>struck</s>`, expectedResult: `<b>Bold</b> <i>italic</i> <s>struck</s>`},
+		{data: `<mark class="cdx-marker">marked</mark> <code class="inline-code">code</code> <u class="cdx-underline">under</u>`,
+			expectedResult: `<mark class="cdx-marker">marked</mark> <code class="inline-code">code</code> <u class="cdx-underline">under</u>`},
+		{data: `Line<br>break<br/>`, expectedResult: `Line<br>break<br/>`},
+		{data: `<a href="https://example.com" target="_blank" rel="nofollow" onclick="alert(1)">link</a>`,
+			expectedResult: `<a href="https://example.com" target="_blank" rel="nofollow">link</a>`},
+		{data: `<a href="javascript:alert(1)">link</a>`, expectedResult: `<a>link</a>`},
+		{data: `<a href=" java&#x09;script:alert(1)">link</a>`, expectedResult: `<a>link</a>`},
+		{data: `<a href="/relative?a=1&amp;b=2">link</a>`, expectedResult: `<a href="/relative?a=1&amp;b=2">link</a>`},
+		{data: `<a href='mailto:a@example.com'>mail</a>`, expectedResult: `<a href="mailto:a@example.com">mail</a>`},
+		{data: `<b class="x" style="color:red">Bold</b>`, expectedResult: `<b>Bold</b>`},
+		{data: `<div><span>Kept text</span></div>`, expectedResult: `Kept text`},
+		{data: `Before<script>alert("<b>x</b>")</script>After`, expectedResult: `BeforeAfter`},
+		{data: `<iframe src="https://example.com"><b>x</b></iframe>After`, expectedResult: `After`},
+		{data: `<img src=x onerror=alert(1)>Text`, expectedResult: `Text`},
+		{data: `<!-- comment -->Text`, expectedResult: `Text`},
+		{data: `<b>Unclosed <i>tags`, expectedResult: `<b>Unclosed <i>tags</i></b>`},
+		{data: `<b><i>Misnested</b></i>`, expectedResult: `<b><i>Misnested</i></b>`},
+		{data: `Stray</b> end tag`, expectedResult: `Stray end tag`},
+		{data: `1 < 2 & 3 > 2 &amp; &nbsp;`, expectedResult: `1 &lt; 2 &amp; 3 &gt; 2 &amp; &nbsp;`},
+		{data: `<b title="x>y">Text</b>`, expectedResult: `<b>Text</b>`},
+		{data: `Unfinished <b`, expectedResult: `Unfinished `},
+		{data: `<A HREF="HTTPS://example.com">Upper</A>`, expectedResult: `<a href="HTTPS://example.com">Upper</a>`},
+	}
+
+	policy := goeditorjs.NewSanitizePolicy()
+	for _, td := range testData {
+		require.Equal(t, td.expectedResult, policy.Sanitize(td.data), td.data)
+	}
+}
+
+func Test_SanitizePolicy_Escapes_Attributes(t *testing.T) {
+	policy := goeditorjs.NewSanitizePolicy()
+	result := policy.Sanitize(`<mark class='a"><script>alert(1)</script>'>Text</mark>`)
+	require.Equal(t, `<mark class="a&#34;&gt;&lt;script&gt;alert(1)&lt;/script&gt;">Text</mark>`, result)
+}
+
+func Test_SanitizePolicy_AllowElement(t *testing.T) {
+	policy := goeditorjs.NewSanitizePolicy().AllowElement("span", "class").AllowElement("a", "title")
+	result := policy.Sanitize(`<span class="x" id="y">Text</span><a href="/" title="t">Link</a>`)
+	require.Equal(t, `<span class="x">Text</span><a href="/" title="t">Link</a>`, result)
+
+	policy = (&goeditorjs.SanitizePolicy{}).AllowElement("em")
+	require.Equal(t, `<em>Text</em>`, policy.Sanitize(`<em>Text</em><b>`))
+}
+
+func Test_SanitizePolicy_SanitizeURL(t *testing.T) {
+	testData := []struct {
+		data           string
+		expectedResult string
+	}{
+		{data: `https://example.com/a?b=c#d`, expectedResult: `https://example.com/a?b=c#d`},
+		{data: `  http://example.com  `, expectedResult: `http://example.com`},
+		{data: `/path/to/image.png`, expectedResult: `/path/to/image.png`},
+		{data: `image.png?at=12:00`, expectedResult: `image.png?at=12:00`},
+		{data: `JavaScript:alert(1)`, expectedResult: ``},
+		{data: "java\nscript:alert(1)", expectedResult: ``},
+		{data: `data:text/html;base64,PHNjcmlwdD4=`, expectedResult: ``},
+		{data: `vbscript:msgbox`, expectedResult: ``},
+	}
+
+	policy := goeditorjs.NewSanitizePolicy()
+	for _, td := range testData {
+		require.Equal(t, td.expectedResult, policy.SanitizeURL(td.data), td.data)
+	}
+}
+
+func Test_SanitizePolicy_SanitizeAttribute(t *testing.T) {
+	policy := goeditorjs.NewSanitizePolicy()
+	require.Equal(t, `Caption &#34;quoted&#34; &amp; bold`, policy.SanitizeAttribute(`Caption "quoted" &amp; <b>bold</b>`))
+}
+
+func Test_SanitizePolicy_Nil_Does_Not_Sanitize(t *testing.T) {
+	var policy *goeditorjs.SanitizePolicy
+	require.Equal(t, `<script>x</script>`, policy.Sanitize(`<script>x</script>`))
+	require.Equal(t, `javascript:x`, policy.SanitizeURL(`javascript:x`))
+	require.Equal(t, `"<b>"`, policy.SanitizeAttribute(`"<b>"`))
+}
+
+func Test_SanitizePolicyFromContext(t *testing.T) {
+	require.Nil(t, goeditorjs.SanitizePolicyFromContext(context.Background()))
+
+	policy := goeditorjs.NewSanitizePolicy()
+	ctx := goeditorjs.ContextWithSanitizePolicy(context.Background(), policy)
+	require.Equal(t, policy, goeditorjs.SanitizePolicyFromContext(ctx))
+}
+
+func Test_HTMLEngine_SanitizePolicy(t *testing.T) {
+	editorJSData := `{"blocks": [
+		{"type": "header","data": {"text": "Title<script>alert(1)</script>","level": 1}},
+		{"type": "paragraph","data": {"text": "<b onmouseover=\"alert(1)\">Text</b>","alignment": "\"><script>"}},
+		{"type": "list","data": {"style": "unordered","items": ["<a href=\"javascript:alert(1)\">Item</a>"]}},
+		{"type": "image","data": {"file": {"url": "javascript:alert(1)"},"caption": "A \"caption\" <b>here</b>"}},
+		{"type": "raw","data": {"html": "<div onclick=\"alert(1)\"><i>Raw</i></div>"}},
+		{"type": "table","data": {"content": [["<img src=x onerror=alert(1)>Cell"]]}},
+		{"type": "quote","data": {"text": "<i>Quote</i><style>*{}</style>","caption": "<u>Author</u>"}},
+		{"type": "codeBox","data": {"language": "go","code": "<div>a &lt; b</div><div><span onclick=\"x()\">c</span></div>"}}
+	]}`
+
+	eng := goeditorjs.NewHTMLEngine()
+	eng.RegisterBlockHandlers(&goeditorjs.HeaderHandler{}, &goeditorjs.ParagraphHandler{}, &goeditorjs.ListHandler{},
+		&goeditorjs.ImageHandler{}, &goeditorjs.RawHTMLHandler{}, &goeditorjs.TableHandler{}, &goeditorjs.QuoteHandler{}, &goeditorjs.CodeBoxHandler{})
+	eng.SanitizePolicy = goeditorjs.NewSanitizePolicy()
+
+	result, err := eng.GenerateHTML(editorJSData)
+	require.NoError(t, err)
+	require.Equal(t, `<h1>Title</h1>`+
+		`<p style="text-align:&#34;&gt;">`+`<b>Text</b></p>`+
+		`<ul><li><a>Item</a></li></ul>`+
+		`<img src="" alt="A &#34;caption&#34; here" />`+
+		`<i>Raw</i>`+
+		`<table><tbody><tr><td>Cell</td></tr></tbody></table>`+
+		`<figure><blockquote><i>Quote</i></blockquote><figcaption><cite><u>Author</u></cite></figcaption></figure>`+
+		"<pre><code class=\"go\">a &lt; b\nc</code></pre>", result)
+}
+
+func Test_HTMLEngine_SanitizePolicy_UnknownBlockPlaceholder(t *testing.T) {
+	editorJSData := `{"blocks": [{"type": "x--><script>alert(1)</script><!--","data": {}}]}`
+	eng := goeditorjs.NewHTMLEngine()
+	eng.UnknownBlockPolicy = goeditorjs.UnknownBlockPlaceholder
+	eng.SanitizePolicy = goeditorjs.NewSanitizePolicy()
+
+	result, err := eng.GenerateHTML(editorJSData)
+	require.NoError(t, err)
+	require.Equal(t, `<!-- goeditorjs: no handler for block type "x-__script_alert_1___script___-" -->`, result)
+}
+
+func Test_HTMLEngine_BlockSanitizePolicies(t *testing.T) {
+	editorJSData := `{"blocks": [
+		{"type": "paragraph","data": {"text": "<span class=\"x\">Text</span>","alignment": "left"}},
+		{"type": "raw","data": {"html": "<div class=\"embed\">Raw</div>"}}
+	]}`
+
+	eng := goeditorjs.NewHTMLEngine()
+	eng.RegisterBlockHandlers(&goeditorjs.ParagraphHandler{}, &goeditorjs.RawHTMLHandler{})
+	eng.SanitizePolicy = goeditorjs.NewSanitizePolicy()
+	eng.BlockSanitizePolicies = map[string]*goeditorjs.SanitizePolicy{"raw": nil}
+
+	result, err := eng.GenerateHTML(editorJSData)
+	require.NoError(t, err)
+	require.Equal(t, `<p>Text</p><div class="embed">Raw</div>`, result)
+
+	eng.BlockSanitizePolicies = map[string]*goeditorjs.SanitizePolicy{"paragraph": goeditorjs.NewSanitizePolicy().AllowElement("span", "class")}
+	result, err = eng.GenerateHTML(editorJSData)
+	require.NoError(t, err)
+	require.Equal(t, `<p><span class="x">Text</span></p>Raw`, result)
+}
+
+func Test_HTMLEngine_SanitizePolicy_Off_By_Default(t *testing.T) {
+	editorJSData := `{"blocks": [{"type": "raw","data": {"html": "<div onclick=\"go()\">Raw</div>"}}]}`
+	eng := goeditorjs.NewHTMLEngine()
+	eng.RegisterBlockWriters(goeditorjs.NewHTMLBlockWriter(&goeditorjs.RawHTMLHandler{}))
+
+	result, err := eng.GenerateHTML(editorJSData)
+	require.NoError(t, err)
+	require.Equal(t, `<div onclick="go()">Raw</div>`, result)
+
+	eng.SanitizePolicy = goeditorjs.NewSanitizePolicy()
+	result, err = eng.GenerateHTML(editorJSData)
+	require.NoError(t, err)
+	require.Equal(t, `Raw`, result)
+}
@@ -0,0 +1,378 @@
+package goeditorjs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// TextEngine is the engine that creates plain text from EditorJS blocks, e.g. for search indexing or previews
+// Handlers can be registered while the engine is generating.
+type TextEngine struct {
+	BlockHandlers map[string]TextBlockHandler
+	TuneHandlers  map[string]TextTuneHandler
+	// Registry, if set, provides the handlers for block types that aren't in BlockHandlers
+	Registry *Registry
+	// UnknownBlockPolicy decides what happens to blocks without a registered handler. Defaults to UnknownBlockFail.
+	UnknownBlockPolicy UnknownBlockPolicy
+	// FallbackHandler generates text for blocks without a registered handler when using UnknownBlockFallback
+	FallbackHandler TextBlockHandler
+	// OnUnknownBlock, if set, is called with every block that doesn't have a registered handler
+	OnUnknownBlock func(editorJSBlock EditorJSBlock)
+	// ContinueOnError generates every block that can be generated and returns BlockErrors for the blocks that can't.
+	// Otherwise generation stops at the first block that fails with a *BlockError.
+	ContinueOnError bool
+	// LineWidth wraps the lines of the text at the given number of characters. Lines aren't wrapped if it's 0.
+	LineWidth int
+	// PreformattedTypes are the block types whose text isn't wrapped. If nil, DefaultTextPreformattedTypes are used.
+	PreformattedTypes []string
+
+	mu sync.RWMutex
+}
+
+// DefaultTextPreformattedTypes are the block types whose text isn't wrapped by default
+var DefaultTextPreformattedTypes = []string{"code", "codeBox", "table"}
+
+// TextBlockHandler is an interface for a plugable EditorJS plain text generator
+type TextBlockHandler interface {
+	Type() string // Type returns the type the block handler supports as a string
+	GenerateText(editorJSBlock EditorJSBlock) (string, error)
+}
+
+// ContextTextBlockHandler is an interface for a plugable EditorJS plain text generator that is given the context of the generation.
+// The engine calls GenerateTextContext instead of GenerateText for handlers implementing it.
+type ContextTextBlockHandler interface {
+	TextBlockHandler
+	GenerateTextContext(ctx context.Context, editorJSBlock EditorJSBlock) (string, error)
+}
+
+// TextTuneHandler is an interface for a plugable EditorJS block tune plain text generator.
+// It's given the text generated for a block that has the tune and returns the tuned text, or ErrSkipBlock to suppress the block.
+type TextTuneHandler interface {
+	TuneHandler
+	TuneText(tuneData json.RawMessage, editorJSBlock EditorJSBlock, text string) (string, error)
+}
+
+// NewTextEngine creates a new TextEngine
+func NewTextEngine() *TextEngine {
+	bhs := make(map[string]TextBlockHandler)
+	ths := make(map[string]TextTuneHandler)
+	return &TextEngine{BlockHandlers: bhs, TuneHandlers: ths}
+}
+
+// NewTextEngineFromRegistry creates a new TextEngine that uses the handlers of the registry
+func NewTextEngineFromRegistry(registry *Registry) *TextEngine {
+	textEngine := NewTextEngine()
+	textEngine.Registry = registry
+	return textEngine
+}
+
+// NewDefaultTextEngine creates a new TextEngine with all the built-in block handlers and tune handlers registered
+func NewDefaultTextEngine() *TextEngine {
+	textEngine := NewTextEngineFromRegistry(NewDefaultRegistry())
+	textEngine.RegisterTuneHandlers(&HiddenTuneHandler{})
+	return textEngine
+}
+
+// RegisterBlockHandlers registers or overrides a block handlers for blockType given by TextBlockHandler.Type()
+func (textEngine *TextEngine) RegisterBlockHandlers(handlers ...TextBlockHandler) {
+	textEngine.mu.Lock()
+	defer textEngine.mu.Unlock()
+	if textEngine.BlockHandlers == nil {
+		textEngine.BlockHandlers = make(map[string]TextBlockHandler)
+	}
+	for _, bh := range handlers {
+		textEngine.BlockHandlers[bh.Type()] = bh
+	}
+}
+
+// RegisterTuneHandlers registers or overrides tune handlers for the tune given by TextTuneHandler.Type()
+func (textEngine *TextEngine) RegisterTuneHandlers(handlers ...TextTuneHandler) {
+	textEngine.mu.Lock()
+	defer textEngine.mu.Unlock()
+	if textEngine.TuneHandlers == nil {
+		textEngine.TuneHandlers = make(map[string]TextTuneHandler)
+	}
+	for _, th := range handlers {
+		textEngine.TuneHandlers[th.Type()] = th
+	}
+}
+
+// GenerateText generates plain text from the editorJS using configured set of text handlers
+func (textEngine *TextEngine) GenerateText(editorJSData string) (string, error) {
+	document, err := ParseDocument(editorJSData)
+	if err != nil {
+		return "", err
+	}
+	return textEngine.GenerateTextFromDocument(document)
+}
+
+// GenerateTextContext generates plain text from the editorJS using configured set of text handlers.
+// The context is passed to handlers implementing ContextTextBlockHandler, and generation stops when the context is done.
+func (textEngine *TextEngine) GenerateTextContext(ctx context.Context, editorJSData string) (string, error) {
+	document, err := ParseDocument(editorJSData)
+	if err != nil {
+		return "", err
+	}
+	return textEngine.GenerateTextFromDocumentContext(ctx, document)
+}
+
+// GenerateTextFromDocument generates plain text from a parsed Document using configured set of text handlers
+func (textEngine *TextEngine) GenerateTextFromDocument(document *Document) (string, error) {
+	result := &strings.Builder{}
+	err := textEngine.RenderTextFromDocument(result, document)
+	return result.String(), err
+}
+
+// GenerateTextFromDocumentContext generates plain text from a parsed Document using configured set of text handlers.
+// The context is passed to handlers implementing ContextTextBlockHandler, and generation stops when the context is done.
+func (textEngine *TextEngine) GenerateTextFromDocumentContext(ctx context.Context, document *Document) (string, error) {
+	result := &strings.Builder{}
+	err := textEngine.renderDocument(ctx, result, document)
+	return result.String(), err
+}
+
+// RenderText reads editorJS data from r and writes the plain text to w using configured set of text handlers
+func (textEngine *TextEngine) RenderText(w io.Writer, r io.Reader) error {
+	document, err := decodeDocument(r)
+	if err != nil {
+		return err
+	}
+	return textEngine.RenderTextFromDocument(w, document)
+}
+
+// RenderTextContext reads editorJS data from r and writes the plain text to w using configured set of text handlers.
+// The context is passed to handlers implementing ContextTextBlockHandler, and rendering stops when the context is done.
+func (textEngine *TextEngine) RenderTextContext(ctx context.Context, w io.Writer, r io.Reader) error {
+	document, err := decodeDocument(r)
+	if err != nil {
+		return err
+	}
+	return textEngine.RenderTextFromDocumentContext(ctx, w, document)
+}
+
+// RenderTextFromDocument writes the plain text for a parsed Document to w using configured set of text handlers
+func (textEngine *TextEngine) RenderTextFromDocument(w io.Writer, document *Document) error {
+	return textEngine.renderDocument(context.Background(), w, document)
+}
+
+// RenderTextFromDocumentContext writes the plain text for a parsed Document to w using configured set of text handlers.
+// The context is passed to handlers implementing ContextTextBlockHandler, and rendering stops when the context is done.
+func (textEngine *TextEngine) RenderTextFromDocumentContext(ctx context.Context, w io.Writer, document *Document) error {
+	return textEngine.renderDocument(ctx, w, document)
+}
+
+// renderDocument writes the plain text for a parsed Document to w
+func (textEngine *TextEngine) renderDocument(ctx context.Context, w io.Writer, document *Document) error {
+	return writeBlocks(ctx, w, document.Blocks, "\n\n", textEngine.ContinueOnError, textEngine.writeBlock)
+}
+
+// writeBlock writes the plain text for a single block to buf. Blocks without any text are left out.
+func (textEngine *TextEngine) writeBlock(ctx context.Context, buf *bytes.Buffer, block EditorJSBlock) (bool, error) {
+	generator, ok := textEngine.blockHandler(block.Type)
+	if !ok {
+		switch unknownBlock(textEngine.UnknownBlockPolicy, textEngine.FallbackHandler != nil, textEngine.OnUnknownBlock, block) {
+		case UnknownBlockSkip:
+			return false, nil
+		case UnknownBlockPlaceholder:
+			buf.WriteString(fmt.Sprintf("[goeditorjs: no handler for block type %q]", block.Type))
+			return true, nil
+		case UnknownBlockFallback:
+			generator = textEngine.FallbackHandler
+		default:
+			return false, errBlockHandlerNotFound(block)
+		}
+	}
+
+	var text string
+	var err error
+	if contextGenerator, ok := generator.(ContextTextBlockHandler); ok {
+		text, err = contextGenerator.GenerateTextContext(ctx, block)
+	} else {
+		text, err = generator.GenerateText(block)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if len(block.Tunes) > 0 {
+		text, err = textEngine.applyTunes(block, text)
+		if errors.Is(err, ErrSkipBlock) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+	}
+
+	if text == "" {
+		return false, nil
+	}
+
+	preformattedTypes := textEngine.PreformattedTypes
+	if preformattedTypes == nil {
+		preformattedTypes = DefaultTextPreformattedTypes
+	}
+	if textEngine.LineWidth > 0 && !containsString(preformattedTypes, block.Type) {
+		text = wrapText(text, textEngine.LineWidth)
+	}
+
+	buf.WriteString(text)
+	return true, nil
+}
+
+// blockHandler returns the handler for the block type from BlockHandlers, or from the Registry if it has one the engine can use
+func (textEngine *TextEngine) blockHandler(blockType string) (TextBlockHandler, bool) {
+	textEngine.mu.RLock()
+	generator, ok := textEngine.BlockHandlers[blockType]
+	registry := textEngine.Registry
+	textEngine.mu.RUnlock()
+	if ok || registry == nil {
+		return generator, ok
+	}
+
+	if h, ok := registry.Lookup(blockType); ok {
+		generator, ok = h.(TextBlockHandler)
+		return generator, ok
+	}
+	return nil, false
+}
+
+// tuneHandler returns the registered tune handler for the tune
+func (textEngine *TextEngine) tuneHandler(name string) (TextTuneHandler, bool) {
+	textEngine.mu.RLock()
+	defer textEngine.mu.RUnlock()
+	tuneHandler, ok := textEngine.TuneHandlers[name]
+	return tuneHandler, ok
+}
+
+// applyTunes applies the registered tune handlers for the tunes of the block, in order of tune name
+func (textEngine *TextEngine) applyTunes(block EditorJSBlock, text string) (string, error) {
+	for _, name := range tuneNames(block) {
+		if tuneHandler, ok := textEngine.tuneHandler(name); ok {
+			var err error
+			text, err = tuneHandler.TuneText(block.Tunes[name], block, text)
+			if err != nil {
+				return "", err
+			}
+		}
+	}
+	return text, nil
+}
+
+// textBlockElements start a new line in the text of html
+var textBlockElements = map[string]bool{
+	"address": true, "article": true, "aside": true, "blockquote": true, "dd": true, "div": true, "dl": true, "dt": true,
+	"figcaption": true, "figure": true, "footer": true, "h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"header": true, "hr": true, "li": true, "ol": true, "p": true, "pre": true, "section": true, "table": true, "tr": true, "ul": true}
+
+// htmlWhitespace matches runs of whitespace that html collapses into a single space
+var htmlWhitespace = regexp.MustCompile(`[ \t\n\r\f]+`)
+
+// htmlToText returns the plain text of an html fragment. Tags are removed, entities decoded and line breaks and
+// block elements start new lines. Unless preformatted is set, whitespace is collapsed the way browsers do.
+func htmlToText(htmlData string, preformatted bool) string {
+	text := &strings.Builder{}
+	newLine := func() {
+		if text.Len() > 0 && !strings.HasSuffix(text.String(), "\n") {
+			text.WriteString("\n")
+		}
+	}
+
+	dropped, droppedDepth := "", 0
+	for _, token := range tokenizeHTML(htmlData) {
+		if droppedDepth > 0 {
+			if token.Data == dropped && token.Type == htmlStartTagToken && !token.SelfClosing {
+				droppedDepth++
+			} else if token.Data == dropped && token.Type == htmlEndTagToken {
+				droppedDepth--
+			}
+			continue
+		}
+
+		switch token.Type {
+		case htmlTextToken:
+			data := html.UnescapeString(token.Data)
+			if !preformatted {
+				// Non-breaking spaces only matter for layout, so they're plain spaces in text
+				data = htmlWhitespace.ReplaceAllString(strings.ReplaceAll(data, "\u00a0", " "), " ")
+			}
+			text.WriteString(data)
+		case htmlStartTagToken:
+			switch {
+			case token.Data == "br":
+				text.WriteString("\n")
+			case sanitizeDroppedElements[token.Data]:
+				if !token.SelfClosing && !htmlVoidElements[token.Data] {
+					dropped, droppedDepth = token.Data, 1
+				}
+			case textBlockElements[token.Data]:
+				newLine()
+			}
+		case htmlEndTagToken:
+			if textBlockElements[token.Data] {
+				newLine()
+			}
+		}
+	}
+
+	if preformatted {
+		return strings.TrimRight(text.String(), "\n")
+	}
+
+	lines := strings.Split(text.String(), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// textListMarker matches the list markers generated for text, which continuation lines are indented past
+var textListMarker = regexp.MustCompile(`^\s*(- |\d+\. |\[[ x]\] )(\[[ x]\] )?`)
+
+// wrapText wraps the lines of text at width characters, breaking them between words.
+// Continuation lines keep the indentation of their line, and are indented past list markers.
+func wrapText(text string, width int) string {
+	lines := strings.Split(text, "\n")
+	results := []string{}
+	for _, line := range lines {
+		if utf8.RuneCountInString(line) <= width {
+			results = append(results, line)
+			continue
+		}
+
+		prefix := line[:len(line)-len(strings.TrimLeft(line, " "))]
+		if marker := textListMarker.FindString(line); marker != "" {
+			prefix = marker
+		}
+		indent := strings.Repeat(" ", utf8.RuneCountInString(prefix))
+
+		current := prefix
+		currentLength := utf8.RuneCountInString(prefix)
+		empty := true
+		for _, word := range strings.Fields(line[len(prefix):]) {
+			wordLength := utf8.RuneCountInString(word)
+			if !empty && currentLength+1+wordLength > width {
+				results = append(results, current)
+				current, currentLength, empty = indent, len(indent), true
+			}
+			if !empty {
+				current += " "
+				currentLength++
+			}
+			current += word
+			currentLength += wordLength
+			empty = false
+		}
+		results = append(results, current)
+	}
+	return strings.Join(results, "\n")
+}
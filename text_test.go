@@ -0,0 +1,182 @@
+package goeditorjs_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/davidscottmills/goeditorjs"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTextEngine() *goeditorjs.TextEngine {
+	eng := goeditorjs.NewTextEngine()
+	eng.RegisterBlockHandlers(&goeditorjs.HeaderHandler{}, &goeditorjs.ParagraphHandler{}, &goeditorjs.ListHandler{},
+		&goeditorjs.CodeBoxHandler{}, &goeditorjs.RawHTMLHandler{}, &goeditorjs.ImageHandler{}, &goeditorjs.TableHandler{},
+		&goeditorjs.ChecklistHandler{}, &goeditorjs.QuoteHandler{}, &goeditorjs.WarningHandler{}, &goeditorjs.EmbedHandler{},
+		&goeditorjs.LinkToolHandler{}, &goeditorjs.AttachesHandler{}, &goeditorjs.DelimiterHandler{}, &goeditorjs.CodeHandler{})
+	return eng
+}
+
+func Test_NewTextEngine(t *testing.T) {
+	eng := goeditorjs.NewTextEngine()
+	require.NotNil(t, eng.BlockHandlers)
+	require.NotNil(t, eng.TuneHandlers)
+}
+
+func Test_GenerateText_Returns_Parse_Err(t *testing.T) {
+	eng := goeditorjs.NewTextEngine()
+	_, err := eng.GenerateText(``)
+	require.Error(t, err)
+}
+
+func Test_GenerateText_NoHandler_Should_Err(t *testing.T) {
+	eng := goeditorjs.NewTextEngine()
+	_, err := eng.GenerateText(`{"blocks": [{"type": "header","data": {"text": "Heading","level": 1}}]}`)
+	require.True(t, errors.Is(err, goeditorjs.ErrBlockHandlerNotFound))
+}
+
+func Test_GenerateText(t *testing.T) {
+	editorJSData := `{"blocks": [
+		{"type": "header","data": {"text": "The <b>Title</b>","level": 1}},
+		{"type": "paragraph","data": {"text": "Fish &amp; chips&nbsp;<i>today</i><br>Second   line","alignment": "left"}},
+		{"type": "list","data": {"style": "ordered","items": [{"content": "One","items": [{"content": "Nested","items": []}]},{"content": "Two","items": []}]}},
+		{"type": "list","data": {"style": "unordered","items": ["Apple","Pear"]}},
+		{"type": "checklist","data": {"items": [{"text": "Done","checked": true},{"text": "Todo","checked": false}]}},
+		{"type": "table","data": {"content": [["Name","Age"],["Bob","<b>42</b>"]]}},
+		{"type": "code","data": {"code": "if a < b {\n\treturn\n}"}},
+		{"type": "codeBox","data": {"language": "go","code": "<span>x</span> := 1<div>y &lt; x</div>"}},
+		{"type": "raw","data": {"html": "<div><p>First</p><p>Second</p><script>alert(1)</script></div>"}},
+		{"type": "quote","data": {"text": "Quote","caption": "Author"}},
+		{"type": "warning","data": {"title": "Careful","message": "Hot"}},
+		{"type": "image","data": {"file": {"url": "https://example.com/a.png"},"caption": ""}},
+		{"type": "delimiter","data": {}},
+		{"type": "linkTool","data": {"link": "https://example.com","meta": {"title": "Example","description": "An example"}}},
+		{"type": "attaches","data": {"file": {"url": "https://example.com/a.pdf","size": 2048},"title": "Report"}},
+		{"type": "embed","data": {"service": "youtube","source": "https://youtu.be/x","embed": "https://www.youtube.com/embed/x","caption": "Video"}}
+	]}`
+
+	expectedResult := strings.Join([]string{
+		"The Title",
+		"Fish & chips today\nSecond line",
+		"1. One\n   1. Nested\n2. Two",
+		"- Apple\n- Pear",
+		"[x] Done\n[ ] Todo",
+		"Name\tAge\nBob\t42",
+		"if a < b {\n\treturn\n}",
+		"x := 1\ny < x",
+		"First\nSecond",
+		"Quote\n— Author",
+		"Careful\nHot",
+		"***",
+		"Example\nAn example\nhttps://example.com",
+		"Report (2 KB)",
+		"Video\nhttps://youtu.be/x",
+	}, "\n\n")
+
+	result, err := newTestTextEngine().GenerateText(editorJSData)
+	require.NoError(t, err)
+	require.Equal(t, expectedResult, result)
+}
+
+func Test_GenerateText_LineWidth(t *testing.T) {
+	editorJSData := `{"blocks": [
+		{"type": "paragraph","data": {"text": "The quick brown fox jumps over the lazy dog","alignment": "left"}},
+		{"type": "list","data": {"style": "unordered","items": ["The quick brown fox jumps"]}},
+		{"type": "code","data": {"code": "fmt.Println(\"The quick brown fox jumps over the lazy dog\")"}},
+		{"type": "paragraph","data": {"text": "Supercalifragilisticexpialidocious word","alignment": "left"}}
+	]}`
+
+	eng := newTestTextEngine()
+	eng.LineWidth = 16
+	result, err := eng.GenerateText(editorJSData)
+	require.NoError(t, err)
+	require.Equal(t, "The quick brown\nfox jumps over\nthe lazy dog\n\n"+
+		"- The quick\n  brown fox\n  jumps\n\n"+
+		"fmt.Println(\"The quick brown fox jumps over the lazy dog\")\n\n"+
+		"Supercalifragilisticexpialidocious\nword", result)
+
+	eng.PreformattedTypes = []string{}
+	result, err = eng.GenerateText(`{"blocks": [{"type": "code","data": {"code": "one two three four five"}}]}`)
+	require.NoError(t, err)
+	require.Equal(t, "one two three\nfour five", result)
+}
+
+func Test_GenerateText_UnknownBlockPolicy(t *testing.T) {
+	editorJSData := `{"blocks": [{"type": "unknown","data": {}},{"type": "paragraph","data": {"text": "Text","alignment": "left"}}]}`
+	eng := newTestTextEngine()
+
+	eng.UnknownBlockPolicy = goeditorjs.UnknownBlockSkip
+	result, err := eng.GenerateText(editorJSData)
+	require.NoError(t, err)
+	require.Equal(t, "Text", result)
+
+	eng.UnknownBlockPolicy = goeditorjs.UnknownBlockPlaceholder
+	result, err = eng.GenerateText(editorJSData)
+	require.NoError(t, err)
+	require.Equal(t, "[goeditorjs: no handler for block type \"unknown\"]\n\nText", result)
+}
+
+func Test_GenerateText_HiddenTune(t *testing.T) {
+	editorJSData := `{"blocks": [{"type": "paragraph","data": {"text": "Hidden","alignment": "left"},"tunes": {"hidden": true}},{"type": "paragraph","data": {"text": "Shown","alignment": "left"}}]}`
+	eng := newTestTextEngine()
+	eng.RegisterTuneHandlers(&goeditorjs.HiddenTuneHandler{})
+
+	result, err := eng.GenerateText(editorJSData)
+	require.NoError(t, err)
+	require.Equal(t, "Shown", result)
+}
+
+func Test_GenerateText_ContinueOnError(t *testing.T) {
+	editorJSData := `{"blocks": [{"type": "paragraph","data": []},{"type": "paragraph","data": {"text": "Text","alignment": "left"}}]}`
+	eng := newTestTextEngine()
+	eng.ContinueOnError = true
+
+	result, err := eng.GenerateText(editorJSData)
+	var blockErrs goeditorjs.BlockErrors
+	require.True(t, errors.As(err, &blockErrs))
+	require.Len(t, blockErrs, 1)
+	require.Equal(t, 0, blockErrs[0].Index)
+	require.Equal(t, "Text", result)
+}
+
+func Test_RenderText(t *testing.T) {
+	editorJSData := `{"blocks": [{"type": "header","data": {"text": "Title","level": 1}},{"type": "paragraph","data": {"text": "Text","alignment": "left"}}]}`
+	w := &bytes.Buffer{}
+	err := newTestTextEngine().RenderText(w, strings.NewReader(editorJSData))
+	require.NoError(t, err)
+	require.Equal(t, "Title\n\nText", w.String())
+
+	err = newTestTextEngine().RenderText(w, strings.NewReader(``))
+	require.Error(t, err)
+}
+
+func Test_GenerateTextContext_Stops_When_Cancelled(t *testing.T) {
+	editorJSData := `{"blocks": [{"type": "paragraph","data": {"text": "Text","alignment": "left"}}]}`
+	document, err := goeditorjs.ParseDocument(editorJSData)
+	require.NoError(t, err)
+	eng := goeditorjs.NewDefaultTextEngine()
+
+	result, err := eng.GenerateTextFromDocumentContext(context.Background(), document)
+	require.NoError(t, err)
+	require.Equal(t, "Text", result)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	result, err = eng.GenerateTextContext(ctx, editorJSData)
+	require.Equal(t, context.Canceled, err)
+	require.Equal(t, "", result)
+
+	result, err = eng.GenerateTextFromDocumentContext(ctx, document)
+	require.Equal(t, context.Canceled, err)
+	require.Equal(t, "", result)
+
+	w := &bytes.Buffer{}
+	require.Equal(t, context.Canceled, eng.RenderTextContext(ctx, w, strings.NewReader(editorJSData)))
+	require.Equal(t, context.Canceled, eng.RenderTextFromDocumentContext(ctx, w, document))
+	require.Equal(t, "", w.String())
+	require.Error(t, eng.RenderTextContext(ctx, w, strings.NewReader(``)))
+}
@@ -0,0 +1,215 @@
+package goeditorjs
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// TuneHandler is the interface shared by HTMLTuneHandler and MarkdownTuneHandler
+type TuneHandler interface {
+	Type() string // Type returns the name of the tune the tune handler supports as a string
+}
+
+// tuneNames returns the names of the tunes of a block in a stable order
+func tuneNames(editorJSBlock EditorJSBlock) []string {
+	names := make([]string, 0, len(editorJSBlock.Tunes))
+	for name := range editorJSBlock.Tunes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// textAlignments are the text alignments that are put in the output, other values are dropped
+var textAlignments = map[string]bool{"left": true, "center": true, "right": true, "justify": true}
+
+// isTextAlignment reports whether the alignment is one of left, center, right and justify
+func isTextAlignment(alignment string) bool {
+	return textAlignments[alignment]
+}
+
+// AlignmentTuneHandler is the default tune handler for the editor.js text alignment tune.
+// Alignments other than left, center, right and justify are dropped.
+type AlignmentTuneHandler struct {
+	// Name is the name the tune is registered under in editor.js. Defaults to "alignmentTune" if empty.
+	Name string
+}
+
+func (*AlignmentTuneHandler) parse(tuneData json.RawMessage) (*alignmentTune, error) {
+	alignmentTune := &alignmentTune{}
+	return alignmentTune, json.Unmarshal(tuneData, alignmentTune)
+}
+
+// Type "alignmentTune", unless configured otherwise
+func (h *AlignmentTuneHandler) Type() string {
+	if h.Name == "" {
+		return "alignmentTune"
+	}
+	return h.Name
+}
+
+// TuneHTML adds the text alignment to the html of the block
+func (h *AlignmentTuneHandler) TuneHTML(tuneData json.RawMessage, editorJSBlock EditorJSBlock, html string) (string, error) {
+	alignmentTune, err := h.parse(tuneData)
+	if err != nil {
+		return "", err
+	}
+
+	if !isTextAlignment(alignmentTune.Alignment) || alignmentTune.Alignment == "left" {
+		return html, nil
+	}
+
+	return setFirstTagAttribute(html, "style", "text-align:"+alignmentTune.Alignment), nil
+}
+
+// TuneMarkdown wraps the markdown of the block in an aligned div
+func (h *AlignmentTuneHandler) TuneMarkdown(tuneData json.RawMessage, editorJSBlock EditorJSBlock, markdown string) (string, error) {
+	alignmentTune, err := h.parse(tuneData)
+	if err != nil {
+		return "", err
+	}
+
+	if !isTextAlignment(alignmentTune.Alignment) || alignmentTune.Alignment == "left" {
+		return markdown, nil
+	}
+
+	// Native markdown doesn't support alignment, so we'll wrap it in html.
+	// The blank lines make markdown parsers keep parsing the content as markdown.
+	return fmt.Sprintf("<div style=\"text-align:%s\">\n\n%s\n\n</div>", alignmentTune.Alignment, markdown), nil
+}
+
+// AnchorTuneHandler is the default tune handler for the editor.js anchor tune
+type AnchorTuneHandler struct {
+	// Name is the name the tune is registered under in editor.js. Defaults to "anchorTune" if empty.
+	Name string
+}
+
+func (*AnchorTuneHandler) parse(tuneData json.RawMessage) (*anchorTune, error) {
+	anchorTune := &anchorTune{}
+	return anchorTune, json.Unmarshal(tuneData, anchorTune)
+}
+
+// Type "anchorTune", unless configured otherwise
+func (h *AnchorTuneHandler) Type() string {
+	if h.Name == "" {
+		return "anchorTune"
+	}
+	return h.Name
+}
+
+// TuneHTML adds the anchor as the id of the html of the block
+func (h *AnchorTuneHandler) TuneHTML(tuneData json.RawMessage, editorJSBlock EditorJSBlock, html string) (string, error) {
+	anchorTune, err := h.parse(tuneData)
+	if err != nil {
+		return "", err
+	}
+
+	if anchorTune.Anchor == "" {
+		return html, nil
+	}
+
+	return setFirstTagAttribute(html, "id", anchorTune.Anchor), nil
+}
+
+// TuneMarkdown puts an html anchor before the markdown of the block
+func (h *AnchorTuneHandler) TuneMarkdown(tuneData json.RawMessage, editorJSBlock EditorJSBlock, markdown string) (string, error) {
+	anchorTune, err := h.parse(tuneData)
+	if err != nil {
+		return "", err
+	}
+
+	if anchorTune.Anchor == "" {
+		return markdown, nil
+	}
+
+	return fmt.Sprintf("<a id=\"%s\"></a>\n\n%s", html.EscapeString(anchorTune.Anchor), markdown), nil
+}
+
+// HiddenTuneHandler suppresses blocks that have a truthy hidden tune, either `true` or `{"hidden": true}`
+type HiddenTuneHandler struct {
+	// Name is the name the tune is registered under in editor.js. Defaults to "hidden" if empty.
+	Name string
+}
+
+// Type "hidden", unless configured otherwise
+func (h *HiddenTuneHandler) Type() string {
+	if h.Name == "" {
+		return "hidden"
+	}
+	return h.Name
+}
+
+// TuneHTML suppresses the block if it's hidden
+func (h *HiddenTuneHandler) TuneHTML(tuneData json.RawMessage, editorJSBlock EditorJSBlock, html string) (string, error) {
+	return h.tune(tuneData, html)
+}
+
+// TuneMarkdown suppresses the block if it's hidden
+func (h *HiddenTuneHandler) TuneMarkdown(tuneData json.RawMessage, editorJSBlock EditorJSBlock, markdown string) (string, error) {
+	return h.tune(tuneData, markdown)
+}
+
+// TuneText suppresses the block if it's hidden
+func (h *HiddenTuneHandler) TuneText(tuneData json.RawMessage, editorJSBlock EditorJSBlock, text string) (string, error) {
+	return h.tune(tuneData, text)
+}
+
+// TuneAsciiDoc suppresses the block if it's hidden
+func (h *HiddenTuneHandler) TuneAsciiDoc(tuneData json.RawMessage, editorJSBlock EditorJSBlock, asciiDoc string) (string, error) {
+	return h.tune(tuneData, asciiDoc)
+}
+
+// TuneRST suppresses the block if it's hidden
+func (h *HiddenTuneHandler) TuneRST(tuneData json.RawMessage, editorJSBlock EditorJSBlock, rst string) (string, error) {
+	return h.tune(tuneData, rst)
+}
+
+func (h *HiddenTuneHandler) tune(tuneData json.RawMessage, output string) (string, error) {
+	hidden := false
+	if err := json.Unmarshal(tuneData, &hidden); err != nil {
+		hiddenTune := &hiddenTune{}
+		if err := json.Unmarshal(tuneData, hiddenTune); err != nil {
+			return "", err
+		}
+		hidden = hiddenTune.Hidden
+	}
+
+	if hidden {
+		return "", ErrSkipBlock
+	}
+	return output, nil
+}
+
+// firstTag matches the first opening tag of an html string
+var firstTag = regexp.MustCompile(`^(\s*<[a-zA-Z][a-zA-Z0-9-]*)([^>]*?)(\s*/?>)`)
+
+// quotedAttribute matches a double quoted attribute of an html tag
+var quotedAttribute = regexp.MustCompile(`\s([^\s"'>/=]+)="([^"]*)"`)
+
+// setFirstTagAttribute sets an attribute on the first element of the html.
+// Styles are merged with an existing style attribute, other attributes are replaced.
+// If the html doesn't start with an element it's wrapped in a div.
+func setFirstTagAttribute(htmlData, name, value string) string {
+	match := firstTag.FindStringSubmatchIndex(htmlData)
+	if match == nil {
+		return fmt.Sprintf(`<div %s="%s">%s</div>`, name, html.EscapeString(value), htmlData)
+	}
+
+	tagName, attributes, tagEnd, rest := htmlData[match[2]:match[3]], htmlData[match[4]:match[5]], htmlData[match[6]:match[7]], htmlData[match[1]:]
+	for _, m := range quotedAttribute.FindAllStringSubmatchIndex(attributes, -1) {
+		if !strings.EqualFold(attributes[m[2]:m[3]], name) {
+			continue
+		}
+		if name == "style" {
+			value = strings.TrimSuffix(html.UnescapeString(attributes[m[4]:m[5]]), ";") + ";" + value
+		}
+		attributes = attributes[:m[0]] + attributes[m[1]:]
+		break
+	}
+
+	return fmt.Sprintf(`%s%s %s="%s"%s%s`, tagName, attributes, name, html.EscapeString(value), tagEnd, rest)
+}
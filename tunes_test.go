@@ -0,0 +1,157 @@
+package goeditorjs_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/davidscottmills/goeditorjs"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_AlignmentTuneHandler_Type(t *testing.T) {
+	require.Equal(t, "alignmentTune", (&goeditorjs.AlignmentTuneHandler{}).Type())
+	require.Equal(t, "textAlign", (&goeditorjs.AlignmentTuneHandler{Name: "textAlign"}).Type())
+}
+
+func Test_AlignmentTuneHandler_TuneHTML_Returns_Parse_Err(t *testing.T) {
+	h := &goeditorjs.AlignmentTuneHandler{}
+	_, err := h.TuneHTML(json.RawMessage{}, goeditorjs.EditorJSBlock{}, "<p>Text</p>")
+	require.Error(t, err)
+}
+
+func Test_AlignmentTuneHandler_TuneHTML(t *testing.T) {
+	h := &goeditorjs.AlignmentTuneHandler{}
+	testData := []struct {
+		data           string
+		html           string
+		expectedResult string
+	}{
+		{data: `{"alignment": "left"}`, html: "<p>Text</p>", expectedResult: "<p>Text</p>"},
+		{data: `{"alignment": "center"}`, html: "<p>Text</p>", expectedResult: `<p style="text-align:center">Text</p>`},
+		{data: `{"alignment": "right"}`, html: `<p class="a" style="color:red">Text</p>`, expectedResult: `<p class="a" style="color:red;text-align:right">Text</p>`},
+		{data: `{"alignment": "center"}`, html: `<p style="font-family:&quot;A B&quot;">Text</p>`, expectedResult: `<p style="font-family:&#34;A B&#34;;text-align:center">Text</p>`},
+		{data: `{"alignment": "center"}`, html: `<p data-style="x">Text</p>`, expectedResult: `<p data-style="x" style="text-align:center">Text</p>`},
+		{data: `{"alignment": "center"}`, html: "<hr/>", expectedResult: `<hr style="text-align:center"/>`},
+		{data: `{"alignment": "center"}`, html: "Text", expectedResult: `<div style="text-align:center">Text</div>`},
+		{data: `{"alignment": "justify"}`, html: "<p>Text</p>", expectedResult: `<p style="text-align:justify">Text</p>`},
+		{data: `{"alignment": "center;position:fixed;inset:0"}`, html: "<p>Text</p>", expectedResult: "<p>Text</p>"},
+	}
+
+	for _, td := range testData {
+		result, err := h.TuneHTML(json.RawMessage(td.data), goeditorjs.EditorJSBlock{}, td.html)
+		require.NoError(t, err)
+		require.Equal(t, td.expectedResult, result)
+	}
+}
+
+func Test_AlignmentTuneHandler_TuneMarkdown(t *testing.T) {
+	h := &goeditorjs.AlignmentTuneHandler{}
+	result, err := h.TuneMarkdown(json.RawMessage(`{"alignment": "left"}`), goeditorjs.EditorJSBlock{}, "Text")
+	require.NoError(t, err)
+	require.Equal(t, "Text", result)
+
+	result, err = h.TuneMarkdown(json.RawMessage(`{"alignment": "center"}`), goeditorjs.EditorJSBlock{}, "**Text**")
+	require.NoError(t, err)
+	require.Equal(t, "<div style=\"text-align:center\">\n\n**Text**\n\n</div>", result)
+
+	result, err = h.TuneMarkdown(json.RawMessage(`{"alignment": "center\"><script>alert(1)</script>"}`), goeditorjs.EditorJSBlock{}, "Text")
+	require.NoError(t, err)
+	require.Equal(t, "Text", result)
+}
+
+func Test_AnchorTuneHandler_Type(t *testing.T) {
+	require.Equal(t, "anchorTune", (&goeditorjs.AnchorTuneHandler{}).Type())
+}
+
+func Test_AnchorTuneHandler_TuneHTML(t *testing.T) {
+	h := &goeditorjs.AnchorTuneHandler{}
+	testData := []struct {
+		data           string
+		html           string
+		expectedResult string
+	}{
+		{data: `{"anchor": ""}`, html: "<h1>Title</h1>", expectedResult: "<h1>Title</h1>"},
+		{data: `{"anchor": "intro"}`, html: "<h1>Title</h1>", expectedResult: `<h1 id="intro">Title</h1>`},
+		{data: `{"anchor": "intro"}`, html: `<h1 id="old">Title</h1>`, expectedResult: `<h1 id="intro">Title</h1>`},
+	}
+
+	for _, td := range testData {
+		result, err := h.TuneHTML(json.RawMessage(td.data), goeditorjs.EditorJSBlock{}, td.html)
+		require.NoError(t, err)
+		require.Equal(t, td.expectedResult, result)
+	}
+}
+
+func Test_AnchorTuneHandler_TuneMarkdown(t *testing.T) {
+	h := &goeditorjs.AnchorTuneHandler{}
+	result, err := h.TuneMarkdown(json.RawMessage(`{"anchor": "intro"}`), goeditorjs.EditorJSBlock{}, "# Title")
+	require.NoError(t, err)
+	require.Equal(t, "<a id=\"intro\"></a>\n\n# Title", result)
+}
+
+func Test_HiddenTuneHandler(t *testing.T) {
+	h := &goeditorjs.HiddenTuneHandler{}
+	require.Equal(t, "hidden", h.Type())
+
+	testData := []struct {
+		data   string
+		hidden bool
+	}{
+		{data: `true`, hidden: true},
+		{data: `false`, hidden: false},
+		{data: `{"hidden": true}`, hidden: true},
+		{data: `{"hidden": false}`, hidden: false},
+	}
+
+	for _, td := range testData {
+		html, err := h.TuneHTML(json.RawMessage(td.data), goeditorjs.EditorJSBlock{}, "<p>Text</p>")
+		md, mdErr := h.TuneMarkdown(json.RawMessage(td.data), goeditorjs.EditorJSBlock{}, "Text")
+		if td.hidden {
+			require.True(t, errors.Is(err, goeditorjs.ErrSkipBlock))
+			require.True(t, errors.Is(mdErr, goeditorjs.ErrSkipBlock))
+		} else {
+			require.NoError(t, err)
+			require.NoError(t, mdErr)
+			require.Equal(t, "<p>Text</p>", html)
+			require.Equal(t, "Text", md)
+		}
+	}
+}
+
+func Test_Engines_Apply_Tunes(t *testing.T) {
+	editorJSData := `{"blocks": [` +
+		`{"type": "header", "data": {"text": "Title", "level": 1}, "tunes": {"anchorTune": {"anchor": "title"}, "alignmentTune": {"alignment": "center"}}},` +
+		`{"type": "paragraph", "data": {"text": "Secret", "alignment": "left"}, "tunes": {"hidden": true}},` +
+		`{"type": "paragraph", "data": {"text": "Text", "alignment": "left"}, "tunes": {"unknownTune": {}}}]}`
+
+	htmlEngine := goeditorjs.NewHTMLEngine()
+	htmlEngine.RegisterBlockHandlers(&goeditorjs.HeaderHandler{}, &goeditorjs.ParagraphHandler{})
+	htmlEngine.RegisterTuneHandlers(&goeditorjs.AlignmentTuneHandler{}, &goeditorjs.AnchorTuneHandler{}, &goeditorjs.HiddenTuneHandler{})
+	html, err := htmlEngine.GenerateHTML(editorJSData)
+	require.NoError(t, err)
+	require.Equal(t, `<h1 style="text-align:center" id="title">Title</h1><p>Text</p>`, html)
+
+	markdownEngine := goeditorjs.NewMarkdownEngine()
+	markdownEngine.RegisterBlockHandlers(&goeditorjs.HeaderHandler{}, &goeditorjs.ParagraphHandler{})
+	markdownEngine.RegisterTuneHandlers(&goeditorjs.AlignmentTuneHandler{}, &goeditorjs.AnchorTuneHandler{}, &goeditorjs.HiddenTuneHandler{})
+	md, err := markdownEngine.GenerateMarkdown(editorJSData)
+	require.NoError(t, err)
+	require.Equal(t, "<a id=\"title\"></a>\n\n<div style=\"text-align:center\">\n\n# Title\n\n</div>\n\nText", md)
+}
+
+func Test_Engines_Return_Tune_Err(t *testing.T) {
+	editorJSData := `{"blocks": [{"type": "paragraph", "data": {"text": "Text", "alignment": "left"}, "tunes": {"alignmentTune": []}}]}`
+
+	htmlEngine := goeditorjs.NewHTMLEngine()
+	htmlEngine.RegisterBlockHandlers(&goeditorjs.ParagraphHandler{})
+	htmlEngine.RegisterTuneHandlers(&goeditorjs.AlignmentTuneHandler{})
+	_, err := htmlEngine.GenerateHTML(editorJSData)
+	require.Error(t, err)
+
+	markdownEngine := goeditorjs.NewMarkdownEngine()
+	markdownEngine.RegisterBlockHandlers(&goeditorjs.ParagraphHandler{})
+	markdownEngine.RegisterTuneHandlers(&goeditorjs.AlignmentTuneHandler{})
+	_, err = markdownEngine.GenerateMarkdown(editorJSData)
+	require.Error(t, err)
+}
@@ -3,24 +3,42 @@ package goeditorjs
 import (
 	"encoding/json"
 	"errors"
+	"net/url"
+	"path"
+	"strings"
 )
 
-// editorJS rpresents the Editor JS data
-type editorJS struct {
-	Blocks []EditorJSBlock `json:"blocks"`
+// Document represents the Editor JS data
+type Document struct {
+	// Time is the time the document was saved in milliseconds since the Unix epoch
+	Time int64 `json:"time,omitempty"`
+	// Version is the version of editor.js that saved the document
+	Version string          `json:"version,omitempty"`
+	Blocks  []EditorJSBlock `json:"blocks"`
 }
 
 // EditorJSBlock type
 type EditorJSBlock struct {
+	// ID is the id of the block, provided by editor.js 2.22 and later
+	ID   string `json:"id,omitempty"`
 	Type string `json:"type"`
 	// Data is the Data for an editorJS block in the form of RawMessage ([]byte). It is left up to the Handler to parse the Data field
 	Data json.RawMessage `json:"data"`
+	// Tunes is the data of the block tunes applied to the block, keyed by the name of the tune
+	Tunes map[string]json.RawMessage `json:"tunes,omitempty"`
 }
 
 var (
 	//ErrBlockHandlerNotFound is returned from GenerateHTML when the HTML engine doesn't have a registered handler
 	//for that type and the HTMLEngine is set to return on errors.
 	ErrBlockHandlerNotFound = errors.New("Handler not found for block type")
+
+	//ErrSkipBlock is returned from a tune handler to suppress the output of the block it's applied to.
+	ErrSkipBlock = errors.New("Skip block")
+
+	//ErrEmbedNotAllowed is returned from the EmbedHandler when the service of an embed is unknown or not allowed
+	//and the handler is set to reject them.
+	ErrEmbedNotAllowed = errors.New("Embed service not allowed")
 )
 
 // header represents header data from EditorJS
@@ -37,8 +55,36 @@ type paragraph struct {
 
 // list represents list data from EditorJS
 type list struct {
-	Style string   `json:"style"`
-	Items []string `json:"items"`
+	Style string     `json:"style"`
+	Meta  listMeta   `json:"meta"`
+	Items []listItem `json:"items"`
+}
+
+// listItem represents a list item from EditorJS. Items are plain strings in the legacy list tool
+// and objects with nested items in the NestedList / List v2 tools.
+type listItem struct {
+	Content string     `json:"content"`
+	Meta    listMeta   `json:"meta"`
+	Items   []listItem `json:"items"`
+}
+
+// UnmarshalJSON unmarshals either a legacy string item or an item object
+func (li *listItem) UnmarshalJSON(data []byte) error {
+	content := ""
+	if err := json.Unmarshal(data, &content); err == nil {
+		*li = listItem{Content: content}
+		return nil
+	}
+
+	type item listItem
+	return json.Unmarshal(data, (*item)(li))
+}
+
+// listMeta represents list and list item meta data from EditorJS
+type listMeta struct {
+	Start       int    `json:"start"`
+	CounterType string `json:"counterType"`
+	Checked     bool   `json:"checked"`
 }
 
 // codeBox represents code box data from EditorJS
@@ -62,5 +108,116 @@ type image struct {
 }
 
 type file struct {
-	URL string `json:"url"`
+	URL       string  `json:"url"`
+	Name      string  `json:"name"`
+	Size      float64 `json:"size"`
+	Extension string  `json:"extension"`
+}
+
+// table represents table data from EditorJS
+type table struct {
+	WithHeadings bool       `json:"withHeadings"`
+	Content      [][]string `json:"content"`
+}
+
+// checklist represents checklist data from EditorJS
+type checklist struct {
+	Items []checklistItem `json:"items"`
+}
+
+type checklistItem struct {
+	Text    string `json:"text"`
+	Checked bool   `json:"checked"`
+}
+
+// quote represents quote data from EditorJS
+type quote struct {
+	Text      string `json:"text"`
+	Caption   string `json:"caption"`
+	Alignment string `json:"alignment"`
+}
+
+// warning represents warning data from EditorJS
+type warning struct {
+	Title   string `json:"title"`
+	Message string `json:"message"`
+}
+
+// linkTool represents link tool data from EditorJS
+type linkTool struct {
+	Link string       `json:"link"`
+	Meta linkToolMeta `json:"meta"`
+}
+
+type linkToolMeta struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Image       file   `json:"image"`
+	SiteName    string `json:"site_name"`
+}
+
+// title returns the title of the link, falling back to the link itself
+func (lt *linkTool) title() string {
+	if lt.Meta.Title != "" {
+		return lt.Meta.Title
+	}
+	return lt.Link
+}
+
+// domain returns the site name of the link, falling back to the host of the link
+func (lt *linkTool) domain() string {
+	if lt.Meta.SiteName != "" {
+		return lt.Meta.SiteName
+	}
+	u, err := url.Parse(lt.Link)
+	if err != nil {
+		return ""
+	}
+	return u.Hostname()
+}
+
+// attaches represents attaches data from EditorJS
+type attaches struct {
+	File  file   `json:"file"`
+	Title string `json:"title"`
+}
+
+// title returns the title of the attachment, falling back to the file name and then the url
+func (a *attaches) title() string {
+	if a.Title != "" {
+		return a.Title
+	}
+	if a.File.Name != "" {
+		return a.File.Name
+	}
+	return a.File.URL
+}
+
+// extension returns the extension of the attached file, falling back to the extension of the file name
+func (a *attaches) extension() string {
+	if a.File.Extension != "" {
+		return a.File.Extension
+	}
+	return strings.TrimPrefix(path.Ext(a.File.Name), ".")
+}
+
+// code represents code data from EditorJS
+type code struct {
+	Code     string
+	Language string
+}
+
+// alignmentTune represents text alignment tune data from EditorJS
+type alignmentTune struct {
+	Alignment string `json:"alignment"`
+}
+
+// anchorTune represents anchor tune data from EditorJS
+type anchorTune struct {
+	Anchor string `json:"anchor"`
+}
+
+// hiddenTune represents hidden tune data
+type hiddenTune struct {
+	Hidden bool `json:"hidden"`
 }
@@ -2,9 +2,9 @@ package goeditorjs
 
 import "encoding/json"
 
-// parseEditorJSON parses editorJS data
-func parseEditorJSON(editorJSData string) (*editorJS, error) {
-	result := &editorJS{}
+// ParseDocument parses editorJS data
+func ParseDocument(editorJSData string) (*Document, error) {
+	result := &Document{}
 	err := json.Unmarshal([]byte(editorJSData), result)
 	if err != nil {
 		return nil, err
@@ -1,21 +1,37 @@
 package goeditorjs
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 )
 
-func Test_parseEditorJSON(t *testing.T) {
+func Test_ParseDocument(t *testing.T) {
 	editorJSData := `{"time": 1607709186831,"blocks": [{"type": "header","data": {"text": "Heading 1","level": 1}}],"version": "2.19.1"}`
-	editorJS, err := parseEditorJSON(editorJSData)
+	document, err := ParseDocument(editorJSData)
 	require.NoError(t, err)
-	require.Len(t, editorJS.Blocks, 1)
+	require.Equal(t, int64(1607709186831), document.Time)
+	require.Equal(t, "2.19.1", document.Version)
+	require.Len(t, document.Blocks, 1)
 }
 
-func Test_parseEditorJSON_Err_Empty(t *testing.T) {
+func Test_ParseDocument_Block_ID_And_Tunes(t *testing.T) {
+	editorJSData := `{"time": 1607709186831,"blocks": [{"id": "oUq2g_tl8y","type": "paragraph","data": {"text": "Text"},"tunes": {"anchorTune": {"anchor": "intro"}}}],"version": "2.22.2"}`
+	document, err := ParseDocument(editorJSData)
+	require.NoError(t, err)
+	require.Len(t, document.Blocks, 1)
+	require.Equal(t, "oUq2g_tl8y", document.Blocks[0].ID)
+	require.JSONEq(t, `{"anchor": "intro"}`, string(document.Blocks[0].Tunes["anchorTune"]))
+
+	marshalled, err := json.Marshal(document)
+	require.NoError(t, err)
+	require.JSONEq(t, editorJSData, string(marshalled))
+}
+
+func Test_ParseDocument_Err_Empty(t *testing.T) {
 	editorJSData := ``
 
-	_, err := parseEditorJSON(editorJSData)
+	_, err := ParseDocument(editorJSData)
 	require.Error(t, err)
 }